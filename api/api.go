@@ -0,0 +1,27 @@
+// Package api embeds the OpenAPI 3 document of the REST API. The document is the contract of the account
+// and transfer routes: requests are validated against it, and a test fails when routes and document drift.
+package api
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+//go:embed openapi.yaml
+var document []byte
+
+// Load parses and validates the OpenAPI document
+func Load() (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+	spec, err := loader.LoadFromData(document)
+	if err != nil {
+		return nil, fmt.Errorf("parsing OpenAPI document: %w", err)
+	}
+	if err := spec.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+	return spec, nil
+}
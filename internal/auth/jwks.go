@@ -0,0 +1,195 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// jwksMinRefetch bounds how often an unknown key id makes the key set fetch the keys at its URL again
+const jwksMinRefetch = time.Minute
+
+// jwksFetchTimeout bounds a fetch of the keys at a JWKS URL
+const jwksFetchTimeout = 10 * time.Second
+
+// jwk is an entry of a JSON Web Key Set; only RSA keys are used
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// keySet holds the RSA keys of a JWKS read from a file, or fetched from a URL and refreshed every refresh
+type keySet struct {
+	load    func(ctx context.Context) ([]byte, error)
+	refresh time.Duration
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+	// attemptedAt is when the keys were last fetched, whether that succeeded or not
+	attemptedAt time.Time
+	// fetching is closed when the fetch in flight, if any, ends
+	fetching chan struct{}
+}
+
+// newFileKeySet reads the keys of the JWKS at path once
+func newFileKeySet(path string) (*keySet, error) {
+	set := &keySet{load: func(context.Context) ([]byte, error) { return os.ReadFile(path) }}
+	if err := set.fetch(context.Background()); err != nil {
+		return nil, fmt.Errorf("reading JWKS %s: %w", path, err)
+	}
+	return set, nil
+}
+
+// newURLKeySet fetches the keys of the JWKS at url, and again every refresh or when a token names a key
+// it does not hold
+func newURLKeySet(url string, refresh time.Duration) (*keySet, error) {
+	httpClient := &http.Client{Timeout: jwksFetchTimeout}
+	set := &keySet{refresh: refresh, load: func(ctx context.Context) ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status %s", resp.Status)
+		}
+		return io.ReadAll(resp.Body)
+	}}
+	if err := set.fetch(context.Background()); err != nil {
+		return nil, fmt.Errorf("fetching JWKS %s: %w", url, err)
+	}
+	return set, nil
+}
+
+// key returns the key named kid. An empty kid names the only key of a set holding one.
+//
+// Stale keys are refreshed in the background while the keys held are served. A kid the set does not hold
+// waits for the keys to be fetched again, or for ctx to be done; one fetch runs at a time, and it is not
+// cancelled with the request that started it.
+func (s *keySet) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	s.mu.Lock()
+	_, known := s.keys[kid]
+	var fetched chan struct{}
+	if s.refresh > 0 && (!known || time.Since(s.fetchedAt) > s.refresh) {
+		if s.fetching == nil && time.Since(s.attemptedAt) > jwksMinRefetch {
+			s.attemptedAt = time.Now()
+			s.fetching = make(chan struct{})
+			go s.refetch(context.WithoutCancel(ctx), s.fetching)
+		}
+		if !known {
+			fetched = s.fetching
+		}
+	}
+	s.mu.Unlock()
+
+	if fetched != nil {
+		select {
+		case <-fetched:
+		case <-ctx.Done():
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, nil
+		}
+	}
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key %q", kid)
+}
+
+// refetch fetches the keys again and closes done. The keys already held are kept when the issuer cannot
+// be reached.
+func (s *keySet) refetch(ctx context.Context, done chan struct{}) {
+	keys, err := s.loadKeys(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		s.keys = keys
+		s.fetchedAt = time.Now()
+	}
+	s.fetching = nil
+	close(done)
+}
+
+// fetch replaces the keys with those loaded now
+func (s *keySet) fetch(ctx context.Context) error {
+	s.mu.Lock()
+	s.attemptedAt = time.Now()
+	s.mu.Unlock()
+
+	keys, err := s.loadKeys(ctx)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+	s.fetchedAt = time.Now()
+	return nil
+}
+
+// loadKeys loads and parses the keys of the JWKS
+func (s *keySet) loadKeys(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	raw, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return parseJWKS(raw)
+}
+
+// parseJWKS returns the RSA signing keys of a JWKS by key id
+func parseJWKS(raw []byte) (map[string]*rsa.PublicKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, key := range set.Keys {
+		if key.Kty != "RSA" || key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(key.N)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: modulus: %w", key.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(key.E)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: exponent: %w", key.Kid, err)
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("key %q: exponent too large", key.Kid)
+		}
+		keys[key.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no RSA signing keys")
+	}
+	return keys, nil
+}
@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"internal-transfer-microservice/internal/config"
+)
+
+var (
+	// ErrInvalidToken is returned for bearer tokens that are missing, malformed, expired or not signed by a trusted key
+	ErrInvalidToken = errors.New("invalid bearer token")
+	// ErrInsufficientScope is returned for valid tokens that do not grant the scopes the service requires
+	ErrInsufficientScope = errors.New("bearer token does not grant the required scopes")
+)
+
+// Verifier checks bearer tokens and returns the principal they were issued to
+type Verifier interface {
+	Verify(ctx context.Context, token string) (Principal, error)
+}
+
+// JWTVerifier verifies JWTs signed with HS256 by a shared secret, or with RS256 by a key of a JWKS
+type JWTVerifier struct {
+	parser         *jwt.Parser
+	hmacSecret     []byte
+	keys           *keySet
+	requiredScopes []string
+}
+
+// claims are the claims of a token the service reads. Scopes are granted in a space-separated scope
+// claim, or in an scp claim listing them.
+type claims struct {
+	jwt.RegisteredClaims
+	Scope scopes `json:"scope"`
+	Scp   scopes `json:"scp"`
+}
+
+// scopes decode from a space-separated string as well as from a list
+type scopes []string
+
+func (s *scopes) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*s = list
+		return nil
+	}
+	var joined string
+	if err := json.Unmarshal(data, &joined); err != nil {
+		return err
+	}
+	*s = strings.Fields(joined)
+	return nil
+}
+
+// NewJWTVerifier creates a verifier from the auth configuration, loading the keys of its JWKS
+func NewJWTVerifier(cfg *config.Config) (*JWTVerifier, error) {
+	v := &JWTVerifier{requiredScopes: cfg.GetAuthRequiredScopes()}
+
+	var methods []string
+	if secret := cfg.GetAuthHMACSecret(); secret != "" {
+		v.hmacSecret = []byte(secret)
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	var err error
+	switch {
+	case cfg.GetAuthJWKSFile() != "":
+		v.keys, err = newFileKeySet(cfg.GetAuthJWKSFile())
+	case cfg.GetAuthJWKSURL() != "":
+		v.keys, err = newURLKeySet(cfg.GetAuthJWKSURL(), cfg.GetAuthJWKSRefresh())
+	}
+	if err != nil {
+		return nil, err
+	}
+	if v.keys != nil {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	if len(methods) == 0 {
+		return nil, errors.New("auth needs an hmac_secret, a jwks_file or a jwks_url to verify tokens")
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithLeeway(cfg.GetAuthClockSkew()),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if issuer := cfg.GetAuthIssuer(); issuer != "" {
+		options = append(options, jwt.WithIssuer(issuer))
+	}
+	if audience := cfg.GetAuthAudience(); audience != "" {
+		options = append(options, jwt.WithAudience(audience))
+	}
+	v.parser = jwt.NewParser(options...)
+	return v, nil
+}
+
+// Verify returns the principal of a valid token, with the scopes it grants. It fails with ErrInvalidToken,
+// or with ErrInsufficientScope and the principal when the token lacks a required scope.
+func (v *JWTVerifier) Verify(ctx context.Context, token string) (Principal, error) {
+	var parsed claims
+	_, err := v.parser.ParseWithClaims(token, &parsed, func(token *jwt.Token) (interface{}, error) {
+		if token.Method.Alg() == jwt.SigningMethodHS256.Alg() {
+			return v.hmacSecret, nil
+		}
+		kid, _ := token.Header["kid"].(string)
+		return v.keys.key(ctx, kid)
+	})
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if parsed.Subject == "" {
+		return Principal{}, fmt.Errorf("%w: no subject", ErrInvalidToken)
+	}
+
+	principal := Principal{Subject: parsed.Subject, Scopes: append(parsed.Scope, parsed.Scp...)}
+	for _, scope := range v.requiredScopes {
+		if !principal.HasScope(scope) {
+			return principal, fmt.Errorf("%w: missing %s", ErrInsufficientScope, scope)
+		}
+	}
+	return principal, nil
+}
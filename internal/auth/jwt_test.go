@@ -0,0 +1,224 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"internal-transfer-microservice/internal/config"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newRSAKey generates a signing key, and the JWKS entry of its public key named kid
+func newRSAKey(t *testing.T, kid string) (*rsa.PrivateKey, map[string]string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Expected an RSA key, got %v", err)
+	}
+	return key, map[string]string{
+		"kty": "RSA",
+		"kid": kid,
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+// signRS256 signs claims with key, naming kid in the header
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("Expected a signed token, got %v", err)
+	}
+	return signed
+}
+
+func validClaims(claims jwt.MapClaims) jwt.MapClaims {
+	valid := jwt.MapClaims{"sub": "svc-payments", "iat": time.Now().Unix(), "exp": time.Now().Add(time.Hour).Unix()}
+	for name, value := range claims {
+		valid[name] = value
+	}
+	return valid
+}
+
+func TestJWTVerifierWithJWKSFile(t *testing.T) {
+	// Setup
+	key, entry := newRSAKey(t, "key-1")
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	raw, _ := json.Marshal(map[string]any{"keys": []any{entry, map[string]string{"kty": "EC", "kid": "ec-1"}}})
+	os.WriteFile(path, raw, 0o600)
+
+	verifier, err := NewJWTVerifier(&config.Config{Auth: config.AuthConfig{
+		Issuer:         "https://id.example.com",
+		RequiredScopes: []string{"transfers"},
+		JWKSFile:       path,
+	}})
+	if err != nil {
+		t.Fatalf("Expected a verifier, got %v", err)
+	}
+	ctx := context.Background()
+
+	// Test case: scopes are read from a scp list as well as from a scope string
+	principal, err := verifier.Verify(ctx, signRS256(t, key, "key-1", validClaims(jwt.MapClaims{
+		"iss": "https://id.example.com",
+		"scp": []string{"transfers", "transfers:admin"},
+	})))
+	if err != nil || principal.Subject != "svc-payments" || !principal.HasScope("transfers:admin") {
+		t.Errorf("Expected svc-payments with transfers:admin, got %+v (%v)", principal, err)
+	}
+	principal, err = verifier.Verify(ctx, signRS256(t, key, "key-1", validClaims(jwt.MapClaims{
+		"iss":   "https://id.example.com",
+		"scope": "accounts:read transfers",
+	})))
+	if err != nil || !principal.HasScope("accounts:read") {
+		t.Errorf("Expected accounts:read, got %+v (%v)", principal, err)
+	}
+
+	// Test case: tokens of another issuer, an unknown key, without subject or signed with HS256 are invalid
+	other, _ := newRSAKey(t, "key-1")
+	invalid := map[string]string{
+		"other issuer":  signRS256(t, key, "key-1", validClaims(jwt.MapClaims{"iss": "https://evil.example.com", "scope": "transfers"})),
+		"unknown key":   signRS256(t, key, "key-2", validClaims(jwt.MapClaims{"iss": "https://id.example.com", "scope": "transfers"})),
+		"forged":        signRS256(t, other, "key-1", validClaims(jwt.MapClaims{"iss": "https://id.example.com", "scope": "transfers"})),
+		"no subject":    signRS256(t, key, "key-1", jwt.MapClaims{"iss": "https://id.example.com", "scope": "transfers", "exp": time.Now().Add(time.Hour).Unix()}),
+		"no expiration": signRS256(t, key, "key-1", jwt.MapClaims{"iss": "https://id.example.com", "scope": "transfers", "sub": "svc-payments"}),
+	}
+	hs256, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims(jwt.MapClaims{"iss": "https://id.example.com", "scope": "transfers"})).SignedString([]byte("guess"))
+	invalid["HS256"] = hs256
+	for name, token := range invalid {
+		if _, err := verifier.Verify(ctx, token); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("Expected ErrInvalidToken for %s, got %v", name, err)
+		}
+	}
+
+	// Test case: a valid token without a required scope is refused, naming its principal
+	principal, err = verifier.Verify(ctx, signRS256(t, key, "key-1", validClaims(jwt.MapClaims{"iss": "https://id.example.com"})))
+	if !errors.Is(err, ErrInsufficientScope) || principal.Subject != "svc-payments" {
+		t.Errorf("Expected ErrInsufficientScope for svc-payments, got %+v (%v)", principal, err)
+	}
+}
+
+func TestJWTVerifierWithJWKSURL(t *testing.T) {
+	// Setup
+	oldKey, oldEntry := newRSAKey(t, "old")
+	newKey, newEntry := newRSAKey(t, "new")
+	var rotated atomic.Bool
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fetches.Add(1)
+		entries := []any{oldEntry}
+		if rotated.Load() {
+			entries = []any{newEntry}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"keys": entries})
+	}))
+	defer server.Close()
+
+	verifier, err := NewJWTVerifier(&config.Config{Auth: config.AuthConfig{JWKSURL: server.URL, JWKSRefresh: time.Hour}})
+	if err != nil {
+		t.Fatalf("Expected a verifier, got %v", err)
+	}
+	ctx := context.Background()
+
+	// Test case: tokens are verified with the fetched keys, which are then cached
+	for i := 0; i < 3; i++ {
+		if _, err := verifier.Verify(ctx, signRS256(t, oldKey, "old", validClaims(nil))); err != nil {
+			t.Fatalf("Expected a valid token, got %v", err)
+		}
+	}
+	if fetches.Load() != 1 {
+		t.Errorf("Expected the keys to be fetched once, got %d", fetches.Load())
+	}
+
+	// Test case: a token naming a key the set does not hold fetches the keys again
+	rotated.Store(true)
+	verifier.keys.attemptedAt = time.Now().Add(-2 * jwksMinRefetch)
+	if _, err := verifier.Verify(ctx, signRS256(t, newKey, "new", validClaims(nil))); err != nil {
+		t.Errorf("Expected the rotated key to be fetched, got %v", err)
+	}
+
+	// Test case: unknown keys are fetched again at most once per jwksMinRefetch
+	for i := 0; i < 3; i++ {
+		verifier.Verify(ctx, signRS256(t, oldKey, "unknown", validClaims(nil)))
+	}
+	if fetches.Load() != 2 {
+		t.Errorf("Expected 2 fetches, got %d", fetches.Load())
+	}
+
+	// Test case: a verifier needs keys
+	if _, err := NewJWTVerifier(&config.Config{}); err == nil {
+		t.Errorf("Expected a verifier without keys to be refused")
+	}
+}
+
+func TestJWKSFetchOutsideRequests(t *testing.T) {
+	// Setup
+	_, oldEntry := newRSAKey(t, "old")
+	_, newEntry := newRSAKey(t, "new")
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	close(release)
+	entries := []any{oldEntry}
+	set := &keySet{refresh: time.Hour, load: func(ctx context.Context) ([]byte, error) {
+		fetches.Add(1)
+		<-release
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return json.Marshal(map[string]any{"keys": entries})
+	}}
+	if err := set.fetch(context.Background()); err != nil {
+		t.Fatalf("Expected the keys to be fetched, got %v", err)
+	}
+	release = make(chan struct{})
+	entries = []any{oldEntry, newEntry}
+	set.attemptedAt = time.Now().Add(-2 * jwksMinRefetch)
+
+	// Test case: a request naming an unknown key waits for the fetch until it is cancelled, without cancelling it
+	reqCtx, cancel := context.WithCancel(context.Background())
+	cancelled := make(chan error, 1)
+	go func() {
+		_, err := set.key(reqCtx, "new")
+		cancelled <- err
+	}()
+	for fetches.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	waiting := make(chan error, 1)
+	go func() {
+		_, err := set.key(context.Background(), "new")
+		waiting <- err
+	}()
+
+	// Test case: known keys are served while the keys are fetched
+	if _, err := set.key(context.Background(), "old"); err != nil {
+		t.Errorf("Expected the held key during the fetch, got %v", err)
+	}
+
+	cancel()
+	if err := <-cancelled; err == nil {
+		t.Errorf("Expected the cancelled request to find no key")
+	}
+	close(release)
+	if err := <-waiting; err != nil {
+		t.Errorf("Expected the fetched key, got %v", err)
+	}
+	if _, err := set.key(context.Background(), "new"); err != nil {
+		t.Errorf("Expected the fetched key after the cancelled request, got %v", err)
+	}
+	if fetches.Load() != 2 {
+		t.Errorf("Expected the requests to share one fetch, got %d fetches", fetches.Load())
+	}
+}
@@ -0,0 +1,38 @@
+package auth
+
+import "context"
+
+// Principal identifies the caller a request acts on behalf of
+type Principal struct {
+	Subject string
+	Scopes  []string
+}
+
+type principalContextKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the principal
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal carried by ctx, if any
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey{}).(Principal)
+	return principal, ok
+}
+
+// SubjectFromContext returns the subject of the principal carried by ctx, or an empty string
+func SubjectFromContext(ctx context.Context) string {
+	principal, _ := PrincipalFromContext(ctx)
+	return principal.Subject
+}
+
+// HasScope reports whether the principal was granted scope
+func (p Principal) HasScope(scope string) bool {
+	for _, granted := range p.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+// Package cli implements the operator commands of the binary: account and transfer operations sent to
+// the service over HTTP, or run directly against the account service, printed as a table or as JSON.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"internal-transfer-microservice/internal/auth"
+	"internal-transfer-microservice/internal/domain/access"
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/pkg/client"
+)
+
+// Operations are what operators can do to accounts. *client.Client sends them to a running service.
+type Operations interface {
+	CreateAccount(ctx context.Context, req client.CreateAccountRequest) error
+	GetAccount(ctx context.Context, accountId string) (*client.Account, error)
+	ListAccounts(ctx context.Context, opts client.ListAccountsOptions) (*client.AccountPage, error)
+	FreezeAccount(ctx context.Context, accountId string, req client.FreezeAccountRequest) (*client.Account, error)
+	Transfer(ctx context.Context, req client.TransferRequest) (*client.TransferResult, error)
+}
+
+var _ Operations = (*client.Client)(nil)
+
+// directOperations runs the operations against the account service in process, on behalf of operator
+type directOperations struct {
+	service  account.Service
+	operator string
+}
+
+// NewDirectOperations runs operations against service in process instead of over HTTP, for when the API
+// is down. They act on behalf of operator, as if it was sent in X-Operator-Id; whoever can reach the
+// database can act on every account, so operator is granted access.AdminScope.
+func NewDirectOperations(service account.Service, operator string) Operations {
+	return &directOperations{service: service, operator: operator}
+}
+
+func (d *directOperations) context(ctx context.Context) context.Context {
+	if d.operator == "" {
+		return ctx
+	}
+	return auth.WithPrincipal(ctx, auth.Principal{Subject: d.operator, Scopes: []string{access.AdminScope}})
+}
+
+func (d *directOperations) CreateAccount(ctx context.Context, req client.CreateAccountRequest) error {
+	var create account.CreateAccountRequest
+	if err := convert(req, &create); err != nil {
+		return err
+	}
+	if create.AccountId == "" {
+		return errors.New("account id is required")
+	}
+	_, err := d.service.CreateAccount(d.context(ctx), create)
+	return err
+}
+
+func (d *directOperations) GetAccount(ctx context.Context, accountId string) (*client.Account, error) {
+	response, err := d.service.GetAccount(d.context(ctx), accountId)
+	if err != nil {
+		return nil, err
+	}
+	var acc client.Account
+	return &acc, convert(response, &acc)
+}
+
+func (d *directOperations) ListAccounts(ctx context.Context, opts client.ListAccountsOptions) (*client.AccountPage, error) {
+	response, err := d.service.ListAccounts(d.context(ctx), opts.After, opts.Limit)
+	if err != nil {
+		return nil, err
+	}
+	var page client.AccountPage
+	return &page, convert(response, &page)
+}
+
+func (d *directOperations) FreezeAccount(ctx context.Context, accountId string, req client.FreezeAccountRequest) (*client.Account, error) {
+	response, err := d.service.SetAccountFrozen(d.context(ctx), accountId, account.FreezeAccountRequest{Frozen: req.Frozen, Reason: req.Reason})
+	if err != nil {
+		return nil, err
+	}
+	var acc client.Account
+	return &acc, convert(response, &acc)
+}
+
+// Transfer fails like the service does, with the response's message, except for transfers refused for lack
+// of funds, which the service answers without an error
+func (d *directOperations) Transfer(ctx context.Context, req client.TransferRequest) (*client.TransferResult, error) {
+	response, err := d.service.TxnAccount(d.context(ctx), req.SourceAccountId, req.DestinationAccountId, req.Amount)
+	if err != nil {
+		if response.Message == "" {
+			return nil, err
+		}
+		return nil, &refusedError{message: response.Message, err: err}
+	}
+	if response.Transfer == nil {
+		return nil, errors.New(response.Message)
+	}
+	result := &client.TransferResult{Message: response.Message}
+	return result, convert(response.Transfer, &result.Transfer)
+}
+
+// refusedError is a transfer the service refused, described by the message of its response
+type refusedError struct {
+	message string
+	err     error
+}
+
+func (e *refusedError) Error() string {
+	return e.message + ": " + e.err.Error()
+}
+
+func (e *refusedError) Unwrap() error {
+	return e.err
+}
+
+// convert copies in to out through their JSON form, which the service's and the client's types share
+func convert(in, out any) error {
+	raw, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, out)
+}
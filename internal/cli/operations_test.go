@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/infrastructure/cache"
+	"internal-transfer-microservice/internal/repository"
+	"internal-transfer-microservice/internal/service"
+	"internal-transfer-microservice/pkg/client"
+	"strings"
+	"testing"
+)
+
+func TestDirectOperations(t *testing.T) {
+	// Setup
+	accountService := service.NewAccountService(repository.NewMemoryAccountRepo(), cache.NewMemoryCache())
+	operations := NewDirectOperations(accountService, "oncall")
+	ctx := context.Background()
+
+	// Test case: accounts are created and read through the service
+	for _, id := range []string{"acc-1", "acc-2"} {
+		if err := operations.CreateAccount(ctx, client.CreateAccountRequest{AccountId: id, HolderName: "Holder " + id, OwnerId: "holder", InitialBalance: 100}); err != nil {
+			t.Fatalf("Expected %s to be created, got %v", id, err)
+		}
+	}
+	if err := operations.CreateAccount(ctx, client.CreateAccountRequest{}); err == nil {
+		t.Errorf("Expected an account without id to be refused")
+	}
+	// the operator acts as an admin, so must name the owner
+	if err := operations.CreateAccount(ctx, client.CreateAccountRequest{AccountId: "acc-3"}); !errors.Is(err, service.ErrOwnerRequired) {
+		t.Errorf("Expected ErrOwnerRequired for an account without owner, got %v", err)
+	}
+	acc, err := operations.GetAccount(ctx, "acc-1")
+	if err != nil || acc.Balance != 100 || acc.HolderName != "Holder acc-1" {
+		t.Errorf("Expected acc-1 with a balance of 100, got %+v (%v)", acc, err)
+	}
+	page, err := operations.ListAccounts(ctx, client.ListAccountsOptions{Limit: 1})
+	if err != nil || len(page.Accounts) != 1 || page.NextAfter != "acc-1" {
+		t.Errorf("Expected a page of acc-1, got %+v (%v)", page, err)
+	}
+
+	// Test case: freezing acts on behalf of the operator, and refuses transfers
+	if _, err := NewDirectOperations(accountService, "").FreezeAccount(ctx, "acc-2", client.FreezeAccountRequest{Frozen: true}); !errors.Is(err, service.ErrOperatorRequired) {
+		t.Errorf("Expected ErrOperatorRequired without an operator, got %v", err)
+	}
+	acc, err = operations.FreezeAccount(ctx, "acc-2", client.FreezeAccountRequest{Frozen: true, Reason: "fraud report"})
+	if err != nil || !acc.Frozen {
+		t.Fatalf("Expected acc-2 to be frozen, got %+v (%v)", acc, err)
+	}
+	_, err = operations.Transfer(ctx, client.TransferRequest{SourceAccountId: "acc-1", DestinationAccountId: "acc-2", Amount: 10})
+	if !errors.Is(err, account.ErrAccountFrozen) {
+		t.Errorf("Expected ErrAccountFrozen, got %v", err)
+	}
+
+	// Test case: transfers execute once the account is unfrozen, and fail without funds
+	operations.FreezeAccount(ctx, "acc-2", client.FreezeAccountRequest{Frozen: false})
+	result, err := operations.Transfer(ctx, client.TransferRequest{SourceAccountId: "acc-1", DestinationAccountId: "acc-2", Amount: 10})
+	if err != nil || result.Transfer == nil || result.Transfer.Status != client.StatusCompleted || result.Transfer.InitiatedBy != "oncall" {
+		t.Errorf("Expected a transfer completed for oncall, got %+v (%v)", result, err)
+	}
+	if _, err := operations.Transfer(ctx, client.TransferRequest{SourceAccountId: "acc-1", DestinationAccountId: "acc-2", Amount: 1000}); err == nil {
+		t.Errorf("Expected a transfer without funds to fail")
+	}
+}
+
+func TestPrinter(t *testing.T) {
+	// Setup
+	var out bytes.Buffer
+	accounts := []client.Account{{AccountId: "acc-1", Balance: 12.5}, {AccountId: "acc-2", Frozen: true}}
+
+	// Test case: unknown formats are refused
+	if _, err := NewPrinter(&out, "yaml"); err == nil {
+		t.Errorf("Expected an unknown format to be refused")
+	}
+
+	// Test case: a table has a row per account, and tells how to list the next page
+	table, _ := NewPrinter(&out, FormatTable)
+	table.AccountPage(&client.AccountPage{Accounts: accounts, NextAfter: "acc-2"})
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 5 || !strings.HasPrefix(lines[0], "ACCOUNT") || !strings.Contains(lines[1], "12.50") || !strings.Contains(lines[4], "--after acc-2") {
+		t.Errorf("Unexpected table\n%s", out.String())
+	}
+
+	// Test case: JSON prints a single account as an object
+	out.Reset()
+	printer, _ := NewPrinter(&out, FormatJSON)
+	printer.Accounts(accounts[1])
+	var acc client.Account
+	if err := json.Unmarshal(out.Bytes(), &acc); err != nil || acc.AccountId != "acc-2" || !acc.Frozen {
+		t.Errorf("Expected acc-2 as JSON, got %s (%v)", out.String(), err)
+	}
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"internal-transfer-microservice/pkg/client"
+)
+
+// Output formats
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+)
+
+// Printer writes the results of operations in a format
+type Printer struct {
+	out    io.Writer
+	format string
+}
+
+// NewPrinter writes to out in format, which is FormatTable or FormatJSON
+func NewPrinter(out io.Writer, format string) (*Printer, error) {
+	if format != FormatTable && format != FormatJSON {
+		return nil, fmt.Errorf("unknown output format %q, expected %s or %s", format, FormatTable, FormatJSON)
+	}
+	return &Printer{out: out, format: format}, nil
+}
+
+// Accounts prints accounts one per row
+func (p *Printer) Accounts(accounts ...client.Account) error {
+	if p.format == FormatJSON {
+		if len(accounts) == 1 {
+			return p.json(accounts[0])
+		}
+		return p.json(accounts)
+	}
+
+	table := tabwriter.NewWriter(p.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "ACCOUNT\tHOLDER\tOWNER\tTYPE\tTIER\tBALANCE\tAVAILABLE\tOVERDRAFT LIMIT\tFROZEN")
+	for _, acc := range accounts {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%t\n", acc.AccountId, acc.HolderName, acc.OwnerId, acc.AccountType, acc.Tier,
+			amount(acc.Balance), amount(acc.AvailableBalance), amount(acc.OverdraftLimit), acc.Frozen)
+	}
+	return table.Flush()
+}
+
+// AccountPage prints a page of accounts, and in a table how to list the next one
+func (p *Printer) AccountPage(page *client.AccountPage) error {
+	if p.format == FormatJSON {
+		return p.json(page)
+	}
+	if err := p.Accounts(page.Accounts...); err != nil {
+		return err
+	}
+	if page.NextAfter != "" {
+		_, err := fmt.Fprintf(p.out, "\nMore accounts follow; list them with --after %s\n", page.NextAfter)
+		return err
+	}
+	return nil
+}
+
+// Transfer prints an executed or pending transfer
+func (p *Printer) Transfer(result *client.TransferResult) error {
+	if p.format == FormatJSON {
+		return p.json(struct {
+			Message  string           `json:"message"`
+			Transfer *client.Transfer `json:"transfer"`
+		}{result.Message, result.Transfer})
+	}
+
+	txn := result.Transfer
+	table := tabwriter.NewWriter(p.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "TRANSFER\tSOURCE\tDESTINATION\tAMOUNT\tFEE\tSTATUS")
+	fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\n", txn.TransferId, txn.SourceAccountId, txn.DestinationAccountId,
+		amount(txn.Amount), amount(txn.Fee), txn.Status)
+	if err := table.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(p.out, "\n%s\n", result.Message)
+	return err
+}
+
+func (p *Printer) json(v any) error {
+	encoder := json.NewEncoder(p.out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// amount formats money with two decimals
+func amount(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
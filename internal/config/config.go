@@ -4,15 +4,31 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 // Config represents the application configuration
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Redis    RedisConfig    `mapstructure:"redis"`
+	Server    ServerConfig    `mapstructure:"server"`
+	Database  DatabaseConfig  `mapstructure:"database"`
+	Redis     RedisConfig     `mapstructure:"redis"`
+	Fees      FeeConfig       `mapstructure:"fees"`
+	Interest  InterestConfig  `mapstructure:"interest"`
+	Limits    LimitsConfig    `mapstructure:"limits"`
+	Risk      RiskConfig      `mapstructure:"risk"`
+	Approvals ApprovalsConfig `mapstructure:"approvals"`
+	Screening ScreeningConfig `mapstructure:"screening"`
+	Events    EventsConfig    `mapstructure:"events"`
+	Webhooks  WebhooksConfig  `mapstructure:"webhooks"`
+	Stream    StreamConfig    `mapstructure:"stream"`
+	Accounts  AccountsConfig  `mapstructure:"accounts"`
+	ReadModel ReadModelConfig `mapstructure:"read_model"`
+	OpenAPI   OpenAPIConfig   `mapstructure:"openapi"`
+	// Idempotency represents how long responses to requests with an Idempotency-Key are replayed
+	Idempotency IdempotencyConfig `mapstructure:"idempotency"`
+	Auth        AuthConfig        `mapstructure:"auth"`
 }
 
 // ServerConfig represents the server configuration
@@ -20,16 +36,39 @@ type ServerConfig struct {
 	Port            string `mapstructure:"port"`
 	GinMode         string `mapstructure:"gin_mode"`
 	ShutdownTimeout int    `mapstructure:"shutdown_timeout"`
+	// GRPCPort is the port of the gRPC server the api command starts next to the REST one
+	GRPCPort string `mapstructure:"grpc_port"`
 }
 
 // DatabaseConfig represents the database configuration
 type DatabaseConfig struct {
+	// Driver is postgres, or sqlite for local development and tests
+	Driver string `mapstructure:"driver"`
+	// Path is the SQLite database file, or :memory:
+	Path     string `mapstructure:"path"`
 	Host     string `mapstructure:"host"`
 	Port     string `mapstructure:"port"`
 	User     string `mapstructure:"user"`
 	Password string `mapstructure:"password"`
 	Name     string `mapstructure:"name"`
 	SSLMode  string `mapstructure:"sslmode"`
+	// DSN connects to the primary instead of the fields above when set
+	DSN string `mapstructure:"dsn"`
+	// Replicas are the DSNs of read replicas that serve read-only queries
+	Replicas             []string      `mapstructure:"replicas"`
+	ReplicaMaxLag        time.Duration `mapstructure:"replica_max_lag"`
+	ReplicaCheckInterval time.Duration `mapstructure:"replica_check_interval"`
+	// Pool sizes and lifetimes apply to the primary and to every replica
+	MaxOpenConns    int           `mapstructure:"max_open_conns"`
+	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `mapstructure:"conn_max_idle_time"`
+	// StatementTimeout and LockTimeout are set on every session; zero leaves the server's setting
+	StatementTimeout time.Duration `mapstructure:"statement_timeout"`
+	LockTimeout      time.Duration `mapstructure:"lock_timeout"`
+	// LogLevel is the level of GORM's query log: silent, error, warn or info
+	LogLevel           string        `mapstructure:"log_level"`
+	SlowQueryThreshold time.Duration `mapstructure:"slow_query_threshold"`
 }
 
 // RedisConfig represents the Redis configuration
@@ -40,6 +79,207 @@ type RedisConfig struct {
 	DB       int    `mapstructure:"db"`
 }
 
+// FeeConfig represents the transfer fee configuration
+type FeeConfig struct {
+	RevenueAccountId string              `mapstructure:"revenue_account_id"`
+	Schedules        []FeeScheduleConfig `mapstructure:"schedules"`
+}
+
+// FeeScheduleConfig represents the fee schedule applied to transfers out of one account type.
+// An empty account type makes the schedule the fallback for types without their own schedule.
+type FeeScheduleConfig struct {
+	AccountType string          `mapstructure:"account_type"`
+	Flat        float64         `mapstructure:"flat"`
+	Percentage  float64         `mapstructure:"percentage"`
+	Min         float64         `mapstructure:"min"`
+	Max         float64         `mapstructure:"max"`
+	Tiers       []FeeTierConfig `mapstructure:"tiers"`
+}
+
+// FeeTierConfig represents one amount band of a tiered fee schedule.
+// A zero UpTo marks the open-ended top tier.
+type FeeTierConfig struct {
+	UpTo       float64 `mapstructure:"up_to"`
+	Flat       float64 `mapstructure:"flat"`
+	Percentage float64 `mapstructure:"percentage"`
+}
+
+// InterestConfig represents the interest accrual and posting configuration
+type InterestConfig struct {
+	FundingAccountId string           `mapstructure:"funding_account_id"`
+	RatePlans        []RatePlanConfig `mapstructure:"rate_plans"`
+}
+
+// RatePlanConfig represents an interest rate plan accounts can be attached to
+type RatePlanConfig struct {
+	Id         string  `mapstructure:"id"`
+	AnnualRate float64 `mapstructure:"annual_rate"`
+	DayCount   string  `mapstructure:"day_count"`
+}
+
+// LimitsConfig represents the velocity and transfer limits configuration
+type LimitsConfig struct {
+	Tiers []LimitTierConfig `mapstructure:"tiers"`
+}
+
+// LimitTierConfig represents the outgoing transfer limits of one account tier.
+// Zero values leave a limit unset; an empty tier makes the entry the fallback for unconfigured tiers.
+type LimitTierConfig struct {
+	Tier           string  `mapstructure:"tier"`
+	PerTransferMax float64 `mapstructure:"per_transfer_max"`
+	DailyAmount    float64 `mapstructure:"daily_amount"`
+	MonthlyAmount  float64 `mapstructure:"monthly_amount"`
+	DailyCount     int     `mapstructure:"daily_count"`
+	MonthlyCount   int     `mapstructure:"monthly_count"`
+}
+
+// RiskConfig represents the pre-transfer risk screening configuration
+type RiskConfig struct {
+	VelocityWindow time.Duration    `mapstructure:"velocity_window"`
+	Rules          []RiskRuleConfig `mapstructure:"rules"`
+}
+
+// RiskRuleConfig represents one rule of the built-in risk rules engine
+type RiskRuleConfig struct {
+	Name      string        `mapstructure:"name"`
+	Type      string        `mapstructure:"type"`
+	Threshold float64       `mapstructure:"threshold"`
+	MaxAge    time.Duration `mapstructure:"max_age"`
+	Accounts  []string      `mapstructure:"accounts"`
+	Action    string        `mapstructure:"action"`
+}
+
+// ApprovalsConfig represents the maker-checker approval configuration
+type ApprovalsConfig struct {
+	// Threshold is the amount above which a transfer needs a second operator's approval; zero disables approvals
+	Threshold float64       `mapstructure:"threshold"`
+	Expiry    time.Duration `mapstructure:"expiry"`
+}
+
+// ScreeningConfig represents the watchlist screening configuration
+type ScreeningConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// WatchlistPath is the OFAC-style CSV or XML file loaded by the reload command
+	WatchlistPath string `mapstructure:"watchlist_path"`
+	// Names scoring at least FlagThreshold hold the transfer for review, at least BlockThreshold block it
+	FlagThreshold  float64 `mapstructure:"flag_threshold"`
+	BlockThreshold float64 `mapstructure:"block_threshold"`
+}
+
+// EventsConfig represents the domain event publishing configuration
+type EventsConfig struct {
+	// Publisher selects where the outbox relay delivers events: log, kafka, nats or file
+	Publisher string `mapstructure:"publisher"`
+	Topic     string `mapstructure:"topic"`
+	// RelayInterval is how often the relay polls the outbox, RelayBatchSize how many events it reads per poll
+	RelayInterval  time.Duration  `mapstructure:"relay_interval"`
+	RelayBatchSize int            `mapstructure:"relay_batch_size"`
+	Kafka          KafkaConfig    `mapstructure:"kafka"`
+	NATS           NATSConfig     `mapstructure:"nats"`
+	File           FileSinkConfig `mapstructure:"file"`
+}
+
+// KafkaConfig represents the Kafka-protocol broker events are published to
+type KafkaConfig struct {
+	Brokers      []string      `mapstructure:"brokers"`
+	WriteTimeout time.Duration `mapstructure:"write_timeout"`
+}
+
+// NATSConfig represents the NATS server events are published to
+type NATSConfig struct {
+	URL string `mapstructure:"url"`
+	// Partitions spreads events over subjects <topic>.<n> by account; one partition publishes to the topic itself
+	Partitions   int           `mapstructure:"partitions"`
+	FlushTimeout time.Duration `mapstructure:"flush_timeout"`
+}
+
+// WebhooksConfig represents the outgoing webhook delivery configuration
+type WebhooksConfig struct {
+	// Timeout bounds each HTTP call to a subscriber
+	Timeout time.Duration `mapstructure:"timeout"`
+	// MaxAttempts is how many calls a delivery gets before it is dead-lettered
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// RetryBackoff is the delay before the first retry, doubled after every failure up to MaxBackoff
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"`
+	MaxBackoff   time.Duration `mapstructure:"max_backoff"`
+	// DispatchInterval is how often the dispatcher polls for due deliveries, BatchSize how many it reads per poll
+	DispatchInterval time.Duration `mapstructure:"dispatch_interval"`
+	BatchSize        int           `mapstructure:"batch_size"`
+}
+
+// AccountsConfig represents how accounts are stored
+type AccountsConfig struct {
+	// Store is "table" for mutable account rows or "event_sourced" for append-only event streams
+	Store string `mapstructure:"store"`
+	// SnapshotEvery is how many events an event-sourced account records between snapshots
+	SnapshotEvery int                `mapstructure:"snapshot_every"`
+	Cache         AccountCacheConfig `mapstructure:"cache"`
+}
+
+// AccountCacheConfig represents the read-through cache of account lookups
+type AccountCacheConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	TTL     time.Duration `mapstructure:"ttl"`
+	// NegativeTTL is how long a lookup of a missing account is cached
+	NegativeTTL time.Duration `mapstructure:"negative_ttl"`
+}
+
+// ReadModelConfig represents the account read model configuration
+type ReadModelConfig struct {
+	// Enabled serves account reads from the read model instead of the accounts table
+	Enabled         bool          `mapstructure:"enabled"`
+	ProjectInterval time.Duration `mapstructure:"project_interval"`
+	BatchSize       int           `mapstructure:"batch_size"`
+}
+
+// StreamConfig represents the real-time account event stream configuration
+type StreamConfig struct {
+	// BacklogSize is how many events are kept per account for clients resuming with Last-Event-ID
+	BacklogSize int           `mapstructure:"backlog_size"`
+	BacklogTTL  time.Duration `mapstructure:"backlog_ttl"`
+	// Heartbeat is how often an idle stream sends a comment to keep proxies from closing it
+	Heartbeat time.Duration `mapstructure:"heartbeat"`
+}
+
+// OpenAPIConfig represents the validation of the REST API against its OpenAPI document
+type OpenAPIConfig struct {
+	// ValidateRequests refuses requests that do not match the document with 400
+	ValidateRequests bool `mapstructure:"validate_requests"`
+	// ValidateResponses logs responses that do not match the document, to spot drift
+	ValidateResponses bool `mapstructure:"validate_responses"`
+}
+
+// IdempotencyConfig represents the replay of responses to POST requests carrying an Idempotency-Key
+type IdempotencyConfig struct {
+	// TTL is how long a response is replayed to retries with the same key
+	TTL time.Duration `mapstructure:"ttl"`
+}
+
+// AuthConfig represents the authentication of API callers with JWT bearer tokens
+type AuthConfig struct {
+	// Enabled requires a bearer token on the API routes, and stops trusting the X-Operator-Id header
+	Enabled  bool   `mapstructure:"enabled"`
+	Issuer   string `mapstructure:"issuer"`
+	Audience string `mapstructure:"audience"`
+	// ClockSkew is how far the clocks of the issuer and the service may drift apart
+	ClockSkew time.Duration `mapstructure:"clock_skew"`
+	// RequiredScopes are granted to every token allowed to call the API; others are refused with 403
+	RequiredScopes []string `mapstructure:"required_scopes"`
+	// HMACSecret verifies HS256 tokens
+	HMACSecret string `mapstructure:"hmac_secret"`
+	// JWKSFile or JWKSURL hold the keys verifying RS256 tokens
+	JWKSFile string `mapstructure:"jwks_file"`
+	JWKSURL  string `mapstructure:"jwks_url"`
+	// JWKSRefresh is how often keys are fetched again from JWKSURL
+	JWKSRefresh time.Duration `mapstructure:"jwks_refresh"`
+}
+
+// FileSinkConfig represents the newline-delimited JSON file events are appended to
+type FileSinkConfig struct {
+	// Path of the file, or "-" for standard output
+	Path string `mapstructure:"path"`
+}
+
 // LoadConfig loads the configuration from the specified file
 func LoadConfig(configPath string) (*Config, error) {
 	v := viper.New()
@@ -74,18 +314,106 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("server.port", "8080")
 	v.SetDefault("server.gin_mode", "debug")
 	v.SetDefault("server.shutdown_timeout", 5)
+	v.SetDefault("server.grpc_port", "9090")
 
 	// Database defaults
+	v.SetDefault("database.driver", "postgres")
+	v.SetDefault("database.path", "transfer_service.db")
 	v.SetDefault("database.host", "localhost")
 	v.SetDefault("database.port", "5432")
 	v.SetDefault("database.user", "postgres")
 	v.SetDefault("database.password", "postgres")
 	v.SetDefault("database.name", "transfer_service")
 	v.SetDefault("database.sslmode", "disable")
+	v.SetDefault("database.dsn", "")
+	v.SetDefault("database.replicas", []string{})
+	v.SetDefault("database.replica_max_lag", "5s")
+	v.SetDefault("database.replica_check_interval", "5s")
+	v.SetDefault("database.max_open_conns", 25)
+	v.SetDefault("database.max_idle_conns", 10)
+	v.SetDefault("database.conn_max_lifetime", "30m")
+	v.SetDefault("database.conn_max_idle_time", "5m")
+	v.SetDefault("database.statement_timeout", "30s")
+	v.SetDefault("database.lock_timeout", "10s")
+	v.SetDefault("database.log_level", "warn")
+	v.SetDefault("database.slow_query_threshold", "200ms")
 
 	// Redis defaults
 	v.SetDefault("redis.host", "localhost")
 	v.SetDefault("redis.port", "6379")
 	v.SetDefault("redis.password", "")
 	v.SetDefault("redis.db", 0)
+
+	// Fee defaults
+	v.SetDefault("fees.revenue_account_id", "")
+
+	// Interest defaults
+	v.SetDefault("interest.funding_account_id", "")
+
+	// Risk defaults
+	v.SetDefault("risk.velocity_window", "1h")
+
+	// Approval defaults
+	v.SetDefault("approvals.threshold", 0)
+	v.SetDefault("approvals.expiry", "24h")
+
+	// Screening defaults
+	v.SetDefault("screening.enabled", false)
+	v.SetDefault("screening.flag_threshold", 0.85)
+	v.SetDefault("screening.block_threshold", 0.95)
+
+	// Event defaults
+	v.SetDefault("events.publisher", "log")
+	v.SetDefault("events.topic", "transfer-service.events")
+	v.SetDefault("events.relay_interval", "1s")
+	v.SetDefault("events.relay_batch_size", 100)
+	v.SetDefault("events.kafka.brokers", []string{"localhost:9092"})
+	v.SetDefault("events.kafka.write_timeout", "10s")
+	v.SetDefault("events.nats.url", "nats://localhost:4222")
+	v.SetDefault("events.nats.partitions", 1)
+	v.SetDefault("events.nats.flush_timeout", "5s")
+	v.SetDefault("events.file.path", "events.ndjson")
+
+	// Webhook defaults
+	v.SetDefault("webhooks.timeout", "10s")
+	v.SetDefault("webhooks.max_attempts", 8)
+	v.SetDefault("webhooks.retry_backoff", "30s")
+	v.SetDefault("webhooks.max_backoff", "1h")
+	v.SetDefault("webhooks.dispatch_interval", "1s")
+	v.SetDefault("webhooks.batch_size", 50)
+
+	// Account store defaults
+	v.SetDefault("accounts.store", "table")
+	v.SetDefault("accounts.snapshot_every", 100)
+	v.SetDefault("accounts.cache.enabled", false)
+	v.SetDefault("accounts.cache.ttl", "30s")
+	v.SetDefault("accounts.cache.negative_ttl", "5s")
+
+	// Read model defaults
+	v.SetDefault("read_model.enabled", false)
+	v.SetDefault("read_model.project_interval", "1s")
+	v.SetDefault("read_model.batch_size", 100)
+
+	// Stream defaults
+	v.SetDefault("stream.backlog_size", 1000)
+	v.SetDefault("stream.backlog_ttl", "24h")
+	v.SetDefault("stream.heartbeat", "15s")
+
+	// OpenAPI defaults
+	v.SetDefault("openapi.validate_requests", true)
+	v.SetDefault("openapi.validate_responses", false)
+
+	// Idempotency defaults
+	v.SetDefault("idempotency.ttl", "24h")
+
+	// Auth defaults
+	v.SetDefault("auth.enabled", false)
+	v.SetDefault("auth.issuer", "")
+	v.SetDefault("auth.audience", "")
+	v.SetDefault("auth.clock_skew", "30s")
+	v.SetDefault("auth.required_scopes", []string{})
+	v.SetDefault("auth.hmac_secret", "")
+	v.SetDefault("auth.jwks_file", "")
+	v.SetDefault("auth.jwks_url", "")
+	v.SetDefault("auth.jwks_refresh", "1h")
 }
@@ -1,10 +1,17 @@
 package config
 
+import "time"
+
 // GetServerPort returns the server port
 func (c *Config) GetServerPort() string {
 	return c.Server.Port
 }
 
+// GetGRPCPort returns the gRPC server port
+func (c *Config) GetGRPCPort() string {
+	return c.Server.GRPCPort
+}
+
 // GetGinMode returns the Gin mode
 func (c *Config) GetGinMode() string {
 	return c.Server.GinMode
@@ -15,6 +22,16 @@ func (c *Config) GetShutdownTimeout() int {
 	return c.Server.ShutdownTimeout
 }
 
+// GetDBDriver returns the database driver, postgres or sqlite
+func (c *Config) GetDBDriver() string {
+	return c.Database.Driver
+}
+
+// GetDBPath returns the path of the SQLite database
+func (c *Config) GetDBPath() string {
+	return c.Database.Path
+}
+
 // GetDBHost returns the database host
 func (c *Config) GetDBHost() string {
 	return c.Database.Host
@@ -65,8 +82,281 @@ func (c *Config) GetRedisDB() int {
 	return c.Redis.DB
 }
 
-// GetDBConnectionString returns the database connection string
+// GetFeeRevenueAccountId returns the account that transfer fees are posted to
+func (c *Config) GetFeeRevenueAccountId() string {
+	return c.Fees.RevenueAccountId
+}
+
+// GetFeeSchedules returns the configured transfer fee schedules
+func (c *Config) GetFeeSchedules() []FeeScheduleConfig {
+	return c.Fees.Schedules
+}
+
+// GetInterestFundingAccountId returns the account that posted interest is paid from
+func (c *Config) GetInterestFundingAccountId() string {
+	return c.Interest.FundingAccountId
+}
+
+// GetRatePlans returns the configured interest rate plans
+func (c *Config) GetRatePlans() []RatePlanConfig {
+	return c.Interest.RatePlans
+}
+
+// GetLimitTiers returns the configured transfer limits per account tier
+func (c *Config) GetLimitTiers() []LimitTierConfig {
+	return c.Limits.Tiers
+}
+
+// GetRiskVelocityWindow returns the window recent transfer velocity is measured over
+func (c *Config) GetRiskVelocityWindow() time.Duration {
+	return c.Risk.VelocityWindow
+}
+
+// GetRiskRules returns the configured risk screening rules
+func (c *Config) GetRiskRules() []RiskRuleConfig {
+	return c.Risk.Rules
+}
+
+// GetApprovalThreshold returns the amount above which transfers need approval
+func (c *Config) GetApprovalThreshold() float64 {
+	return c.Approvals.Threshold
+}
+
+// GetApprovalExpiry returns how long a pending transfer waits for a decision
+func (c *Config) GetApprovalExpiry() time.Duration {
+	return c.Approvals.Expiry
+}
+
+// GetScreeningEnabled returns whether transfers are screened against the watchlist
+func (c *Config) GetScreeningEnabled() bool {
+	return c.Screening.Enabled
+}
+
+// GetWatchlistPath returns the path of the watchlist file
+func (c *Config) GetWatchlistPath() string {
+	return c.Screening.WatchlistPath
+}
+
+// GetScreeningFlagThreshold returns the match score from which a transfer is held for review
+func (c *Config) GetScreeningFlagThreshold() float64 {
+	return c.Screening.FlagThreshold
+}
+
+// GetScreeningBlockThreshold returns the match score from which a transfer is blocked
+func (c *Config) GetScreeningBlockThreshold() float64 {
+	return c.Screening.BlockThreshold
+}
+
+// GetEventPublisher returns the kind of publisher events are delivered with
+func (c *Config) GetEventPublisher() string {
+	return c.Events.Publisher
+}
+
+// GetEventTopic returns the topic events are published to
+func (c *Config) GetEventTopic() string {
+	return c.Events.Topic
+}
+
+// GetOutboxRelayInterval returns how often the relay polls the outbox
+func (c *Config) GetOutboxRelayInterval() time.Duration {
+	return c.Events.RelayInterval
+}
+
+// GetOutboxRelayBatchSize returns how many events the relay reads per poll
+func (c *Config) GetOutboxRelayBatchSize() int {
+	return c.Events.RelayBatchSize
+}
+
+// GetKafkaBrokers returns the addresses of the Kafka brokers
+func (c *Config) GetKafkaBrokers() []string {
+	return c.Events.Kafka.Brokers
+}
+
+// GetKafkaWriteTimeout returns how long a Kafka write may take
+func (c *Config) GetKafkaWriteTimeout() time.Duration {
+	return c.Events.Kafka.WriteTimeout
+}
+
+// GetNATSURL returns the URL of the NATS server
+func (c *Config) GetNATSURL() string {
+	return c.Events.NATS.URL
+}
+
+// GetNATSPartitions returns the number of subjects events are spread over
+func (c *Config) GetNATSPartitions() int {
+	return c.Events.NATS.Partitions
+}
+
+// GetNATSFlushTimeout returns how long to wait for the NATS server to acknowledge a publish
+func (c *Config) GetNATSFlushTimeout() time.Duration {
+	return c.Events.NATS.FlushTimeout
+}
+
+// GetEventFilePath returns the path of the event file sink
+func (c *Config) GetEventFilePath() string {
+	return c.Events.File.Path
+}
+
+// GetWebhookTimeout returns how long a webhook call may take
+func (c *Config) GetWebhookTimeout() time.Duration {
+	return c.Webhooks.Timeout
+}
+
+// GetWebhookMaxAttempts returns how many calls a webhook delivery gets before it is dead-lettered
+func (c *Config) GetWebhookMaxAttempts() int {
+	return c.Webhooks.MaxAttempts
+}
+
+// GetWebhookRetryBackoff returns the delay before the first webhook retry
+func (c *Config) GetWebhookRetryBackoff() time.Duration {
+	return c.Webhooks.RetryBackoff
+}
+
+// GetWebhookMaxBackoff returns the longest delay between two webhook attempts
+func (c *Config) GetWebhookMaxBackoff() time.Duration {
+	return c.Webhooks.MaxBackoff
+}
+
+// GetWebhookDispatchInterval returns how often the dispatcher polls for due deliveries
+func (c *Config) GetWebhookDispatchInterval() time.Duration {
+	return c.Webhooks.DispatchInterval
+}
+
+// GetWebhookBatchSize returns how many due deliveries the dispatcher reads per poll
+func (c *Config) GetWebhookBatchSize() int {
+	return c.Webhooks.BatchSize
+}
+
+// GetAccountStore returns how accounts are stored: table or event_sourced
+func (c *Config) GetAccountStore() string {
+	return c.Accounts.Store
+}
+
+// GetAccountSnapshotEvery returns how many events an event-sourced account records between snapshots
+func (c *Config) GetAccountSnapshotEvery() int {
+	return c.Accounts.SnapshotEvery
+}
+
+// GetAccountCacheEnabled returns whether account lookups go through the cache
+func (c *Config) GetAccountCacheEnabled() bool {
+	return c.Accounts.Cache.Enabled
+}
+
+// GetAccountCacheTTL returns how long a looked up account is cached
+func (c *Config) GetAccountCacheTTL() time.Duration {
+	return c.Accounts.Cache.TTL
+}
+
+// GetAccountCacheNegativeTTL returns how long a lookup of a missing account is cached
+func (c *Config) GetAccountCacheNegativeTTL() time.Duration {
+	return c.Accounts.Cache.NegativeTTL
+}
+
+// GetStreamBacklogSize returns how many events are kept per account for resuming streams
+func (c *Config) GetStreamBacklogSize() int {
+	return c.Stream.BacklogSize
+}
+
+// GetStreamBacklogTTL returns how long the event backlog of an idle account is kept
+func (c *Config) GetStreamBacklogTTL() time.Duration {
+	return c.Stream.BacklogTTL
+}
+
+// GetStreamHeartbeat returns how often an idle event stream sends a keepalive
+func (c *Config) GetStreamHeartbeat() time.Duration {
+	return c.Stream.Heartbeat
+}
+
+// GetReadModelEnabled returns whether account reads are served from the read model
+func (c *Config) GetReadModelEnabled() bool {
+	return c.ReadModel.Enabled
+}
+
+// GetReadModelProjectInterval returns how often the projector polls the outbox
+func (c *Config) GetReadModelProjectInterval() time.Duration {
+	return c.ReadModel.ProjectInterval
+}
+
+// GetReadModelBatchSize returns how many events the projector reads per poll
+func (c *Config) GetReadModelBatchSize() int {
+	return c.ReadModel.BatchSize
+}
+
+// GetOpenAPIValidateRequests returns whether requests are validated against the OpenAPI document
+func (c *Config) GetOpenAPIValidateRequests() bool {
+	return c.OpenAPI.ValidateRequests
+}
+
+// GetOpenAPIValidateResponses returns whether responses that do not match the OpenAPI document are logged
+func (c *Config) GetOpenAPIValidateResponses() bool {
+	return c.OpenAPI.ValidateResponses
+}
+
+// GetIdempotencyTTL returns how long responses are replayed to retries with the same Idempotency-Key
+func (c *Config) GetIdempotencyTTL() time.Duration {
+	return c.Idempotency.TTL
+}
+
+// GetDBReplicaDSNs returns the DSNs of the read replicas
+func (c *Config) GetDBReplicaDSNs() []string {
+	return c.Database.Replicas
+}
+
+// GetDBReplicaMaxLag returns how far a replica may lag behind the primary and still serve reads
+func (c *Config) GetDBReplicaMaxLag() time.Duration {
+	return c.Database.ReplicaMaxLag
+}
+
+// GetDBReplicaCheckInterval returns how often the replicas' health and lag are checked
+func (c *Config) GetDBReplicaCheckInterval() time.Duration {
+	return c.Database.ReplicaCheckInterval
+}
+
+// GetDBMaxOpenConns returns the maximum number of open connections per database, zero for no limit
+func (c *Config) GetDBMaxOpenConns() int {
+	return c.Database.MaxOpenConns
+}
+
+// GetDBMaxIdleConns returns the maximum number of idle connections kept per database
+func (c *Config) GetDBMaxIdleConns() int {
+	return c.Database.MaxIdleConns
+}
+
+// GetDBConnMaxLifetime returns how long a connection is reused before it is closed
+func (c *Config) GetDBConnMaxLifetime() time.Duration {
+	return c.Database.ConnMaxLifetime
+}
+
+// GetDBConnMaxIdleTime returns how long a connection may stay idle before it is closed
+func (c *Config) GetDBConnMaxIdleTime() time.Duration {
+	return c.Database.ConnMaxIdleTime
+}
+
+// GetDBStatementTimeout returns how long a statement may run before the database cancels it
+func (c *Config) GetDBStatementTimeout() time.Duration {
+	return c.Database.StatementTimeout
+}
+
+// GetDBLockTimeout returns how long a statement may wait for a lock before the database cancels it
+func (c *Config) GetDBLockTimeout() time.Duration {
+	return c.Database.LockTimeout
+}
+
+// GetDBLogLevel returns the level of the database query log
+func (c *Config) GetDBLogLevel() string {
+	return c.Database.LogLevel
+}
+
+// GetDBSlowQueryThreshold returns how long a query may take before it is logged as slow
+func (c *Config) GetDBSlowQueryThreshold() time.Duration {
+	return c.Database.SlowQueryThreshold
+}
+
+// GetDBConnectionString returns the primary database connection string
 func (c *Config) GetDBConnectionString() string {
+	if c.Database.DSN != "" {
+		return c.Database.DSN
+	}
 	return "host=" + c.Database.Host +
 		" port=" + c.Database.Port +
 		" user=" + c.Database.User +
@@ -79,3 +369,48 @@ func (c *Config) GetDBConnectionString() string {
 func (c *Config) GetRedisAddress() string {
 	return c.Redis.Host + ":" + c.Redis.Port
 }
+
+// GetAuthEnabled returns whether API callers must authenticate with a bearer token
+func (c *Config) GetAuthEnabled() bool {
+	return c.Auth.Enabled
+}
+
+// GetAuthIssuer returns the issuer bearer tokens must name, or an empty string to accept any
+func (c *Config) GetAuthIssuer() string {
+	return c.Auth.Issuer
+}
+
+// GetAuthAudience returns the audience bearer tokens must be issued for, or an empty string to accept any
+func (c *Config) GetAuthAudience() string {
+	return c.Auth.Audience
+}
+
+// GetAuthClockSkew returns the leeway given to the time claims of bearer tokens
+func (c *Config) GetAuthClockSkew() time.Duration {
+	return c.Auth.ClockSkew
+}
+
+// GetAuthRequiredScopes returns the scopes every bearer token must grant
+func (c *Config) GetAuthRequiredScopes() []string {
+	return c.Auth.RequiredScopes
+}
+
+// GetAuthHMACSecret returns the secret verifying HS256 tokens
+func (c *Config) GetAuthHMACSecret() string {
+	return c.Auth.HMACSecret
+}
+
+// GetAuthJWKSFile returns the JWKS file holding the keys verifying RS256 tokens
+func (c *Config) GetAuthJWKSFile() string {
+	return c.Auth.JWKSFile
+}
+
+// GetAuthJWKSURL returns the URL of the JWKS holding the keys verifying RS256 tokens
+func (c *Config) GetAuthJWKSURL() string {
+	return c.Auth.JWKSURL
+}
+
+// GetAuthJWKSRefresh returns how often the keys at the JWKS URL are fetched again
+func (c *Config) GetAuthJWKSRefresh() time.Duration {
+	return c.Auth.JWKSRefresh
+}
@@ -1,11 +1,16 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
+	"internal-transfer-microservice/internal/domain/access"
 	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/limit"
+	"internal-transfer-microservice/internal/domain/transfer"
+	"internal-transfer-microservice/internal/service"
 )
 
 type AccountController struct {
@@ -24,8 +29,34 @@ func (c *AccountController) GetAccount(ctx *gin.Context) {
 	accountId := ctx.Param("id")
 
 	response, err := c.accountService.GetAccount(ctx, accountId)
+	if status := accessErrorStatus(err); status != 0 {
+		ctx.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, response)
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// ListAccounts handles GET /accounts
+func (c *AccountController) ListAccounts(ctx *gin.Context) {
+	var req account.ListAccountsRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := c.accountService.ListAccounts(ctx, req.After, req.Limit)
+	if status := accessErrorStatus(err); status != 0 {
+		ctx.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -41,7 +72,19 @@ func (c *AccountController) CreateAccount(ctx *gin.Context) {
 		return
 	}
 
-	response, err := c.accountService.CreateAccount(ctx, req.AccountId, req.InitialBalance)
+	response, err := c.accountService.CreateAccount(ctx, req)
+	if status := accessErrorStatus(err); status != 0 {
+		ctx.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, service.ErrOwnerRequired) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, account.ErrAccountExists) {
+		ctx.JSON(http.StatusConflict, response)
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, response)
 		return
@@ -60,10 +103,215 @@ func (c *AccountController) TransferMoney(ctx *gin.Context) {
 	}
 
 	response, err := c.accountService.TxnAccount(ctx, req.SourceAccountId, req.DestinationAccountId, req.Amount)
+	if status := accessErrorStatus(err); status != 0 {
+		ctx.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, service.ErrTransferAccountsRequired) || errors.Is(err, service.ErrSameAccountTransfer) || errors.Is(err, service.ErrInvalidAmount) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, service.ErrAccountNotFound) {
+		ctx.JSON(http.StatusNotFound, response)
+		return
+	}
+	var exceeded *limit.ExceededError
+	if errors.As(err, &exceeded) || errors.Is(err, service.ErrTransferDenied) || errors.Is(err, service.ErrTransferBlocked) || errors.Is(err, account.ErrAccountFrozen) {
+		ctx.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, response)
 		return
 	}
+	if response.Transfer != nil && (response.Transfer.Status == transfer.StatusPendingReview || response.Transfer.Status == transfer.StatusPendingApproval) {
+		ctx.JSON(http.StatusAccepted, response)
+		return
+	}
 
 	ctx.JSON(http.StatusOK, response)
 }
+
+// GetTransferHistory handles GET /accounts/:id/transfers
+func (c *AccountController) GetTransferHistory(ctx *gin.Context) {
+	accountId := ctx.Param("id")
+
+	history, err := c.accountService.GetTransferHistory(ctx, accountId)
+	if status := accessErrorStatus(err); status != 0 {
+		ctx.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, history)
+}
+
+// UpdateOverdraftLimit handles PUT /accounts/:id/overdraft
+func (c *AccountController) UpdateOverdraftLimit(ctx *gin.Context) {
+	var req account.UpdateOverdraftLimitRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := c.accountService.UpdateOverdraftLimit(ctx, ctx.Param("id"), req)
+	switch {
+	case accessErrorStatus(err) != 0:
+		ctx.JSON(accessErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, service.ErrAccountNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, service.ErrInvalidOverdraftLimit), errors.Is(err, service.ErrOverdraftLimitBelowUsage):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, account.ErrVersionConflict):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// FreezeAccount handles PUT /accounts/:id/freeze
+func (c *AccountController) FreezeAccount(ctx *gin.Context) {
+	var req account.FreezeAccountRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := c.accountService.SetAccountFrozen(ctx, ctx.Param("id"), req)
+	switch {
+	case accessErrorStatus(err) != 0:
+		ctx.JSON(accessErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, service.ErrAccountNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetOverdraftLimitHistory handles GET /accounts/:id/overdraft/history
+func (c *AccountController) GetOverdraftLimitHistory(ctx *gin.Context) {
+	history, err := c.accountService.GetOverdraftLimitHistory(ctx, ctx.Param("id"))
+	if status := accessErrorStatus(err); status != 0 {
+		ctx.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, history)
+}
+
+// GetRemainingLimits handles GET /accounts/:id/limits
+func (c *AccountController) GetRemainingLimits(ctx *gin.Context) {
+	allowance, err := c.accountService.GetRemainingLimits(ctx, ctx.Param("id"))
+	if status := accessErrorStatus(err); status != 0 {
+		ctx.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, service.ErrAccountNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, allowance)
+}
+
+// ListPendingTransfers handles GET /transfers/pending
+func (c *AccountController) ListPendingTransfers(ctx *gin.Context) {
+	pending, err := c.accountService.ListPendingTransfers(ctx)
+	if status := accessErrorStatus(err); status != 0 {
+		ctx.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pending)
+}
+
+// ApproveTransfer handles POST /transfers/:id/approve
+func (c *AccountController) ApproveTransfer(ctx *gin.Context) {
+	response, err := c.accountService.ApproveTransfer(ctx, ctx.Param("id"))
+	var exceeded *limit.ExceededError
+	if errors.As(err, &exceeded) {
+		ctx.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+	if err != nil {
+		ctx.JSON(transferDecisionErrorStatus(err), response)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// RejectTransfer handles POST /transfers/:id/reject
+func (c *AccountController) RejectTransfer(ctx *gin.Context) {
+	var req transfer.RejectTransferRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := c.accountService.RejectTransfer(ctx, ctx.Param("id"), req.Reason)
+	if err != nil {
+		ctx.JSON(transferDecisionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// transferDecisionErrorStatus maps the errors of approving or rejecting a transfer to an HTTP status
+func transferDecisionErrorStatus(err error) int {
+	switch {
+	case accessErrorStatus(err) != 0:
+		return accessErrorStatus(err)
+	case errors.Is(err, service.ErrSelfApproval):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrTransferNotFound), errors.Is(err, service.ErrAccountNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrTransferNotPending), errors.Is(err, service.ErrTransferExpired):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// accessErrorStatus returns the HTTP status of the errors refusing the caller of a request, or zero for other errors
+func accessErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrOperatorRequired):
+		return http.StatusUnauthorized
+	case errors.Is(err, access.ErrForbidden):
+		return http.StatusForbidden
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+)
+
+// swaggerUIPage renders the OpenAPI document with Swagger UI, loaded from a CDN
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Internal Transfer Microservice API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({url: "/openapi.json", dom_id: "#swagger-ui"});
+  </script>
+</body>
+</html>
+`
+
+type DocsController struct {
+	document []byte
+}
+
+// NewDocsController creates a new DocsController serving spec as JSON
+func NewDocsController(spec *openapi3.T) (*DocsController, error) {
+	document, err := spec.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return &DocsController{
+		document: document,
+	}, nil
+}
+
+// GetOpenAPI handles GET /openapi.json
+func (c *DocsController) GetOpenAPI(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "application/json", c.document)
+}
+
+// SwaggerUI handles GET /docs
+func (c *DocsController) SwaggerUI(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUIPage))
+}
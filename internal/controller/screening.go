@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"internal-transfer-microservice/internal/domain/screening"
+	"internal-transfer-microservice/internal/service"
+)
+
+type ScreeningController struct {
+	screeningService screening.Service
+}
+
+// NewScreeningController creates a new ScreeningController
+func NewScreeningController(screeningService screening.Service) *ScreeningController {
+	return &ScreeningController{
+		screeningService: screeningService,
+	}
+}
+
+// ListHits handles GET /screening/hits
+func (c *ScreeningController) ListHits(ctx *gin.Context) {
+	hits, err := c.screeningService.ListHits(ctx, ctx.Query("status"))
+	if status := accessErrorStatus(err); status != 0 {
+		ctx.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, hits)
+}
+
+// ReviewHit handles POST /screening/hits/:id/review
+func (c *ScreeningController) ReviewHit(ctx *gin.Context) {
+	var req screening.ReviewHitRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := c.screeningService.ReviewHit(ctx, ctx.Param("id"), req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case accessErrorStatus(err) != 0:
+			status = accessErrorStatus(err)
+		case errors.Is(err, service.ErrInvalidHitStatus):
+			status = http.StatusBadRequest
+		case errors.Is(err, service.ErrHitNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, service.ErrHitAlreadyReviewed):
+			status = http.StatusConflict
+		}
+		ctx.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
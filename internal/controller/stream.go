@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"internal-transfer-microservice/internal/domain/stream"
+	"internal-transfer-microservice/internal/service"
+)
+
+type StreamController struct {
+	streamService stream.Service
+	heartbeat     time.Duration
+}
+
+// NewStreamController creates a new StreamController sending a keepalive comment every heartbeat
+func NewStreamController(streamService stream.Service, heartbeat time.Duration) *StreamController {
+	if heartbeat <= 0 {
+		heartbeat = 15 * time.Second
+	}
+	return &StreamController{
+		streamService: streamService,
+		heartbeat:     heartbeat,
+	}
+}
+
+// StreamAccountEvents handles GET /accounts/:id/events as a Server-Sent Events stream
+func (c *StreamController) StreamAccountEvents(ctx *gin.Context) {
+	// browsers resend the last id they saw in the header; the query parameter serves other clients
+	lastEventId := ctx.GetHeader("Last-Event-ID")
+	if lastEventId == "" {
+		lastEventId = ctx.Query("last_event_id")
+	}
+
+	events, err := c.streamService.Subscribe(ctx.Request.Context(), ctx.Param("id"), lastEventId)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case accessErrorStatus(err) != 0:
+			status = accessErrorStatus(err)
+		case errors.Is(err, service.ErrInvalidLastEventId):
+			status = http.StatusBadRequest
+		case errors.Is(err, service.ErrAccountNotFound):
+			status = http.StatusNotFound
+		}
+		ctx.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+	ctx.Writer.Flush()
+
+	heartbeat := time.NewTicker(c.heartbeat)
+	defer heartbeat.Stop()
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Data)
+			return true
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+			return true
+		}
+	})
+}
@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"internal-transfer-microservice/internal/domain/webhook"
+	"internal-transfer-microservice/internal/service"
+)
+
+type WebhookController struct {
+	webhookService webhook.Service
+}
+
+// NewWebhookController creates a new WebhookController
+func NewWebhookController(webhookService webhook.Service) *WebhookController {
+	return &WebhookController{
+		webhookService: webhookService,
+	}
+}
+
+// CreateSubscription handles POST /webhooks
+func (c *WebhookController) CreateSubscription(ctx *gin.Context) {
+	var req webhook.CreateSubscriptionRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := c.webhookService.CreateSubscription(ctx, req)
+	if err != nil {
+		ctx.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// ListSubscriptions handles GET /webhooks
+func (c *WebhookController) ListSubscriptions(ctx *gin.Context) {
+	subscriptions, err := c.webhookService.ListSubscriptions(ctx)
+	if err != nil {
+		ctx.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, subscriptions)
+}
+
+// DeleteSubscription handles DELETE /webhooks/:id
+func (c *WebhookController) DeleteSubscription(ctx *gin.Context) {
+	if err := c.webhookService.DeleteSubscription(ctx, ctx.Param("id")); err != nil {
+		ctx.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListDeliveries handles GET /webhooks/:id/deliveries
+func (c *WebhookController) ListDeliveries(ctx *gin.Context) {
+	deliveries, err := c.webhookService.ListDeliveries(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deliveries)
+}
+
+// GetDelivery handles GET /webhooks/:id/deliveries/:deliveryId
+func (c *WebhookController) GetDelivery(ctx *gin.Context) {
+	delivery, err := c.webhookService.GetDelivery(ctx, ctx.Param("id"), ctx.Param("deliveryId"))
+	if err != nil {
+		ctx.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, delivery)
+}
+
+// ReplayDelivery handles POST /webhooks/:id/deliveries/:deliveryId/replay
+func (c *WebhookController) ReplayDelivery(ctx *gin.Context) {
+	delivery, err := c.webhookService.ReplayDelivery(ctx, ctx.Param("id"), ctx.Param("deliveryId"))
+	if err != nil {
+		ctx.JSON(webhookErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, delivery)
+}
+
+// webhookErrorStatus maps the errors of the webhook service to HTTP statuses
+func webhookErrorStatus(err error) int {
+	switch {
+	case accessErrorStatus(err) != 0:
+		return accessErrorStatus(err)
+	case errors.Is(err, service.ErrInvalidWebhookURL), errors.Is(err, service.ErrInvalidWebhookEvent):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrAccountNotFound),
+		errors.Is(err, service.ErrSubscriptionNotFound),
+		errors.Is(err, service.ErrDeliveryNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,15 @@
+package access
+
+import (
+	"internal-transfer-microservice/internal/auth"
+	"internal-transfer-microservice/internal/domain/account"
+)
+
+// Policy decides which accounts a principal may act on, and how
+type Policy interface {
+	// Authorize returns an error matching ErrForbidden unless principal may take action on acc. A nil acc
+	// stands for actions on no account in particular, such as reviewing held transfers.
+	Authorize(principal auth.Principal, action string, acc *account.Model) error
+	// ListableOwner returns the owner whose accounts principal may list, or an empty string when it may list them all
+	ListableOwner(principal auth.Principal) string
+}
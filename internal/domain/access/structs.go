@@ -0,0 +1,22 @@
+package access
+
+import "errors"
+
+// AdminScope lets a service principal act on every account, whoever owns it
+const AdminScope = "transfers:admin"
+
+// Actions a principal takes
+const (
+	// ActionRead reads an account, its transfers, limits and events
+	ActionRead = "read"
+	// ActionDebit moves money out of an account
+	ActionDebit = "debit"
+	// ActionManage creates accounts, changes their settings, such as the overdraft limit or whether they are
+	// frozen, and configures the webhooks of the service
+	ActionManage = "manage"
+	// ActionReview decides on the transfers and watchlist hits held for an operator
+	ActionReview = "review"
+)
+
+// ErrForbidden is returned when the principal of a request may not take the action it asks for
+var ErrForbidden = errors.New("not allowed")
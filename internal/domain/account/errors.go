@@ -0,0 +1,14 @@
+package account
+
+import "errors"
+
+var (
+	// ErrOverdraftLimitExceeded is returned when a balance update would take an account below its overdraft limit
+	ErrOverdraftLimitExceeded = errors.New("overdraft limit exceeded")
+	// ErrVersionConflict is returned when an account changed since the version the caller based its update on
+	ErrVersionConflict = errors.New("account was modified concurrently")
+	// ErrAccountFrozen is returned when a transfer touches a frozen account
+	ErrAccountFrozen = errors.New("account is frozen")
+	// ErrAccountExists is returned when an account is created with the id of an existing one
+	ErrAccountExists = errors.New("account already exists")
+)
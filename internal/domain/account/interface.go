@@ -1,16 +1,59 @@
 package account
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"internal-transfer-microservice/internal/domain/limit"
+	"internal-transfer-microservice/internal/domain/transfer"
+)
 
 type Repository interface {
 	GetAccount(ctx context.Context, accountId string) (*Model, error)
+	// ListAccounts returns up to limit accounts with an id after the given one, ordered by account id;
+	// a limit that is not positive returns them all. A non-empty ownerId only returns the accounts it owns.
+	ListAccounts(ctx context.Context, ownerId, after string, limit int) ([]Model, error)
 	UpdateAccount(ctx context.Context, account *Model) error
 	CreateAccount(ctx context.Context, account *Model) error
-	UpdateAccountsInTx(ctx context.Context, srcAccount *Model, destAccount *Model) error
+	// UpdateAccountsInTx saves the given accounts and the transfer record in a single database transaction
+	UpdateAccountsInTx(ctx context.Context, txn *transfer.Model, accounts ...*Model) error
+	GetTransferHistory(ctx context.Context, accountId string, limit int) ([]transfer.Model, error)
+	GetTransfer(ctx context.Context, transferId uuid.UUID) (*transfer.Model, error)
+	ListTransfersByStatus(ctx context.Context, statuses []string, limit int) ([]transfer.Model, error)
+	// ExpireTransfers moves transfers in one of statuses whose expiry is before the given time to expired
+	ExpireTransfers(ctx context.Context, statuses []string, before time.Time) (int64, error)
+	// GetOutgoingVelocity returns the number and total amount of completed transfers out of the account since the given time
+	GetOutgoingVelocity(ctx context.Context, accountId string, since time.Time) (int, float64, error)
+	// UpdateOverdraftLimit stores the account's new limit and version together with the audit record,
+	// failing with ErrVersionConflict if the stored version is no longer change.Version-1
+	UpdateOverdraftLimit(ctx context.Context, account *Model, change *OverdraftLimitChange) error
+	GetOverdraftLimitHistory(ctx context.Context, accountId string) ([]OverdraftLimitChange, error)
+	// SetFrozen stores whether the account is frozen, as in account.Frozen, together with the event recording
+	// who changed it and why
+	SetFrozen(ctx context.Context, account *Model, changedBy, reason string) error
 }
 
 type Service interface {
 	GetAccount(ctx context.Context, accountId string) (*GetAccountResponse, error)
-	CreateAccount(ctx context.Context, accountId string, balance float64) (ApiResponse, error)
-	TxnAccount(ctx context.Context, accountId, destinationAccountId string, amount float64) (ApiResponse, error)
+	// ListAccounts returns a page of accounts ordered by account id, starting after the given id, of those
+	// the caller may read
+	ListAccounts(ctx context.Context, after string, limit int) (*ListAccountsResponse, error)
+	CreateAccount(ctx context.Context, req CreateAccountRequest) (ApiResponse, error)
+	TxnAccount(ctx context.Context, accountId, destinationAccountId string, amount float64) (TransferResponse, error)
+	GetTransferHistory(ctx context.Context, accountId string) ([]*transfer.Response, error)
+	UpdateOverdraftLimit(ctx context.Context, accountId string, req UpdateOverdraftLimitRequest) (*GetAccountResponse, error)
+	GetOverdraftLimitHistory(ctx context.Context, accountId string) ([]OverdraftLimitChange, error)
+	GetRemainingLimits(ctx context.Context, accountId string) (*limit.Allowance, error)
+	// SetAccountFrozen freezes or unfreezes an account on behalf of an operator
+	SetAccountFrozen(ctx context.Context, accountId string, req FreezeAccountRequest) (*GetAccountResponse, error)
+
+	// ListPendingTransfers returns the transfers waiting for an operator's approval or review
+	ListPendingTransfers(ctx context.Context) ([]*transfer.Response, error)
+	// ApproveTransfer executes a pending transfer on behalf of an operator other than its initiator
+	ApproveTransfer(ctx context.Context, transferId string) (TransferResponse, error)
+	RejectTransfer(ctx context.Context, transferId string, reason string) (*transfer.Response, error)
+	// ExpirePendingTransfers expires the pending transfers nobody decided on in time
+	ExpirePendingTransfers(ctx context.Context) (int64, error)
 }
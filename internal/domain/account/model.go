@@ -2,12 +2,83 @@ package account
 
 import "internal-transfer-microservice/internal/domain"
 
+// DefaultAccountType is assigned to accounts created without an explicit type
+const DefaultAccountType = "standard"
+
+// DefaultTier is the limits tier of accounts created without an explicit tier
+const DefaultTier = "standard"
+
 type Model struct {
 	domain.Base
-	AccountId string  `json:"account_id" gorm:"uniqueIndex;"`
-	Balance   float64 `json:"balance"`
+	AccountId   string  `json:"account_id" gorm:"uniqueIndex;"`
+	HolderName  string  `json:"holder_name"`
+	OwnerId     string  `json:"owner_id" gorm:"index"`
+	Balance     float64 `json:"balance"`
+	AccountType string  `json:"account_type" gorm:"default:standard"`
+	RatePlanId  string  `json:"rate_plan_id" gorm:"index"`
+	Tier        string  `json:"tier" gorm:"default:standard"`
+	// OverdraftLimit is how far below zero the balance may go
+	OverdraftLimit        float64 `json:"overdraft_limit" gorm:"not null;default:0"`
+	OverdraftLimitVersion int     `json:"overdraft_limit_version" gorm:"not null;default:0"`
+	// Frozen accounts can neither send nor receive transfers
+	Frozen bool `json:"frozen" gorm:"not null;default:false"`
+	// Version is the number of events in the account's stream when accounts are event-sourced
+	Version int `json:"-" gorm:"not null;default:0"`
+	// balanceChange is what Credit and Debit changed the balance by since the account was read. Writes
+	// apply it to the stored balance rather than overwrite it, so no concurrent change is lost.
+	balanceChange float64
 }
 
 func (Model) TableName() string {
 	return "accounts"
 }
+
+// AvailableBalance returns the balance plus the unused overdraft
+func (m *Model) AvailableBalance() float64 {
+	return m.Balance + m.OverdraftLimit
+}
+
+// Credit adds amount to the balance
+func (m *Model) Credit(amount float64) {
+	m.Balance += amount
+	m.balanceChange += amount
+}
+
+// Debit takes amount from the balance
+func (m *Model) Debit(amount float64) {
+	m.Credit(-amount)
+}
+
+// BalanceChange returns what Credit and Debit changed the balance by since the account was read or saved
+func (m *Model) BalanceChange() float64 {
+	return m.balanceChange
+}
+
+// BalanceSaved sets the balance to the stored one, which the balance change was applied to
+func (m *Model) BalanceSaved(balance float64) {
+	m.Balance = balance
+	m.balanceChange = 0
+}
+
+// OverdraftUsed returns how far the balance is below zero
+func (m *Model) OverdraftUsed() float64 {
+	if m.Balance >= 0 {
+		return 0
+	}
+	return -m.Balance
+}
+
+// OverdraftLimitChange is the audit record of one overdraft limit version
+type OverdraftLimitChange struct {
+	domain.Base
+	AccountId     string  `json:"account_id" gorm:"uniqueIndex:idx_overdraft_limit_changes_account_version"`
+	Version       int     `json:"version" gorm:"uniqueIndex:idx_overdraft_limit_changes_account_version"`
+	PreviousLimit float64 `json:"previous_limit"`
+	NewLimit      float64 `json:"new_limit"`
+	ChangedBy     string  `json:"changed_by"`
+	Reason        string  `json:"reason"`
+}
+
+func (OverdraftLimitChange) TableName() string {
+	return "overdraft_limit_changes"
+}
@@ -1,17 +1,88 @@
 package account
 
+import (
+	"time"
+
+	"internal-transfer-microservice/internal/domain/limit"
+	"internal-transfer-microservice/internal/domain/transfer"
+)
+
 type GetAccountResponse struct {
-	AccountId string  `json:"account_id"`
-	Balance   float64 `json:"balance"`
+	AccountId   string  `json:"account_id"`
+	HolderName  string  `json:"holder_name,omitempty"`
+	OwnerId     string  `json:"owner_id,omitempty"`
+	Balance     float64 `json:"balance"`
+	AccountType string  `json:"account_type"`
+	RatePlanId  string  `json:"rate_plan_id,omitempty"`
+	Tier        string  `json:"tier"`
+
+	OverdraftLimit        float64 `json:"overdraft_limit"`
+	OverdraftLimitVersion int     `json:"overdraft_limit_version"`
+	OverdraftUsed         float64 `json:"overdraft_used"`
+	AvailableBalance      float64 `json:"available_balance"`
+	Frozen                bool    `json:"frozen,omitempty"`
+
+	// Activity is only reported when accounts are read from the read model
+	Activity *Activity `json:"activity,omitempty"`
+}
+
+// Activity summarizes the recent transfers of an account
+type Activity struct {
+	LastTransfer *LastTransfer `json:"last_transfer,omitempty"`
+	Inflow30d    float64       `json:"inflow_30d"`
+	Outflow30d   float64       `json:"outflow_30d"`
+	// AsOf is when the read model last applied a change to the account
+	AsOf time.Time `json:"as_of"`
+}
+
+type LastTransfer struct {
+	TransferId     string     `json:"transfer_id"`
+	Direction      string     `json:"direction"`
+	CounterpartyId string     `json:"counterparty_id"`
+	Amount         float64    `json:"amount"`
+	ExecutedAt     *time.Time `json:"executed_at"`
+}
+
+// FreezeAccountRequest freezes or unfreezes an account. Frozen accounts can neither send nor receive transfers.
+type FreezeAccountRequest struct {
+	Frozen bool   `json:"frozen"`
+	Reason string `json:"reason"`
+}
+
+// ListAccountsRequest is the query of a page of accounts; a zero Limit asks for the default page size
+type ListAccountsRequest struct {
+	After string `form:"after"`
+	Limit int    `form:"limit"`
+}
+
+// ListAccountsResponse is a page of accounts. NextAfter is the id to list the next page after,
+// and is empty on the last page.
+type ListAccountsResponse struct {
+	Accounts  []*GetAccountResponse `json:"accounts"`
+	NextAfter string                `json:"next_after,omitempty"`
 }
 
 type ApiResponse struct {
 	Message string `json:"message"`
 }
 
+type TransferResponse struct {
+	Message string `json:"message"`
+	// ErrorCode tells why a transfer was refused: a limit code, or one of the codes in transfer
+	ErrorCode string             `json:"error_code,omitempty"`
+	Transfer  *transfer.Response `json:"transfer,omitempty"`
+	// Remaining is the allowance left when a transfer limit was hit
+	Remaining *limit.Allowance `json:"remaining,omitempty"`
+}
+
 type CreateAccountRequest struct {
 	AccountId      string  `json:"account_id"`
+	HolderName     string  `json:"holder_name"`
+	OwnerId        string  `json:"owner_id"`
 	InitialBalance float64 `json:"initial_balance"`
+	AccountType    string  `json:"account_type"`
+	RatePlanId     string  `json:"rate_plan_id"`
+	Tier           string  `json:"tier"`
 }
 
 type TxnAccountRequest struct {
@@ -19,3 +90,10 @@ type TxnAccountRequest struct {
 	DestinationAccountId string  `json:"destination_account_id"`
 	Amount               float64 `json:"amount"`
 }
+
+type UpdateOverdraftLimitRequest struct {
+	Limit float64 `json:"limit"`
+	// ExpectedVersion optionally guards against overwriting a concurrent limit change
+	ExpectedVersion *int   `json:"expected_version"`
+	Reason          string `json:"reason"`
+}
@@ -24,7 +24,3 @@ func (b *Base) BeforeCreate(db *gorm.DB) error {
 	}
 	return nil
 }
-
-func (Base) TableName() string {
-	panic("implement me")
-}
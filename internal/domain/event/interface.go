@@ -0,0 +1,24 @@
+package event
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// ListPendingEvents returns the oldest unsent events in sequence order
+	ListPendingEvents(ctx context.Context, limit int) ([]Outbox, error)
+	MarkEventSent(ctx context.Context, eventId uuid.UUID, sentAt time.Time) error
+	// RecordEventFailure counts a failed delivery attempt of the event
+	RecordEventFailure(ctx context.Context, eventId uuid.UUID, reason string) error
+}
+
+// Relay moves recorded events from the outbox to the event publisher
+type Relay interface {
+	// RelayPending publishes pending events in order until none are left or one fails, returning how many were sent
+	RelayPending(ctx context.Context) (int, error)
+	// Run relays pending events every interval until ctx is done
+	Run(ctx context.Context) error
+}
@@ -0,0 +1,31 @@
+package event
+
+import (
+	"time"
+
+	"internal-transfer-microservice/internal/domain"
+)
+
+// Outbox is a domain event recorded in the same database transaction as the change it describes.
+// The relay publishes pending events in Sequence order and marks them sent.
+type Outbox struct {
+	domain.Base
+	Sequence      uint64 `json:"sequence" gorm:"autoIncrement;uniqueIndex"`
+	EventType     string `json:"event_type" gorm:"index"`
+	SchemaVersion int    `json:"schema_version"`
+	AggregateType string `json:"aggregate_type"`
+	AggregateId   string `json:"aggregate_id" gorm:"index"`
+	// PartitionKey is the account whose events must stay in order; publishers partition by it
+	PartitionKey string     `json:"partition_key"`
+	Payload      string     `json:"payload" gorm:"type:text"`
+	OccurredAt   time.Time  `json:"occurred_at"`
+	SentAt       *time.Time `json:"sent_at" gorm:"index"`
+	Attempts     int        `json:"attempts" gorm:"not null;default:0"`
+	LastError    string     `json:"last_error"`
+	// ProjectedAt is when the event was applied to the account read model
+	ProjectedAt *time.Time `json:"projected_at" gorm:"index"`
+}
+
+func (Outbox) TableName() string {
+	return "outbox_events"
+}
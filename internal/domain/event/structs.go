@@ -0,0 +1,121 @@
+package event
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SchemaVersion is the version of the event payloads below; it changes whenever a payload changes incompatibly
+const SchemaVersion = 1
+
+// Event types
+const (
+	TypeAccountCreated    = "AccountCreated"
+	TypeTransferCompleted = "TransferCompleted"
+	TypeTransferFailed    = "TransferFailed"
+	// TypeOverdraftLimitChanged is recorded for every new overdraft limit version of an account
+	TypeOverdraftLimitChanged = "OverdraftLimitChanged"
+	// TypeAccountFrozen and TypeAccountUnfrozen are recorded when an operator freezes or unfreezes an account
+	TypeAccountFrozen   = "AccountFrozen"
+	TypeAccountUnfrozen = "AccountUnfrozen"
+)
+
+// Aggregate types
+const (
+	AggregateAccount  = "account"
+	AggregateTransfer = "transfer"
+)
+
+// Envelope is the published form of an outbox event
+type Envelope struct {
+	EventId       string          `json:"event_id"`
+	EventType     string          `json:"event_type"`
+	SchemaVersion int             `json:"schema_version"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateId   string          `json:"aggregate_id"`
+	PartitionKey  string          `json:"partition_key"`
+	Sequence      uint64          `json:"sequence"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+type AccountCreated struct {
+	AccountId      string  `json:"account_id"`
+	HolderName     string  `json:"holder_name,omitempty"`
+	OwnerId        string  `json:"owner_id,omitempty"`
+	AccountType    string  `json:"account_type"`
+	Tier           string  `json:"tier"`
+	RatePlanId     string  `json:"rate_plan_id,omitempty"`
+	InitialBalance float64 `json:"initial_balance"`
+}
+
+type TransferCompleted struct {
+	TransferId           string     `json:"transfer_id"`
+	SourceAccountId      string     `json:"source_account_id"`
+	DestinationAccountId string     `json:"destination_account_id"`
+	Amount               float64    `json:"amount"`
+	Fee                  float64    `json:"fee"`
+	Type                 string     `json:"type"`
+	ExecutedAt           *time.Time `json:"executed_at,omitempty"`
+	// Balances are the balances after the transfer of every account it changed, the fee revenue account included
+	Balances map[string]float64 `json:"balances,omitempty"`
+}
+
+type TransferFailed struct {
+	TransferId           string  `json:"transfer_id"`
+	SourceAccountId      string  `json:"source_account_id"`
+	DestinationAccountId string  `json:"destination_account_id"`
+	Amount               float64 `json:"amount"`
+	Type                 string  `json:"type"`
+	// Status is the final status of the transfer: failed, denied, blocked, rejected or expired
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type OverdraftLimitChanged struct {
+	AccountId     string  `json:"account_id"`
+	PreviousLimit float64 `json:"previous_limit"`
+	Limit         float64 `json:"limit"`
+	Version       int     `json:"version"`
+	ChangedBy     string  `json:"changed_by,omitempty"`
+	Reason        string  `json:"reason,omitempty"`
+}
+
+// AccountFreezeChanged is the payload of AccountFrozen and AccountUnfrozen
+type AccountFreezeChanged struct {
+	AccountId string `json:"account_id"`
+	ChangedBy string `json:"changed_by,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// NewOutbox records payload as an event of the given type about an aggregate, ordered with the other events of partitionKey
+func NewOutbox(eventType, aggregateType, aggregateId, partitionKey string, payload interface{}, occurredAt time.Time) (*Outbox, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &Outbox{
+		EventType:     eventType,
+		SchemaVersion: SchemaVersion,
+		AggregateType: aggregateType,
+		AggregateId:   aggregateId,
+		PartitionKey:  partitionKey,
+		Payload:       string(encoded),
+		OccurredAt:    occurredAt,
+	}, nil
+}
+
+// ToEnvelope converts an outbox record into its published form
+func (o *Outbox) ToEnvelope() *Envelope {
+	return &Envelope{
+		EventId:       o.ID.String(),
+		EventType:     o.EventType,
+		SchemaVersion: o.SchemaVersion,
+		AggregateType: o.AggregateType,
+		AggregateId:   o.AggregateId,
+		PartitionKey:  o.PartitionKey,
+		Sequence:      o.Sequence,
+		OccurredAt:    o.OccurredAt,
+		Payload:       json.RawMessage(o.Payload),
+	}
+}
@@ -0,0 +1,124 @@
+package eventstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"internal-transfer-microservice/internal/domain/account"
+)
+
+var ErrStreamNotFound = errors.New("account has no event stream")
+
+// Apply folds event into state, which must be at the version just before it
+func Apply(state *account.Model, event *Event) error {
+	if event.Version != state.Version+1 {
+		return fmt.Errorf("event %d of account %s applied to version %d", event.Version, event.AccountId, state.Version)
+	}
+
+	data := []byte(event.Data)
+	switch event.Type {
+	case TypeOpened:
+		var opened Opened
+		if err := json.Unmarshal(data, &opened); err != nil {
+			return err
+		}
+		recordedAt := event.RecordedAt
+		state.ID = opened.Id
+		state.CreatedAt = &recordedAt
+		state.AccountId = event.AccountId
+		state.HolderName = opened.HolderName
+		state.OwnerId = opened.OwnerId
+		state.AccountType = opened.AccountType
+		state.RatePlanId = opened.RatePlanId
+		state.Tier = opened.Tier
+		state.Balance = opened.InitialBalance
+		state.OverdraftLimit = opened.OverdraftLimit
+		state.OverdraftLimitVersion = opened.OverdraftLimitVersion
+		state.Frozen = opened.Frozen
+	case TypeCredited:
+		var credited Credited
+		if err := json.Unmarshal(data, &credited); err != nil {
+			return err
+		}
+		state.Balance += credited.Amount
+	case TypeDebited:
+		var debited Debited
+		if err := json.Unmarshal(data, &debited); err != nil {
+			return err
+		}
+		state.Balance -= debited.Amount
+	case TypeFrozen:
+		state.Frozen = true
+	case TypeUnfrozen:
+		state.Frozen = false
+	case TypeOverdraftLimitChanged:
+		var changed OverdraftLimitChanged
+		if err := json.Unmarshal(data, &changed); err != nil {
+			return err
+		}
+		state.OverdraftLimit = changed.Limit
+		state.OverdraftLimitVersion = changed.LimitVersion
+	case TypeDetailsChanged:
+		var changed DetailsChanged
+		if err := json.Unmarshal(data, &changed); err != nil {
+			return err
+		}
+		state.HolderName = changed.HolderName
+		state.AccountType = changed.AccountType
+		state.RatePlanId = changed.RatePlanId
+		state.Tier = changed.Tier
+	default:
+		return fmt.Errorf("unknown account event type %q", event.Type)
+	}
+
+	recordedAt := event.RecordedAt
+	state.UpdatedAt = &recordedAt
+	state.Version = event.Version
+	return nil
+}
+
+// Rehydrate rebuilds an account from its latest snapshot, if any, and the events recorded after it
+func Rehydrate(snapshot *Snapshot, events []Event) (*account.Model, error) {
+	state := &account.Model{}
+	if snapshot != nil {
+		if err := json.Unmarshal([]byte(snapshot.State), state); err != nil {
+			return nil, err
+		}
+		state.Version = snapshot.Version
+	}
+	for i := range events {
+		if err := Apply(state, &events[i]); err != nil {
+			return nil, err
+		}
+	}
+	if state.Version == 0 {
+		return nil, ErrStreamNotFound
+	}
+	return state, nil
+}
+
+// Changes returns the events turning current into updated, with amounts and details taken from their difference
+func Changes(current, updated *account.Model) []Change {
+	var changes []Change
+	if current.HolderName != updated.HolderName || current.AccountType != updated.AccountType ||
+		current.RatePlanId != updated.RatePlanId || current.Tier != updated.Tier {
+		changes = append(changes, Change{TypeDetailsChanged, DetailsChanged{
+			HolderName:  updated.HolderName,
+			AccountType: updated.AccountType,
+			RatePlanId:  updated.RatePlanId,
+			Tier:        updated.Tier,
+		}})
+	}
+	if delta := updated.Balance - current.Balance; delta > 0 {
+		changes = append(changes, Change{TypeCredited, Credited{Amount: delta}})
+	} else if delta < 0 {
+		changes = append(changes, Change{TypeDebited, Debited{Amount: -delta}})
+	}
+	if !current.Frozen && updated.Frozen {
+		changes = append(changes, Change{TypeFrozen, Frozen{}})
+	} else if current.Frozen && !updated.Frozen {
+		changes = append(changes, Change{TypeUnfrozen, Unfrozen{}})
+	}
+	return changes
+}
@@ -0,0 +1,35 @@
+package eventstore
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event is one entry of an account's append-only stream. Version numbers the events of a stream from 1,
+// and the unique index on (account_id, version) makes concurrent appends of the same version fail.
+type Event struct {
+	ID         uuid.UUID  `json:"id" gorm:"primaryKey;type:uuid"`
+	AccountId  string     `json:"account_id" gorm:"not null;uniqueIndex:idx_account_events_stream_version"`
+	Version    int        `json:"version" gorm:"not null;uniqueIndex:idx_account_events_stream_version"`
+	Type       string     `json:"type" gorm:"not null"`
+	Data       string     `json:"data" gorm:"type:text"`
+	TransferId *uuid.UUID `json:"transfer_id,omitempty" gorm:"type:uuid;index"`
+	RecordedAt time.Time  `json:"recorded_at"`
+}
+
+func (Event) TableName() string {
+	return "account_events"
+}
+
+// Snapshot is the state of an account after the event at Version, so rehydration only replays what follows
+type Snapshot struct {
+	AccountId string    `json:"account_id" gorm:"primaryKey"`
+	Version   int       `json:"version" gorm:"not null"`
+	State     string    `json:"state" gorm:"type:text"`
+	TakenAt   time.Time `json:"taken_at"`
+}
+
+func (Snapshot) TableName() string {
+	return "account_snapshots"
+}
@@ -0,0 +1,84 @@
+package eventstore
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event types
+const (
+	TypeOpened                = "Opened"
+	TypeCredited              = "Credited"
+	TypeDebited               = "Debited"
+	TypeFrozen                = "Frozen"
+	TypeUnfrozen              = "Unfrozen"
+	TypeOverdraftLimitChanged = "OverdraftLimitChanged"
+	TypeDetailsChanged        = "DetailsChanged"
+)
+
+// Opened starts a stream with the full initial state of the account
+type Opened struct {
+	Id                    uuid.UUID `json:"id"`
+	HolderName            string    `json:"holder_name,omitempty"`
+	OwnerId               string    `json:"owner_id,omitempty"`
+	AccountType           string    `json:"account_type"`
+	RatePlanId            string    `json:"rate_plan_id,omitempty"`
+	Tier                  string    `json:"tier"`
+	InitialBalance        float64   `json:"initial_balance"`
+	OverdraftLimit        float64   `json:"overdraft_limit,omitempty"`
+	OverdraftLimitVersion int       `json:"overdraft_limit_version,omitempty"`
+	Frozen                bool      `json:"frozen,omitempty"`
+}
+
+// Credited and Debited move the balance by Amount, which is always positive
+type Credited struct {
+	Amount float64 `json:"amount"`
+}
+
+type Debited struct {
+	Amount float64 `json:"amount"`
+}
+
+type Frozen struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+type Unfrozen struct{}
+
+type OverdraftLimitChanged struct {
+	PreviousLimit float64 `json:"previous_limit"`
+	Limit         float64 `json:"limit"`
+	LimitVersion  int     `json:"limit_version"`
+}
+
+// DetailsChanged carries the descriptive fields of the account as they are after the change
+type DetailsChanged struct {
+	HolderName  string `json:"holder_name,omitempty"`
+	AccountType string `json:"account_type"`
+	RatePlanId  string `json:"rate_plan_id,omitempty"`
+	Tier        string `json:"tier"`
+}
+
+// Change is an event not yet encoded or numbered
+type Change struct {
+	Type string
+	Data interface{}
+}
+
+// NewEvent encodes data as an event of the given type, to be numbered when it is appended
+func NewEvent(accountId, eventType string, data interface{}, transferId *uuid.UUID, recordedAt time.Time) (*Event, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return &Event{
+		ID:         uuid.New(),
+		AccountId:  accountId,
+		Type:       eventType,
+		Data:       string(encoded),
+		TransferId: transferId,
+		RecordedAt: recordedAt,
+	}, nil
+}
@@ -0,0 +1,11 @@
+package fee
+
+// Engine computes the fee charged on a transfer
+type Engine interface {
+	// Calculate returns the fee breakdown for transferring amount out of an account of the given type.
+	// A nil breakdown means no fee applies.
+	Calculate(accountType string, amount float64) *Breakdown
+
+	// RevenueAccountId returns the account that collected fees are posted to
+	RevenueAccountId() string
+}
@@ -0,0 +1,26 @@
+package fee
+
+// Fee schedule kinds
+const (
+	KindFlat       = "flat"
+	KindPercentage = "percentage"
+	KindTiered     = "tiered"
+)
+
+// Cap markers reported when a min/max cap changed the computed fee
+const (
+	CapMin = "min"
+	CapMax = "max"
+)
+
+// Breakdown describes how the fee on a transfer was computed
+type Breakdown struct {
+	AccountType string  `json:"account_type"`
+	Kind        string  `json:"kind"`
+	Tier        int     `json:"tier,omitempty"`
+	Flat        float64 `json:"flat"`
+	Rate        float64 `json:"rate"`
+	Variable    float64 `json:"variable"`
+	CapApplied  string  `json:"cap_applied,omitempty"`
+	Total       float64 `json:"total"`
+}
@@ -0,0 +1,32 @@
+package interest
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/transfer"
+)
+
+type Repository interface {
+	// ListInterestBearingAccounts returns the accounts attached to a rate plan
+	ListInterestBearingAccounts(ctx context.Context) ([]account.Model, error)
+	// ListTransfersSince returns the completed transfers to or from the account executed at or after since, and with
+	// withFees every other completed transfer charging a fee too
+	ListTransfersSince(ctx context.Context, accountId string, since time.Time, withFees bool) ([]transfer.Model, error)
+	// CreateAccrual stores the accrual unless one exists for the same account and day, reporting whether it was created
+	CreateAccrual(ctx context.Context, accrual *Accrual) (bool, error)
+	// GetUnpostedAccruals returns accruals dated up to and including through that have not been posted yet, grouped by account
+	GetUnpostedAccruals(ctx context.Context, through string) (map[string][]Accrual, error)
+	// PostAccruals saves the accounts, records the interest transfer and marks the accruals posted in a single database transaction
+	PostAccruals(ctx context.Context, txn *transfer.Model, accrualIds []uuid.UUID, accounts ...*account.Model) error
+}
+
+type Service interface {
+	// AccrueDaily accrues one day of interest on every interest-bearing account
+	AccrueDaily(ctx context.Context, date time.Time) (*AccrualSummary, error)
+	// PostMonthly transfers the interest accrued during month from the funding account
+	PostMonthly(ctx context.Context, month time.Time) (*PostingSummary, error)
+}
@@ -0,0 +1,25 @@
+package interest
+
+import (
+	"github.com/google/uuid"
+
+	"internal-transfer-microservice/internal/domain"
+)
+
+// Accrual is the interest earned by one account over one day.
+// The (account_id, accrual_date) index makes the daily accrual job idempotent.
+type Accrual struct {
+	domain.Base
+	AccountId   string     `json:"account_id" gorm:"uniqueIndex:idx_interest_accruals_account_date"`
+	AccrualDate string     `json:"accrual_date" gorm:"uniqueIndex:idx_interest_accruals_account_date;size:10"`
+	RatePlanId  string     `json:"rate_plan_id"`
+	AnnualRate  float64    `json:"annual_rate"`
+	DayCount    string     `json:"day_count"`
+	Balance     float64    `json:"balance"`
+	Amount      float64    `json:"amount"`
+	TransferId  *uuid.UUID `json:"transfer_id" gorm:"type:uuid;index"`
+}
+
+func (Accrual) TableName() string {
+	return "interest_accruals"
+}
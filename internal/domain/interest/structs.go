@@ -0,0 +1,37 @@
+package interest
+
+// Day-count conventions
+const (
+	DayCountActual365 = "ACT/365"
+	DayCountActual360 = "ACT/360"
+	DayCountActualAct = "ACT/ACT"
+	DayCount30360     = "30/360"
+)
+
+// DateLayout is the format of accrual dates
+const DateLayout = "2006-01-02"
+
+// MonthLayout is the format of posting months
+const MonthLayout = "2006-01"
+
+// RatePlan is an annual interest rate accounts can be attached to
+type RatePlan struct {
+	Id         string  `json:"id"`
+	AnnualRate float64 `json:"annual_rate"`
+	DayCount   string  `json:"day_count"`
+}
+
+type AccrualSummary struct {
+	Date     string  `json:"date"`
+	Accrued  int     `json:"accrued"`
+	Existing int     `json:"existing"`
+	Skipped  int     `json:"skipped"`
+	Total    float64 `json:"total"`
+}
+
+type PostingSummary struct {
+	Month  string  `json:"month"`
+	Posted int     `json:"posted"`
+	Failed int     `json:"failed"`
+	Total  float64 `json:"total"`
+}
@@ -0,0 +1,21 @@
+package limit
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// GetCounters returns the account's counters for the given periods, keyed by period
+	GetCounters(ctx context.Context, accountId string, periods ...string) (map[string]Counter, error)
+}
+
+// Engine evaluates the velocity and transfer limits of an account's tier
+type Engine interface {
+	// Check returns an *ExceededError if transferring amount out of the account at the given time breaches a limit of its tier
+	Check(ctx context.Context, accountId, tier string, amount float64, at time.Time) error
+	// Remaining returns what the account may still transfer out in the periods covering at
+	Remaining(ctx context.Context, accountId, tier string, at time.Time) (*Allowance, error)
+	// Caps returns the period limits of the tier, within which the counters of a transfer are incremented
+	Caps(tier string) Caps
+}
@@ -0,0 +1,31 @@
+package limit
+
+import (
+	"time"
+
+	"internal-transfer-microservice/internal/domain"
+)
+
+// Counter accumulates the outgoing transfers of one account over one period.
+// Counters are incremented in the same database transaction that records the transfer.
+type Counter struct {
+	domain.Base
+	AccountId string  `json:"account_id" gorm:"uniqueIndex:idx_transfer_limit_counters_account_period"`
+	Period    string  `json:"period" gorm:"uniqueIndex:idx_transfer_limit_counters_account_period;size:16"`
+	Amount    float64 `json:"amount" gorm:"not null;default:0"`
+	Count     int     `json:"count" gorm:"not null;default:0"`
+}
+
+func (Counter) TableName() string {
+	return "transfer_limit_counters"
+}
+
+// DailyPeriod returns the key of the daily counter covering t
+func DailyPeriod(t time.Time) string {
+	return "day:" + t.UTC().Format("2006-01-02")
+}
+
+// MonthlyPeriod returns the key of the monthly counter covering t
+func MonthlyPeriod(t time.Time) string {
+	return "month:" + t.UTC().Format("2006-01")
+}
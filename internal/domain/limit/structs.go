@@ -0,0 +1,40 @@
+package limit
+
+import "fmt"
+
+// Error codes reported when a transfer exceeds a limit
+const (
+	CodePerTransferMax = "LIMIT_PER_TRANSFER_MAX"
+	CodeDailyAmount    = "LIMIT_DAILY_AMOUNT"
+	CodeMonthlyAmount  = "LIMIT_MONTHLY_AMOUNT"
+	CodeDailyCount     = "LIMIT_DAILY_COUNT"
+	CodeMonthlyCount   = "LIMIT_MONTHLY_COUNT"
+)
+
+// Allowance is what an account may still transfer out; nil fields are not limited
+type Allowance struct {
+	Tier           string   `json:"tier"`
+	PerTransferMax *float64 `json:"per_transfer_max,omitempty"`
+	DailyAmount    *float64 `json:"daily_amount,omitempty"`
+	MonthlyAmount  *float64 `json:"monthly_amount,omitempty"`
+	DailyCount     *int     `json:"daily_count,omitempty"`
+	MonthlyCount   *int     `json:"monthly_count,omitempty"`
+}
+
+// Caps are the amounts and counts an account may transfer out per period; zero fields are not limited
+type Caps struct {
+	DailyAmount   float64
+	MonthlyAmount float64
+	DailyCount    int
+	MonthlyCount  int
+}
+
+// ExceededError reports the limit a transfer would breach together with the remaining allowance
+type ExceededError struct {
+	Code      string
+	Remaining *Allowance
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("transfer limit exceeded: %s", e.Code)
+}
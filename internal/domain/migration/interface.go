@@ -0,0 +1,31 @@
+package migration
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// Lock waits until no other migrator holds the migration lock and takes it; release gives it back
+	Lock(ctx context.Context) (release func(), err error)
+	// EnsureTable creates the schema_migrations table if it does not exist
+	EnsureTable(ctx context.Context) error
+	// ListApplied returns the applied migrations, ordered by version
+	ListApplied(ctx context.Context) ([]Applied, error)
+	// Apply runs the up script of the migration and records it in a single database transaction
+	Apply(ctx context.Context, migration Migration, appliedAt time.Time) error
+	// Revert runs the down script of the migration and removes its record in a single database transaction
+	Revert(ctx context.Context, migration Migration) error
+}
+
+// Service applies and reverts the migrations in order, one migrator at a time
+type Service interface {
+	// Up applies every pending migration
+	Up(ctx context.Context) ([]Step, error)
+	// Down reverts the last steps applied migrations
+	Down(ctx context.Context, steps int) ([]Step, error)
+	// To applies or reverts migrations until version is the last one applied; version 0 reverts them all
+	To(ctx context.Context, version int) ([]Step, error)
+	// Status returns every known or applied migration and whether it is applied
+	Status(ctx context.Context) ([]Status, error)
+}
@@ -0,0 +1,15 @@
+package migration
+
+import "time"
+
+// Applied records a migration applied to the database, with the checksum of its up script at the time
+type Applied struct {
+	Version   int       `json:"version" gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `json:"name" gorm:"not null"`
+	Checksum  string    `json:"checksum" gorm:"not null"`
+	AppliedAt time.Time `json:"applied_at" gorm:"not null"`
+}
+
+func (Applied) TableName() string {
+	return "schema_migrations"
+}
@@ -0,0 +1,33 @@
+package readmodel
+
+import (
+	"context"
+	"time"
+
+	"internal-transfer-microservice/internal/domain/event"
+)
+
+type Repository interface {
+	// GetSummary returns the summary of the account, or ErrSummaryNotFound when the read model does not have it yet
+	GetSummary(ctx context.Context, accountId string) (*AccountSummary, error)
+	// GetFlowTotals returns the transfers into and out of the account on the days from since (YYYY-MM-DD) onwards
+	GetFlowTotals(ctx context.Context, accountId string, since string) (float64, float64, error)
+	// ListUnprojectedEvents returns the oldest outbox events not yet applied to the read model, in sequence order
+	ListUnprojectedEvents(ctx context.Context, limit int) ([]event.Outbox, error)
+	// ApplyProjection applies the projection and marks its event projected in a single database transaction,
+	// doing nothing when the event was projected already
+	ApplyProjection(ctx context.Context, projection *Projection, projectedAt time.Time) error
+	// Rebuild replaces the read model with one built from the accounts and transfers, with the daily flows
+	// from since onwards, and marks every event it covers projected. It returns the number of summaries written.
+	Rebuild(ctx context.Context, since string, rebuiltAt time.Time) (int, error)
+}
+
+// Projector keeps the read model up to date with the outbox
+type Projector interface {
+	// ProjectPending applies unprojected events in order until none are left or one fails, returning how many were applied
+	ProjectPending(ctx context.Context) (int, error)
+	// Run projects pending events every interval until ctx is done
+	Run(ctx context.Context) error
+	// Rebuild rebuilds the read model from the accounts and transfers tables
+	Rebuild(ctx context.Context) (int, error)
+}
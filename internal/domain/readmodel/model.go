@@ -0,0 +1,99 @@
+package readmodel
+
+import (
+	"time"
+
+	"internal-transfer-microservice/internal/domain/account"
+)
+
+// AccountSummary is the denormalized view of an account that account reads are served from.
+// It is kept up to date by projecting the outbox events, so it trails the accounts table by the projector's lag.
+type AccountSummary struct {
+	AccountId   string  `json:"account_id" gorm:"primaryKey"`
+	HolderName  string  `json:"holder_name"`
+	OwnerId     string  `json:"owner_id"`
+	AccountType string  `json:"account_type"`
+	RatePlanId  string  `json:"rate_plan_id"`
+	Tier        string  `json:"tier"`
+	Balance     float64 `json:"balance"`
+	// BalanceSequence is the outbox sequence of the event Balance comes from; events before it leave Balance alone
+	BalanceSequence       uint64  `json:"balance_sequence" gorm:"not null;default:0"`
+	OverdraftLimit        float64 `json:"overdraft_limit" gorm:"not null;default:0"`
+	OverdraftLimitVersion int     `json:"overdraft_limit_version" gorm:"not null;default:0"`
+	Frozen                bool    `json:"frozen" gorm:"not null;default:false"`
+
+	LastTransferId           string     `json:"last_transfer_id"`
+	LastTransferDirection    string     `json:"last_transfer_direction"`
+	LastTransferCounterparty string     `json:"last_transfer_counterparty"`
+	LastTransferAmount       float64    `json:"last_transfer_amount"`
+	LastTransferAt           *time.Time `json:"last_transfer_at"`
+	UpdatedAt                time.Time  `json:"updated_at"`
+}
+
+func (AccountSummary) TableName() string {
+	return "account_summaries"
+}
+
+// DailyFlow totals the completed transfers into and out of an account on one UTC day
+type DailyFlow struct {
+	AccountId string  `json:"account_id" gorm:"primaryKey"`
+	Day       string  `json:"day" gorm:"primaryKey"`
+	Inflow    float64 `json:"inflow" gorm:"not null;default:0"`
+	Outflow   float64 `json:"outflow" gorm:"not null;default:0"`
+}
+
+func (DailyFlow) TableName() string {
+	return "account_daily_flows"
+}
+
+// ApplyBalance sets the balance reported by the event at sequence, unless a later event already set it
+func (s *AccountSummary) ApplyBalance(balance float64, sequence uint64) {
+	if sequence < s.BalanceSequence {
+		return
+	}
+	s.Balance = balance
+	s.BalanceSequence = sequence
+}
+
+// ApplyOverdraftLimit sets a new overdraft limit, unless a later version is already recorded
+func (s *AccountSummary) ApplyOverdraftLimit(limit float64, version int) {
+	if version < s.OverdraftLimitVersion {
+		return
+	}
+	s.OverdraftLimit = limit
+	s.OverdraftLimitVersion = version
+}
+
+// RecordTransfer makes activity the last transfer of the account, unless a later transfer is already recorded
+func (s *AccountSummary) RecordTransfer(activity *TransferActivity) {
+	if s.LastTransferAt != nil && s.LastTransferAt.After(activity.ExecutedAt) {
+		return
+	}
+	executedAt := activity.ExecutedAt
+	s.LastTransferId = activity.TransferId
+	s.LastTransferAmount = activity.Amount
+	s.LastTransferAt = &executedAt
+	if activity.SourceAccountId == s.AccountId {
+		s.LastTransferDirection = DirectionOut
+		s.LastTransferCounterparty = activity.DestinationAccountId
+	} else {
+		s.LastTransferDirection = DirectionIn
+		s.LastTransferCounterparty = activity.SourceAccountId
+	}
+}
+
+// Account returns the account state held by the summary
+func (s *AccountSummary) Account() *account.Model {
+	return &account.Model{
+		AccountId:             s.AccountId,
+		HolderName:            s.HolderName,
+		OwnerId:               s.OwnerId,
+		Balance:               s.Balance,
+		AccountType:           s.AccountType,
+		RatePlanId:            s.RatePlanId,
+		Tier:                  s.Tier,
+		OverdraftLimit:        s.OverdraftLimit,
+		OverdraftLimitVersion: s.OverdraftLimitVersion,
+		Frozen:                s.Frozen,
+	}
+}
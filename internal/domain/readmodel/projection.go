@@ -0,0 +1,129 @@
+package readmodel
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"internal-transfer-microservice/internal/domain/event"
+)
+
+var ErrSummaryNotFound = errors.New("account summary not found")
+
+// Project returns what the outbox event changes in the read model.
+// Events the read model does not follow yield an empty projection, so they are still marked projected.
+func Project(outbox *event.Outbox) (*Projection, error) {
+	projection := &Projection{EventId: outbox.ID, Sequence: outbox.Sequence}
+	payload := []byte(outbox.Payload)
+
+	switch outbox.EventType {
+	case event.TypeAccountCreated:
+		var created event.AccountCreated
+		if err := json.Unmarshal(payload, &created); err != nil {
+			return nil, err
+		}
+		projection.Opened = &AccountSummary{
+			AccountId:       created.AccountId,
+			HolderName:      created.HolderName,
+			OwnerId:         created.OwnerId,
+			AccountType:     created.AccountType,
+			RatePlanId:      created.RatePlanId,
+			Tier:            created.Tier,
+			Balance:         created.InitialBalance,
+			BalanceSequence: outbox.Sequence,
+		}
+	case event.TypeTransferCompleted:
+		var completed event.TransferCompleted
+		if err := json.Unmarshal(payload, &completed); err != nil {
+			return nil, err
+		}
+		executedAt := outbox.OccurredAt
+		if completed.ExecutedAt != nil {
+			executedAt = *completed.ExecutedAt
+		}
+		projection.Balances = completed.Balances
+		projection.Transfer = &TransferActivity{
+			TransferId:           completed.TransferId,
+			SourceAccountId:      completed.SourceAccountId,
+			DestinationAccountId: completed.DestinationAccountId,
+			Amount:               completed.Amount,
+			Fee:                  completed.Fee,
+			ExecutedAt:           executedAt.UTC(),
+		}
+	case event.TypeOverdraftLimitChanged:
+		var changed event.OverdraftLimitChanged
+		if err := json.Unmarshal(payload, &changed); err != nil {
+			return nil, err
+		}
+		projection.Overdraft = &OverdraftLimit{
+			AccountId: changed.AccountId,
+			Limit:     changed.Limit,
+			Version:   changed.Version,
+		}
+	case event.TypeAccountFrozen, event.TypeAccountUnfrozen:
+		var changed event.AccountFreezeChanged
+		if err := json.Unmarshal(payload, &changed); err != nil {
+			return nil, err
+		}
+		projection.Freeze = &Freeze{
+			AccountId: changed.AccountId,
+			Frozen:    outbox.EventType == event.TypeAccountFrozen,
+		}
+	}
+	return projection, nil
+}
+
+// AccountIds returns the accounts whose summary the projection changes
+func (p *Projection) AccountIds() []string {
+	seen := make(map[string]bool)
+	var ids []string
+	add := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for id := range p.Balances {
+		add(id)
+	}
+	if p.Overdraft != nil {
+		add(p.Overdraft.AccountId)
+	}
+	if p.Freeze != nil {
+		add(p.Freeze.AccountId)
+	}
+	if p.Transfer != nil {
+		add(p.Transfer.SourceAccountId)
+		add(p.Transfer.DestinationAccountId)
+	}
+	return ids
+}
+
+// Apply changes the summaries of the accounts the projection touches, keyed by account id
+func (p *Projection) Apply(summaries map[string]*AccountSummary, appliedAt time.Time) {
+	for id, balance := range p.Balances {
+		if summary, ok := summaries[id]; ok {
+			summary.ApplyBalance(balance, p.Sequence)
+		}
+	}
+	if p.Overdraft != nil {
+		if summary, ok := summaries[p.Overdraft.AccountId]; ok {
+			summary.ApplyOverdraftLimit(p.Overdraft.Limit, p.Overdraft.Version)
+		}
+	}
+	if p.Freeze != nil {
+		if summary, ok := summaries[p.Freeze.AccountId]; ok {
+			summary.Frozen = p.Freeze.Frozen
+		}
+	}
+	if p.Transfer != nil {
+		for _, id := range []string{p.Transfer.SourceAccountId, p.Transfer.DestinationAccountId} {
+			if summary, ok := summaries[id]; ok {
+				summary.RecordTransfer(p.Transfer)
+			}
+		}
+	}
+	for _, summary := range summaries {
+		summary.UpdatedAt = appliedAt
+	}
+}
@@ -0,0 +1,67 @@
+package readmodel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Directions of an account's last transfer
+const (
+	DirectionIn  = "in"
+	DirectionOut = "out"
+)
+
+// FlowWindowDays is how many days, today included, the inflow and outflow totals of a summary cover
+const FlowWindowDays = 30
+
+// Day returns the UTC day of t that daily flows are bucketed by
+func Day(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+// Projection is what one outbox event changes in the read model
+type Projection struct {
+	EventId  uuid.UUID
+	Sequence uint64
+	// Opened is the summary of the account the event created
+	Opened *AccountSummary
+	// Balances are the balances after the event of the accounts it changed
+	Balances  map[string]float64
+	Overdraft *OverdraftLimit
+	Freeze    *Freeze
+	Transfer  *TransferActivity
+}
+
+// Freeze is an account being frozen or unfrozen
+type Freeze struct {
+	AccountId string
+	Frozen    bool
+}
+
+// OverdraftLimit is a new overdraft limit version of an account
+type OverdraftLimit struct {
+	AccountId string
+	Limit     float64
+	Version   int
+}
+
+// TransferActivity is a completed transfer as the read model records it
+type TransferActivity struct {
+	TransferId           string
+	SourceAccountId      string
+	DestinationAccountId string
+	Amount               float64
+	Fee                  float64
+	ExecutedAt           time.Time
+}
+
+// Flows returns the daily flows the transfer adds to its source and destination accounts.
+// The source's outflow includes the fee it paid.
+func (t *TransferActivity) Flows() []DailyFlow {
+	day := Day(t.ExecutedAt)
+	return []DailyFlow{
+		{AccountId: t.SourceAccountId, Day: day, Outflow: t.Amount + t.Fee},
+		{AccountId: t.DestinationAccountId, Day: day, Inflow: t.Amount},
+	}
+}
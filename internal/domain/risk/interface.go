@@ -0,0 +1,9 @@
+package risk
+
+import "context"
+
+// RiskEvaluator screens a transfer before it executes
+type RiskEvaluator interface {
+	// Evaluate decides whether the transfer may proceed, must be denied or needs manual review
+	Evaluate(ctx context.Context, transfer TransferContext) (*Decision, error)
+}
@@ -0,0 +1,51 @@
+package risk
+
+import "time"
+
+// Decision outcomes, ordered from least to most severe
+const (
+	OutcomeAllow  = "allow"
+	OutcomeReview = "review"
+	OutcomeDeny   = "deny"
+)
+
+// Rule types understood by the built-in rules engine
+const (
+	RuleAmountAbove    = "amount_above"
+	RuleNewAccount     = "new_account"
+	RuleVelocityCount  = "velocity_count"
+	RuleVelocityAmount = "velocity_amount"
+	RuleBlocklist      = "blocklist"
+)
+
+// TransferContext is what a RiskEvaluator knows about the transfer being screened
+type TransferContext struct {
+	SourceAccountId       string
+	DestinationAccountId  string
+	Amount                float64
+	Fee                   float64
+	SourceAccountAge      time.Duration
+	DestinationAccountAge time.Duration
+	// RecentCount and RecentAmount are the source account's completed outgoing transfers within the velocity window
+	RecentCount  int
+	RecentAmount float64
+}
+
+// Decision is the outcome of screening a transfer, stored with the transfer record
+type Decision struct {
+	Outcome        string    `json:"outcome"`
+	TriggeredRules []string  `json:"triggered_rules,omitempty"`
+	EvaluatedAt    time.Time `json:"evaluated_at"`
+}
+
+// Severity ranks an outcome so the most severe triggered rule wins
+func Severity(outcome string) int {
+	switch outcome {
+	case OutcomeDeny:
+		return 2
+	case OutcomeReview:
+		return 1
+	default:
+		return 0
+	}
+}
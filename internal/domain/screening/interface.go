@@ -0,0 +1,32 @@
+package screening
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	// ReplaceWatchlist swaps the stored watchlist for entries in one transaction
+	ReplaceWatchlist(ctx context.Context, entries []WatchlistEntry) error
+	ListWatchlist(ctx context.Context) ([]WatchlistEntry, error)
+	CreateHits(ctx context.Context, hits []Hit) error
+	// ListHits returns the most recent hits, only those with the given status unless it is empty
+	ListHits(ctx context.Context, status string, limit int) ([]Hit, error)
+	GetHit(ctx context.Context, hitId uuid.UUID) (*Hit, error)
+	UpdateHit(ctx context.Context, hit *Hit) error
+}
+
+// Screener checks the parties of a transfer against the watchlist
+type Screener interface {
+	// Screen returns the watchlist matches of parties, recording each one as a hit of the transfer
+	Screen(ctx context.Context, transferId uuid.UUID, parties ...Party) ([]Match, error)
+}
+
+type Service interface {
+	Screener
+	// ReloadWatchlist replaces the watchlist with the entries of the file at path
+	ReloadWatchlist(ctx context.Context, path string) (*ReloadSummary, error)
+	ListHits(ctx context.Context, status string) ([]*HitResponse, error)
+	ReviewHit(ctx context.Context, hitId string, req ReviewHitRequest) (*HitResponse, error)
+}
@@ -0,0 +1,52 @@
+package screening
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"internal-transfer-microservice/internal/domain"
+)
+
+// Hit review statuses
+const (
+	HitStatusOpen      = "open"
+	HitStatusConfirmed = "confirmed"
+	HitStatusCleared   = "cleared"
+)
+
+// WatchlistEntry is one sanctioned party of the loaded watchlist
+type WatchlistEntry struct {
+	domain.Base
+	EntryId  string   `json:"entry_id" gorm:"index"`
+	Name     string   `json:"name"`
+	Aliases  []string `json:"aliases" gorm:"serializer:json"`
+	Type     string   `json:"type"`
+	Programs []string `json:"programs" gorm:"serializer:json"`
+	Source   string   `json:"source"`
+}
+
+func (WatchlistEntry) TableName() string {
+	return "watchlist_entries"
+}
+
+// Hit records a party of a transfer whose name matched a watchlist entry
+type Hit struct {
+	domain.Base
+	TransferId   uuid.UUID  `json:"transfer_id" gorm:"type:uuid;index"`
+	AccountId    string     `json:"account_id" gorm:"index"`
+	ScreenedName string     `json:"screened_name"`
+	EntryId      string     `json:"entry_id"`
+	MatchedName  string     `json:"matched_name"`
+	Programs     []string   `json:"programs" gorm:"serializer:json"`
+	Score        float64    `json:"score"`
+	Action       string     `json:"action"`
+	Status       string     `json:"status" gorm:"index;default:open"`
+	ReviewedBy   string     `json:"reviewed_by"`
+	ReviewedAt   *time.Time `json:"reviewed_at"`
+	ReviewNote   string     `json:"review_note"`
+}
+
+func (Hit) TableName() string {
+	return "screening_hits"
+}
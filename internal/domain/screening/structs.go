@@ -0,0 +1,98 @@
+package screening
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Match actions, ordered from least to most severe
+const (
+	ActionNone  = ""
+	ActionFlag  = "flag"
+	ActionBlock = "block"
+)
+
+// Party is an account holder taking part in a transfer
+type Party struct {
+	AccountId string
+	Name      string
+}
+
+// Match is a party whose name is close enough to a watchlist entry to act on
+type Match struct {
+	AccountId    string
+	ScreenedName string
+	Entry        *WatchlistEntry
+	MatchedName  string
+	Score        float64
+	Action       string
+}
+
+// StrongestAction returns the most severe action among matches
+func StrongestAction(matches []Match) string {
+	action := ActionNone
+	for _, match := range matches {
+		if match.Action == ActionBlock {
+			return ActionBlock
+		}
+		if match.Action == ActionFlag {
+			action = ActionFlag
+		}
+	}
+	return action
+}
+
+// ReloadSummary reports the outcome of a watchlist reload
+type ReloadSummary struct {
+	Source  string `json:"source"`
+	Entries int    `json:"entries"`
+	Version string `json:"version"`
+}
+
+type HitResponse struct {
+	HitId        string     `json:"hit_id"`
+	TransferId   string     `json:"transfer_id"`
+	AccountId    string     `json:"account_id"`
+	ScreenedName string     `json:"screened_name"`
+	EntryId      string     `json:"entry_id"`
+	MatchedName  string     `json:"matched_name"`
+	Programs     []string   `json:"programs,omitempty"`
+	Score        float64    `json:"score"`
+	Action       string     `json:"action"`
+	Status       string     `json:"status"`
+	ReviewedBy   string     `json:"reviewed_by,omitempty"`
+	ReviewedAt   *time.Time `json:"reviewed_at,omitempty"`
+	ReviewNote   string     `json:"review_note,omitempty"`
+	CreatedAt    *time.Time `json:"created_at,omitempty"`
+}
+
+type ReviewHitRequest struct {
+	// Status is the disposition of the hit, confirmed for a true match or cleared for a false positive
+	Status string `json:"status" binding:"required"`
+	Note   string `json:"note"`
+}
+
+// ToResponse converts a hit into its API representation
+func (h *Hit) ToResponse() *HitResponse {
+	transferId := ""
+	if h.TransferId != uuid.Nil {
+		transferId = h.TransferId.String()
+	}
+	return &HitResponse{
+		HitId:        h.ID.String(),
+		TransferId:   transferId,
+		AccountId:    h.AccountId,
+		ScreenedName: h.ScreenedName,
+		EntryId:      h.EntryId,
+		MatchedName:  h.MatchedName,
+		Programs:     h.Programs,
+		Score:        h.Score,
+		Action:       h.Action,
+		Status:       h.Status,
+		ReviewedBy:   h.ReviewedBy,
+		ReviewedAt:   h.ReviewedAt,
+		ReviewNote:   h.ReviewNote,
+		CreatedAt:    h.CreatedAt,
+	}
+}
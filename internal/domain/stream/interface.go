@@ -0,0 +1,18 @@
+package stream
+
+import (
+	"context"
+)
+
+// Notifier publishes the changes of an account to its event stream
+type Notifier interface {
+	// Notify publishes data as an event of the given type on the stream of accountId
+	Notify(ctx context.Context, accountId, eventType string, data interface{}) error
+}
+
+type Service interface {
+	Notifier
+	// Subscribe streams the events of an account until ctx is done, first replaying those published
+	// after lastEventId when it is set
+	Subscribe(ctx context.Context, accountId, lastEventId string) (<-chan Event, error)
+}
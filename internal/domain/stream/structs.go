@@ -0,0 +1,30 @@
+package stream
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event types
+const (
+	EventBalance  = "balance"
+	EventTransfer = "transfer"
+	// EventReset tells a resuming client that events were dropped from the backlog, so it must refetch the account
+	EventReset = "reset"
+)
+
+// Event is one event of an account stream; ID is sent as the SSE id clients resume from
+type Event struct {
+	ID   uint64          `json:"id"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// BalanceChanged is the data of a balance event
+type BalanceChanged struct {
+	AccountId        string    `json:"account_id"`
+	Balance          float64   `json:"balance"`
+	AvailableBalance float64   `json:"available_balance"`
+	TransferId       string    `json:"transfer_id,omitempty"`
+	ChangedAt        time.Time `json:"changed_at"`
+}
@@ -0,0 +1,82 @@
+package transfer
+
+import (
+	"time"
+
+	"internal-transfer-microservice/internal/domain"
+	"internal-transfer-microservice/internal/domain/fee"
+	"internal-transfer-microservice/internal/domain/limit"
+	"internal-transfer-microservice/internal/domain/risk"
+)
+
+// Transfer statuses
+const (
+	StatusCompleted       = "completed"
+	StatusPendingReview   = "pending_review"
+	StatusPendingApproval = "pending_approval"
+	StatusDenied          = "denied"
+	StatusBlocked         = "blocked"
+	StatusRejected        = "rejected"
+	StatusExpired         = "expired"
+	StatusFailed          = "failed"
+)
+
+// Error codes of refused transfers, reported next to the codes of the limits in limit
+const (
+	CodeInsufficientFunds = "INSUFFICIENT_FUNDS"
+	CodeAccountNotFound   = "ACCOUNT_NOT_FOUND"
+	CodeAccountFrozen     = "ACCOUNT_FROZEN"
+	CodeDenied            = "TRANSFER_DENIED"
+	CodeBlocked           = "TRANSFER_BLOCKED"
+)
+
+// PendingStatuses are the statuses of transfers waiting for an operator decision
+var PendingStatuses = []string{StatusPendingReview, StatusPendingApproval}
+
+// FailedStatuses are the final statuses of transfers that moved no money
+var FailedStatuses = []string{StatusFailed, StatusDenied, StatusBlocked, StatusRejected, StatusExpired}
+
+// Transfer types
+const (
+	TypeTransfer = "transfer"
+	TypeInterest = "interest"
+)
+
+type Model struct {
+	domain.Base
+	SourceAccountId      string         `json:"source_account_id" gorm:"index"`
+	DestinationAccountId string         `json:"destination_account_id" gorm:"index"`
+	Amount               float64        `json:"amount"`
+	Fee                  float64        `json:"fee"`
+	FeeBreakdown         *fee.Breakdown `json:"fee_breakdown" gorm:"serializer:json"`
+	Type                 string         `json:"type" gorm:"default:transfer"`
+	Status               string         `json:"status" gorm:"index"`
+	RiskDecision         *risk.Decision `json:"risk_decision" gorm:"serializer:json"`
+	FailureReason        string         `json:"failure_reason"`
+	InitiatedBy          string         `json:"initiated_by"`
+	ReviewedBy           string         `json:"reviewed_by"`
+	ReviewedAt           *time.Time     `json:"reviewed_at"`
+	ExpiresAt            *time.Time     `json:"expires_at"`
+	ExecutedAt           *time.Time     `json:"executed_at"`
+	// LimitCaps bound the limit counters of the source account as the transfer is recorded; they are not stored
+	LimitCaps *limit.Caps `json:"-" gorm:"-"`
+}
+
+func (Model) TableName() string {
+	return "transfers"
+}
+
+// IsPending reports whether the transfer is waiting for an operator decision
+func (m *Model) IsPending() bool {
+	return m.Status == StatusPendingReview || m.Status == StatusPendingApproval
+}
+
+// IsFailed reports whether the transfer ended without moving money
+func (m *Model) IsFailed() bool {
+	for _, status := range FailedStatuses {
+		if m.Status == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package transfer
+
+import (
+	"time"
+
+	"internal-transfer-microservice/internal/domain/fee"
+	"internal-transfer-microservice/internal/domain/risk"
+)
+
+type Response struct {
+	TransferId           string         `json:"transfer_id"`
+	SourceAccountId      string         `json:"source_account_id"`
+	DestinationAccountId string         `json:"destination_account_id"`
+	Amount               float64        `json:"amount"`
+	Fee                  float64        `json:"fee"`
+	FeeBreakdown         *fee.Breakdown `json:"fee_breakdown,omitempty"`
+	Type                 string         `json:"type"`
+	Status               string         `json:"status"`
+	RiskDecision         *risk.Decision `json:"risk_decision,omitempty"`
+	FailureReason        string         `json:"failure_reason,omitempty"`
+	InitiatedBy          string         `json:"initiated_by,omitempty"`
+	ReviewedBy           string         `json:"reviewed_by,omitempty"`
+	ReviewedAt           *time.Time     `json:"reviewed_at,omitempty"`
+	ExpiresAt            *time.Time     `json:"expires_at,omitempty"`
+	ExecutedAt           *time.Time     `json:"executed_at,omitempty"`
+	CreatedAt            *time.Time     `json:"created_at,omitempty"`
+}
+
+type RejectTransferRequest struct {
+	Reason string `json:"reason"`
+}
+
+// ToResponse converts a transfer record into its API representation
+func (m *Model) ToResponse() *Response {
+	return &Response{
+		TransferId:           m.ID.String(),
+		SourceAccountId:      m.SourceAccountId,
+		DestinationAccountId: m.DestinationAccountId,
+		Amount:               m.Amount,
+		Fee:                  m.Fee,
+		FeeBreakdown:         m.FeeBreakdown,
+		Type:                 m.Type,
+		Status:               m.Status,
+		RiskDecision:         m.RiskDecision,
+		FailureReason:        m.FailureReason,
+		InitiatedBy:          m.InitiatedBy,
+		ReviewedBy:           m.ReviewedBy,
+		ReviewedAt:           m.ReviewedAt,
+		ExpiresAt:            m.ExpiresAt,
+		ExecutedAt:           m.ExecutedAt,
+		CreatedAt:            m.CreatedAt,
+	}
+}
@@ -0,0 +1,7 @@
+package webhook
+
+import "errors"
+
+// ErrDeliveryReclaimed is returned when the outcome of an attempt is recorded after the delivery was claimed
+// again or replayed, so the outcome no longer applies
+var ErrDeliveryReclaimed = errors.New("webhook delivery was claimed again")
@@ -0,0 +1,46 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	CreateSubscription(ctx context.Context, subscription *Subscription) error
+	GetSubscription(ctx context.Context, subscriptionId uuid.UUID) (*Subscription, error)
+	ListSubscriptions(ctx context.Context) ([]Subscription, error)
+	UpdateSubscription(ctx context.Context, subscription *Subscription) error
+	// ListDeliveries returns the most recent deliveries of a subscription
+	ListDeliveries(ctx context.Context, subscriptionId uuid.UUID, limit int) ([]Delivery, error)
+	GetDelivery(ctx context.Context, deliveryId uuid.UUID) (*Delivery, error)
+	ListAttempts(ctx context.Context, deliveryId uuid.UUID) ([]DeliveryAttempt, error)
+	// ListDueDeliveries returns pending deliveries whose next attempt is due at now, oldest first
+	ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]Delivery, error)
+	// ClaimDelivery takes a due delivery for one attempt, counting the attempt and leasing the delivery until
+	// leaseUntil, when it is due again. It reports false when the delivery changed since it was read.
+	ClaimDelivery(ctx context.Context, delivery *Delivery, leaseUntil time.Time) (bool, error)
+	// RecordAttempt saves the delivery together with the log entry of the attempt just made, failing with
+	// ErrDeliveryReclaimed when the delivery was claimed again or replayed since its claim
+	RecordAttempt(ctx context.Context, delivery *Delivery, attempt *DeliveryAttempt) error
+	UpdateDelivery(ctx context.Context, delivery *Delivery) error
+}
+
+type Service interface {
+	CreateSubscription(ctx context.Context, req CreateSubscriptionRequest) (*SubscriptionResponse, error)
+	ListSubscriptions(ctx context.Context) ([]*SubscriptionResponse, error)
+	DeleteSubscription(ctx context.Context, subscriptionId string) error
+	ListDeliveries(ctx context.Context, subscriptionId string) ([]*DeliveryResponse, error)
+	GetDelivery(ctx context.Context, subscriptionId, deliveryId string) (*DeliveryResponse, error)
+	// ReplayDelivery schedules a delivery to be sent again right away, whatever its status
+	ReplayDelivery(ctx context.Context, subscriptionId, deliveryId string) (*DeliveryResponse, error)
+}
+
+// Dispatcher posts due deliveries to their subscribers
+type Dispatcher interface {
+	// DispatchDue makes one attempt at every due delivery, returning how many succeeded
+	DispatchDue(ctx context.Context) (int, error)
+	// Run dispatches due deliveries every interval until ctx is done
+	Run(ctx context.Context) error
+}
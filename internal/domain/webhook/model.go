@@ -0,0 +1,77 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"internal-transfer-microservice/internal/domain"
+)
+
+// Delivery statuses
+const (
+	DeliveryPending      = "pending"
+	DeliverySucceeded    = "succeeded"
+	DeliveryDeadLettered = "dead_lettered"
+)
+
+// Subscription asks for the transfer events touching one account to be posted to URL
+type Subscription struct {
+	domain.Base
+	AccountId string `json:"account_id" gorm:"index"`
+	URL       string `json:"url"`
+	// EventTypes limits the subscription to some event types; empty means every transfer event
+	EventTypes []string `json:"event_types" gorm:"serializer:json"`
+	Secret     string   `json:"-"`
+	Active     bool     `json:"active" gorm:"index;default:true"`
+}
+
+func (Subscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// Subscribes reports whether the subscription wants events of eventType
+func (s *Subscription) Subscribes(eventType string) bool {
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+	for _, subscribed := range s.EventTypes {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery is one event to post to one subscription, retried until it succeeds or is dead-lettered
+type Delivery struct {
+	domain.Base
+	SubscriptionId uuid.UUID  `json:"subscription_id" gorm:"type:uuid;index"`
+	EventId        uuid.UUID  `json:"event_id" gorm:"type:uuid;index"`
+	EventType      string     `json:"event_type"`
+	Payload        string     `json:"payload" gorm:"type:text"`
+	Status         string     `json:"status" gorm:"index"`
+	Attempts       int        `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at" gorm:"index"`
+	LastStatusCode int        `json:"last_status_code"`
+	LastError      string     `json:"last_error"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+}
+
+func (Delivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// DeliveryAttempt is the log entry of one HTTP call made for a delivery
+type DeliveryAttempt struct {
+	domain.Base
+	DeliveryId uuid.UUID `json:"delivery_id" gorm:"type:uuid;index"`
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"status_code"`
+	Error      string    `json:"error"`
+	DurationMs int64     `json:"duration_ms"`
+}
+
+func (DeliveryAttempt) TableName() string {
+	return "webhook_delivery_attempts"
+}
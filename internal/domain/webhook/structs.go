@@ -0,0 +1,98 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Headers sent with every webhook call
+const (
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderEventType = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+)
+
+type CreateSubscriptionRequest struct {
+	AccountId  string   `json:"account_id" binding:"required"`
+	URL        string   `json:"url" binding:"required"`
+	EventTypes []string `json:"event_types"`
+	// Secret signs the payloads; one is generated when left empty
+	Secret string `json:"secret"`
+}
+
+type SubscriptionResponse struct {
+	SubscriptionId string   `json:"subscription_id"`
+	AccountId      string   `json:"account_id"`
+	URL            string   `json:"url"`
+	EventTypes     []string `json:"event_types,omitempty"`
+	Active         bool     `json:"active"`
+	// Secret is only returned when the subscription is created
+	Secret    string     `json:"secret,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+type DeliveryResponse struct {
+	DeliveryId     string             `json:"delivery_id"`
+	SubscriptionId string             `json:"subscription_id"`
+	EventId        string             `json:"event_id"`
+	EventType      string             `json:"event_type"`
+	Payload        json.RawMessage    `json:"payload,omitempty"`
+	Status         string             `json:"status"`
+	Attempts       int                `json:"attempts"`
+	NextAttemptAt  *time.Time         `json:"next_attempt_at,omitempty"`
+	LastStatusCode int                `json:"last_status_code,omitempty"`
+	LastError      string             `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time         `json:"delivered_at,omitempty"`
+	CreatedAt      *time.Time         `json:"created_at,omitempty"`
+	Log            []*AttemptResponse `json:"log,omitempty"`
+}
+
+type AttemptResponse struct {
+	Attempt    int        `json:"attempt"`
+	StatusCode int        `json:"status_code,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	DurationMs int64      `json:"duration_ms"`
+	At         *time.Time `json:"at,omitempty"`
+}
+
+// ToResponse converts a subscription into its API representation, without its secret
+func (s *Subscription) ToResponse() *SubscriptionResponse {
+	return &SubscriptionResponse{
+		SubscriptionId: s.ID.String(),
+		AccountId:      s.AccountId,
+		URL:            s.URL,
+		EventTypes:     s.EventTypes,
+		Active:         s.Active,
+		CreatedAt:      s.CreatedAt,
+	}
+}
+
+// ToResponse converts a delivery into its API representation
+func (d *Delivery) ToResponse() *DeliveryResponse {
+	return &DeliveryResponse{
+		DeliveryId:     d.ID.String(),
+		SubscriptionId: d.SubscriptionId.String(),
+		EventId:        d.EventId.String(),
+		EventType:      d.EventType,
+		Payload:        json.RawMessage(d.Payload),
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		DeliveredAt:    d.DeliveredAt,
+		CreatedAt:      d.CreatedAt,
+	}
+}
+
+// ToResponse converts a delivery attempt into its API representation
+func (a *DeliveryAttempt) ToResponse() *AttemptResponse {
+	return &AttemptResponse{
+		Attempt:    a.Attempt,
+		StatusCode: a.StatusCode,
+		Error:      a.Error,
+		DurationMs: a.DurationMs,
+		At:         a.CreatedAt,
+	}
+}
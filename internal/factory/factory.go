@@ -1,14 +1,34 @@
 package factory
 
 import (
+	"context"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"internal-transfer-microservice/internal/domain/access"
 	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/event"
+	"internal-transfer-microservice/internal/domain/interest"
+	"internal-transfer-microservice/internal/domain/limit"
+	"internal-transfer-microservice/internal/domain/migration"
+	"internal-transfer-microservice/internal/domain/readmodel"
+	"internal-transfer-microservice/internal/domain/screening"
+	"internal-transfer-microservice/internal/domain/stream"
+	"internal-transfer-microservice/internal/domain/webhook"
 
+	"internal-transfer-microservice/internal/auth"
 	"internal-transfer-microservice/internal/config"
 	"internal-transfer-microservice/internal/controller"
+	"internal-transfer-microservice/internal/infrastructure/broker"
 	"internal-transfer-microservice/internal/infrastructure/cache"
 	"internal-transfer-microservice/internal/infrastructure/db"
+	"internal-transfer-microservice/internal/infrastructure/publisher"
+	"internal-transfer-microservice/internal/middleware"
 	"internal-transfer-microservice/internal/repository"
+	"internal-transfer-microservice/internal/rpc"
 	"internal-transfer-microservice/internal/service"
+	"internal-transfer-microservice/migrations"
 	"internal-transfer-microservice/pkg/logger"
 )
 
@@ -16,13 +36,16 @@ import (
 type Factory struct {
 	database db.Database
 	cache    cache.Cache
+	broker   broker.Broker
 	config   *config.Config
+	// accounts holds accounts and transfers instead of the database when the factory is in memory
+	accounts *repository.MemoryAccountRepoImpl
 }
 
 // NewFactory creates a new factory
 func NewFactory(cfg *config.Config) (*Factory, error) {
 	// Initialize database
-	database, err := db.NewPostgresDB(cfg)
+	database, err := db.NewDatabase(cfg)
 	if err != nil {
 		logger.Errorf("Failed to connect to database: %v", err)
 		return nil, err
@@ -37,13 +60,55 @@ func NewFactory(cfg *config.Config) (*Factory, error) {
 		return nil, err
 	}
 
+	// Initialize broker
+	redisBroker, err := broker.NewRedisBroker(cfg)
+	if err != nil {
+		logger.Errorf("Failed to connect to Redis broker: %v", err)
+		redisCache.Close()
+		database.Close()
+		return nil, err
+	}
+
 	return &Factory{
 		database: database,
 		cache:    redisCache,
+		broker:   redisBroker,
 		config:   cfg,
 	}, nil
 }
 
+// NewInMemoryFactory creates a factory needing no external services, for demos: accounts, transfers, the
+// cache and the event broker live in memory, and everything else in an in-memory SQLite database.
+// Nothing survives a restart, and as transfers write no outbox events, webhooks and the read model stay idle.
+func NewInMemoryFactory(cfg *config.Config) (*Factory, error) {
+	memoryCfg := *cfg
+	memoryCfg.Database.Driver = db.DriverSQLite
+	memoryCfg.Database.Path = ":memory:"
+	database, err := db.NewSQLiteDB(&memoryCfg)
+	if err != nil {
+		logger.Errorf("Failed to open in-memory database: %v", err)
+		return nil, err
+	}
+
+	f := &Factory{
+		database: database,
+		cache:    cache.NewMemoryCache(),
+		broker:   broker.NewMemoryBroker(cfg.GetStreamBacklogSize()),
+		config:   &memoryCfg,
+		accounts: repository.NewMemoryAccountRepo(),
+	}
+	migrator, err := f.CreateMigrator()
+	if err == nil {
+		_, err = migrator.Up(context.Background())
+	}
+	if err != nil {
+		logger.Errorf("Failed to migrate in-memory database: %v", err)
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
 // Close closes all connections
 func (f *Factory) Close() {
 	if f.database != nil {
@@ -52,14 +117,68 @@ func (f *Factory) Close() {
 	if f.cache != nil {
 		f.cache.Close()
 	}
+	if f.broker != nil {
+		f.broker.Close()
+	}
 }
 
-func (f *Factory) CreateAccountController() *controller.AccountController {
+// CreateAccountRepo creates the account repository for the configured store, behind the account cache when enabled,
+// or returns the in-memory one
+func (f *Factory) CreateAccountRepo() repository.AccountStore {
+	if f.accounts != nil {
+		return f.accounts
+	}
+	var store repository.AccountStore = repository.NewAccountRepo(f.database)
+	if f.config.GetAccountStore() == "event_sourced" {
+		store = repository.NewEventSourcedAccountRepo(f.database, f.config.GetAccountSnapshotEvery())
+	}
+	if f.config.GetAccountCacheEnabled() {
+		store = repository.NewCachedAccountRepo(store, f.cache, f.config.GetAccountCacheTTL(), f.config.GetAccountCacheNegativeTTL())
+	}
+	return store
+}
+
+// CreateEventSourcedAccountRepo creates the event-sourced account repository, whatever store is configured
+func (f *Factory) CreateEventSourcedAccountRepo() *repository.EventSourcedAccountRepoImpl {
+	return repository.NewEventSourcedAccountRepo(f.database, f.config.GetAccountSnapshotEvery())
+}
+
+// CreateAccountService creates the account service with every configured transfer policy
+func (f *Factory) CreateAccountService() account.Service {
 	// Create repository
-	accountRepo := repository.NewAccountRepo(f.database)
+	accountRepo := f.CreateAccountRepo()
+
+	// Create service
+	opts := []service.Option{
+		service.WithFeeEngine(service.NewFeeEngine(f.config)),
+		service.WithLimitEngine(service.NewLimitEngine(f.createLimitRepo(), f.config)),
+		service.WithApprovalPolicy(f.config.GetApprovalThreshold(), f.config.GetApprovalExpiry()),
+		service.WithNotifier(f.CreateStreamService()),
+		service.WithAccessPolicy(f.CreateAccessPolicy()),
+	}
+	if len(f.config.GetRiskRules()) > 0 {
+		opts = append(opts, service.WithRiskEvaluator(service.NewRulesEngine(f.config), f.config.GetRiskVelocityWindow()))
+	}
+	if f.config.GetScreeningEnabled() {
+		opts = append(opts, service.WithScreener(f.CreateScreeningService()))
+	}
+	if f.config.GetReadModelEnabled() {
+		opts = append(opts, service.WithReadModel(repository.NewReadModelRepo(f.database)))
+	}
+	return service.NewAccountService(accountRepo, f.cache, opts...)
+}
+
+// createLimitRepo creates the repository of the limit counters the account repository keeps
+func (f *Factory) createLimitRepo() limit.Repository {
+	if f.accounts != nil {
+		return f.accounts
+	}
+	return repository.NewLimitRepo(f.database)
+}
 
+func (f *Factory) CreateAccountController() *controller.AccountController {
 	// Create service
-	accountService := service.NewAccountService(accountRepo, f.cache)
+	accountService := f.CreateAccountService()
 
 	// Create controller
 	accountController := controller.NewAccountController(accountService)
@@ -67,11 +186,116 @@ func (f *Factory) CreateAccountController() *controller.AccountController {
 	return accountController
 }
 
-// MigrateDB performs database migrations
-func (f *Factory) MigrateDB() error {
-	// Auto migrate models
-	err := f.database.GetConnection().AutoMigrate(
-		&account.Model{},
+// CreateIdempotencyMiddleware creates the middleware replaying responses to retried POST requests from the cache
+func (f *Factory) CreateIdempotencyMiddleware() gin.HandlerFunc {
+	return middleware.Idempotency(f.cache, f.config.GetIdempotencyTTL())
+}
+
+// CreateStreamService creates the service publishing and streaming account events
+func (f *Factory) CreateStreamService() stream.Service {
+	return service.NewStreamService(f.broker, f.CreateAccountRepo(), f.CreateAccessPolicy())
+}
+
+func (f *Factory) CreateStreamController() *controller.StreamController {
+	return controller.NewStreamController(f.CreateStreamService(), f.config.GetStreamHeartbeat())
+}
+
+// CreateGRPCServer creates the gRPC server of the account and stream services, authenticating calls with
+// verifier unless it is nil
+func (f *Factory) CreateGRPCServer(verifier auth.Verifier) *rpc.Server {
+	return rpc.NewServer(rpc.NewAccountServer(f.CreateAccountService(), f.CreateStreamService()), verifier)
+}
+
+// CreateTokenVerifier creates the verifier of bearer tokens, or returns nil when auth is disabled
+func (f *Factory) CreateTokenVerifier() (auth.Verifier, error) {
+	if !f.config.GetAuthEnabled() {
+		return nil, nil
+	}
+	verifier, err := auth.NewJWTVerifier(f.config)
+	if err != nil {
+		return nil, err
+	}
+	return verifier, nil
+}
+
+// CreateAccessPolicy creates the policy restricting callers to the accounts they own, or returns nil when
+// auth is disabled and callers are not authenticated
+func (f *Factory) CreateAccessPolicy() access.Policy {
+	if !f.config.GetAuthEnabled() {
+		return nil
+	}
+	return service.NewOwnershipPolicy()
+}
+
+// CreateScreeningService creates the service screening transfers against the watchlist
+func (f *Factory) CreateScreeningService() screening.Service {
+	return service.NewScreeningService(repository.NewScreeningRepo(f.database), f.cache, f.config, f.CreateAccessPolicy())
+}
+
+func (f *Factory) CreateScreeningController() *controller.ScreeningController {
+	return controller.NewScreeningController(f.CreateScreeningService())
+}
+
+// CreateInterestService creates the service running the interest accrual and posting jobs
+func (f *Factory) CreateInterestService() interest.Service {
+	accountRepo := f.CreateAccountRepo()
+	return service.NewInterestService(
+		repository.NewInterestRepo(f.database, accountRepo),
+		accountRepo,
+		f.cache,
+		f.config,
 	)
-	return err
+}
+
+// CreateEventPublisher creates the event publisher selected by the configuration
+func (f *Factory) CreateEventPublisher() (publisher.EventPublisher, error) {
+	switch kind := f.config.GetEventPublisher(); kind {
+	case "", "log":
+		return publisher.NewLogPublisher(), nil
+	case "kafka":
+		return publisher.NewKafkaPublisher(f.config)
+	case "nats":
+		return publisher.NewNATSPublisher(f.config)
+	case "file":
+		return publisher.NewFilePublisher(f.config)
+	default:
+		return nil, fmt.Errorf("unknown event publisher %q", kind)
+	}
+}
+
+// CreateOutboxRelay creates the relay publishing outbox events with eventPublisher
+func (f *Factory) CreateOutboxRelay(eventPublisher publisher.EventPublisher) event.Relay {
+	return service.NewOutboxRelay(repository.NewOutboxRepo(f.database), f.cache, eventPublisher, f.config)
+}
+
+// CreateReadModelProjector creates the projector keeping the account read model up to date
+func (f *Factory) CreateReadModelProjector() readmodel.Projector {
+	return service.NewReadModelProjector(repository.NewReadModelRepo(f.database), f.cache, f.config)
+}
+
+// CreateWebhookService creates the service managing webhook subscriptions and their deliveries
+func (f *Factory) CreateWebhookService() webhook.Service {
+	return service.NewWebhookService(repository.NewWebhookRepo(f.database), f.CreateAccountRepo(), f.CreateAccessPolicy())
+}
+
+func (f *Factory) CreateWebhookController() *controller.WebhookController {
+	return controller.NewWebhookController(f.CreateWebhookService())
+}
+
+// CreateWebhookDispatcher creates the dispatcher posting due webhook deliveries
+func (f *Factory) CreateWebhookDispatcher() webhook.Dispatcher {
+	return service.NewWebhookDispatcher(repository.NewWebhookRepo(f.database), f.cache, f.config)
+}
+
+// CreateMigrator creates the migrator applying the embedded SQL migrations
+func (f *Factory) CreateMigrator() (migration.Service, error) {
+	files, err := migrations.For(f.config.GetDBDriver())
+	if err != nil {
+		return nil, err
+	}
+	scripts, err := migration.Load(files)
+	if err != nil {
+		return nil, err
+	}
+	return service.NewMigrator(repository.NewMigrationRepo(f.database), scripts), nil
 }
@@ -0,0 +1,27 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Message is a payload published on a channel, numbered in publishing order within that channel
+type Message struct {
+	ID      uint64          `json:"id"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Broker fans messages out to every subscriber of a channel, across replicas,
+// keeping a bounded backlog per channel so subscribers can resume after a disconnect
+type Broker interface {
+	// Publish numbers payload, appends it to the backlog of channel and delivers it to live subscribers.
+	// Payload must be valid JSON.
+	Publish(ctx context.Context, channel string, payload []byte) (uint64, error)
+
+	// Subscribe delivers the messages of channel until ctx is done. A non-zero lastId first replays
+	// the backlogged messages published after it; live messages already replayed are skipped.
+	Subscribe(ctx context.Context, channel string, lastId uint64) (<-chan Message, error)
+
+	// Close closes the broker connection
+	Close() error
+}
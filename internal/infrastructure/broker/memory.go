@@ -0,0 +1,96 @@
+package broker
+
+import (
+	"context"
+	"sync"
+)
+
+// memorySubscriber is a live subscription, ended when ctx is done
+type memorySubscriber struct {
+	ctx context.Context
+	out chan Message
+}
+
+// MemoryBroker implements Broker within the process, for demos and tests, keeping the last backlogSize
+// messages of each channel. Subscribers only see messages published by the same process.
+type MemoryBroker struct {
+	backlogSize int
+	sequences   map[string]uint64
+	backlogs    map[string][]Message
+	subscribers map[string][]*memorySubscriber
+	mu          sync.Mutex
+}
+
+// NewMemoryBroker creates a broker keeping the last backlogSize messages of each channel
+func NewMemoryBroker(backlogSize int) *MemoryBroker {
+	return &MemoryBroker{
+		backlogSize: backlogSize,
+		sequences:   make(map[string]uint64),
+		backlogs:    make(map[string][]Message),
+		subscribers: make(map[string][]*memorySubscriber),
+	}
+}
+
+func (m *MemoryBroker) Publish(ctx context.Context, channel string, payload []byte) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sequences[channel]++
+	message := Message{ID: m.sequences[channel], Payload: append([]byte(nil), payload...)}
+	if m.backlogSize > 0 {
+		backlog := append(m.backlogs[channel], message)
+		if len(backlog) > m.backlogSize {
+			backlog = backlog[len(backlog)-m.backlogSize:]
+		}
+		m.backlogs[channel] = backlog
+	}
+
+	for _, subscriber := range m.subscribers[channel] {
+		select {
+		case subscriber.out <- message:
+		case <-subscriber.ctx.Done():
+		}
+	}
+	return message.ID, nil
+}
+
+func (m *MemoryBroker) Subscribe(ctx context.Context, channel string, lastId uint64) (<-chan Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// the backlog is replayed into the buffer, so it is sized to take all of it
+	var replayed []Message
+	if lastId > 0 {
+		for _, message := range m.backlogs[channel] {
+			if message.ID > lastId {
+				replayed = append(replayed, message)
+			}
+		}
+	}
+	subscriber := &memorySubscriber{ctx: ctx, out: make(chan Message, subscriberBuffer+len(replayed))}
+	for _, message := range replayed {
+		subscriber.out <- message
+	}
+	m.subscribers[channel] = append(m.subscribers[channel], subscriber)
+
+	go func() {
+		<-ctx.Done()
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		subscribers := m.subscribers[channel]
+		for i, s := range subscribers {
+			if s == subscriber {
+				m.subscribers[channel] = append(subscribers[:i:i], subscribers[i+1:]...)
+				break
+			}
+		}
+		close(subscriber.out)
+	}()
+	return subscriber.out, nil
+}
+
+// Close has nothing to release, as subscriptions end with their contexts
+func (m *MemoryBroker) Close() error {
+	return nil
+}
@@ -0,0 +1,142 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"internal-transfer-microservice/internal/config"
+)
+
+const (
+	sequencePrefix = "broker:seq:"
+	backlogPrefix  = "broker:backlog:"
+)
+
+// subscriberBuffer is how many messages a slow subscriber may fall behind before delivery blocks
+const subscriberBuffer = 64
+
+// RedisBroker implements Broker with Redis pub/sub, numbering messages with INCR
+// and keeping the backlog of each channel in a sorted set scored by message id
+type RedisBroker struct {
+	client      *redis.Client
+	backlogSize int64
+	backlogTTL  time.Duration
+}
+
+// NewRedisBroker creates a new Redis broker
+func NewRedisBroker(cfg *config.Config) (Broker, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.GetRedisAddress(),
+		Password: cfg.GetRedisPassword(),
+		DB:       cfg.GetRedisDB(),
+	})
+
+	if _, err := client.Ping(context.Background()).Result(); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	log.Println("Connected to Redis broker")
+
+	return &RedisBroker{
+		client:      client,
+		backlogSize: int64(cfg.GetStreamBacklogSize()),
+		backlogTTL:  cfg.GetStreamBacklogTTL(),
+	}, nil
+}
+
+func (r *RedisBroker) Publish(ctx context.Context, channel string, payload []byte) (uint64, error) {
+	id, err := r.client.Incr(ctx, sequencePrefix+channel).Result()
+	if err != nil {
+		return 0, err
+	}
+	encoded, err := json.Marshal(Message{ID: uint64(id), Payload: payload})
+	if err != nil {
+		return 0, err
+	}
+
+	backlog := backlogPrefix + channel
+	pipe := r.client.TxPipeline()
+	pipe.ZAdd(ctx, backlog, &redis.Z{Score: float64(id), Member: encoded})
+	if r.backlogSize > 0 {
+		pipe.ZRemRangeByRank(ctx, backlog, 0, -r.backlogSize-1)
+	}
+	if r.backlogTTL > 0 {
+		pipe.Expire(ctx, backlog, r.backlogTTL)
+	}
+	pipe.Publish(ctx, channel, encoded)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return uint64(id), nil
+}
+
+func (r *RedisBroker) Subscribe(ctx context.Context, channel string, lastId uint64) (<-chan Message, error) {
+	// subscribe before reading the backlog, so nothing published in between is missed
+	sub := r.client.Subscribe(ctx, channel)
+	if _, err := sub.Receive(ctx); err != nil {
+		sub.Close()
+		return nil, err
+	}
+
+	var backlog []string
+	if lastId > 0 {
+		var err error
+		backlog, err = r.client.ZRangeByScore(ctx, backlogPrefix+channel, &redis.ZRangeBy{
+			Min: "(" + strconv.FormatUint(lastId, 10),
+			Max: "+inf",
+		}).Result()
+		if err != nil {
+			sub.Close()
+			return nil, err
+		}
+	}
+
+	out := make(chan Message, subscriberBuffer)
+	go func() {
+		defer close(out)
+		defer sub.Close()
+
+		deliver := func(encoded string) bool {
+			var message Message
+			if err := json.Unmarshal([]byte(encoded), &message); err != nil || message.ID <= lastId {
+				return true
+			}
+			lastId = message.ID
+			select {
+			case out <- message:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for _, encoded := range backlog {
+			if !deliver(encoded) {
+				return
+			}
+		}
+		live := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case received, ok := <-live:
+				if !ok || !deliver(received.Payload) {
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+// Close closes the Redis client
+func (r *RedisBroker) Close() error {
+	return r.client.Close()
+}
@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned by MemoryCache.Get for keys that are missing or expired
+var ErrNotFound = errors.New("cache: key not found")
+
+// memorySweepInterval is how often Set drops the expired entries nobody read since they expired
+const memorySweepInterval = time.Minute
+
+type memoryEntry struct {
+	value string
+	// expiresAt is zero for entries that never expire
+	expiresAt time.Time
+}
+
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// MemoryCache implements Cache interface within the process, for demos and tests. Values and locks
+// expire like their Redis counterparts, and locks share the key space of values under LockPrefix.
+type MemoryCache struct {
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+	mu        sync.Mutex
+	// now is replaced by tests that move the clock
+	now func() time.Time
+}
+
+// NewMemoryCache creates an empty in-memory cache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		entries: make(map[string]memoryEntry),
+		now:     time.Now,
+	}
+}
+
+// get returns the entry of key unless it expired, which it drops
+func (m *MemoryCache) get(key string, now time.Time) (memoryEntry, bool) {
+	entry, exists := m.entries[key]
+	if exists && entry.expired(now) {
+		delete(m.entries, key)
+		return memoryEntry{}, false
+	}
+	return entry, exists
+}
+
+// set stores value under key for expiration, or until it is deleted when expiration is not positive
+func (m *MemoryCache) set(key, value string, expiration time.Duration, now time.Time) {
+	entry := memoryEntry{value: value}
+	if expiration > 0 {
+		entry.expiresAt = now.Add(expiration)
+	}
+	m.entries[key] = entry
+
+	if now.Sub(m.lastSweep) >= memorySweepInterval {
+		for key, entry := range m.entries {
+			if entry.expired(now) {
+				delete(m.entries, key)
+			}
+		}
+		m.lastSweep = now
+	}
+}
+
+// Get retrieves a value from the cache, failing with ErrNotFound when there is none
+func (m *MemoryCache) Get(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, exists := m.get(key, m.now())
+	if !exists {
+		return "", ErrNotFound
+	}
+	return entry.value, nil
+}
+
+// Set stores a value in the cache; a zero expiration keeps it until it is deleted
+func (m *MemoryCache) Set(ctx context.Context, key string, value string, expiration time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.set(key, value, expiration, m.now())
+	return nil
+}
+
+// Delete removes a value from the cache
+func (m *MemoryCache) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+	return nil
+}
+
+// Lock acquires the lock of key unless it is held. The lock is held until it is released or, with a
+// positive expiration, until it expires, so a holder that never releases it does not block others forever.
+func (m *MemoryCache) Lock(ctx context.Context, key string, expiration time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if _, held := m.get(LockPrefix+key, now); held {
+		return false, nil
+	}
+	m.set(LockPrefix+key, "locked", expiration, now)
+	return true, nil
+}
+
+// Release releases the lock of key
+func (m *MemoryCache) Release(ctx context.Context, key string) error {
+	return m.Delete(ctx, LockPrefix+key)
+}
+
+// Close drops every value and lock
+func (m *MemoryCache) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries = make(map[string]memoryEntry)
+	return nil
+}
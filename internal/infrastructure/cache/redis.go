@@ -28,8 +28,8 @@ func (r *RedisCache) Lock(ctx context.Context, key string, expiration time.Durat
 }
 
 func (r *RedisCache) Release(ctx context.Context, key string) error {
-	// Release the lock by deleting the key
-	return r.Delete(ctx, key)
+	// Release the lock by deleting the key it was taken with
+	return r.Delete(ctx, LockPrefix+key)
 }
 
 // NewRedisCache creates a new Redis cache
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"expvar"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	statsMu       sync.Mutex
+	statsByName   = make(map[string]*Stats)
+	publishedVars = expvar.NewMap("cache")
+)
+
+// Stats counts the lookups of a read-through cache. Every Stats is published under its name in the
+// "cache" expvar map, served at /debug/vars.
+type Stats struct {
+	hits         atomic.Int64
+	negativeHits atomic.Int64
+	misses       atomic.Int64
+	loads        atomic.Int64
+	errors       atomic.Int64
+}
+
+// NewStats returns the stats published under name, creating them on first use
+func NewStats(name string) *Stats {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+
+	if stats, ok := statsByName[name]; ok {
+		return stats
+	}
+	stats := &Stats{}
+	statsByName[name] = stats
+	publishedVars.Set(name, expvar.Func(func() interface{} { return stats.Snapshot() }))
+	return stats
+}
+
+// Hit counts a lookup served from the cache
+func (s *Stats) Hit() { s.hits.Add(1) }
+
+// NegativeHit counts a lookup answered by a cached "not found"
+func (s *Stats) NegativeHit() { s.negativeHits.Add(1) }
+
+// Miss counts a lookup the cache could not answer
+func (s *Stats) Miss() { s.misses.Add(1) }
+
+// Load counts a read of the backing store; concurrent misses of the same key share one load
+func (s *Stats) Load() { s.loads.Add(1) }
+
+// Error counts a failed cache read or write, which falls back to the backing store
+func (s *Stats) Error() { s.errors.Add(1) }
+
+// HitRate returns the share of lookups answered by the cache, negative hits included
+func (s *Stats) HitRate() float64 {
+	answered := s.hits.Load() + s.negativeHits.Load()
+	total := answered + s.misses.Load()
+	if total == 0 {
+		return 0
+	}
+	return float64(answered) / float64(total)
+}
+
+// Snapshot returns the current counters and hit rate
+func (s *Stats) Snapshot() map[string]interface{} {
+	return map[string]interface{}{
+		"hits":          s.hits.Load(),
+		"negative_hits": s.negativeHits.Load(),
+		"misses":        s.misses.Load(),
+		"loads":         s.loads.Load(),
+		"errors":        s.errors.Load(),
+		"hit_rate":      s.HitRate(),
+	}
+}
@@ -1,14 +1,54 @@
 package db
 
 import (
+	"context"
+	"fmt"
+
 	"gorm.io/gorm"
+
+	"internal-transfer-microservice/internal/config"
+)
+
+// Supported database drivers
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
 )
 
 // Database interface defines the operations for database access
 type Database interface {
-	// GetConnection returns the database connection
+	// GetConnection returns the connection to the primary, for writes, locking reads and transactions
 	GetConnection() *gorm.DB
 
+	// GetReadConnection returns a connection for read-only queries: a healthy replica within the lag limit,
+	// or the primary when there is none or ctx was pinned to it with WithPrimary
+	GetReadConnection(ctx context.Context) *gorm.DB
+
 	// Close closes the database connection
 	Close() error
 }
+
+type primaryKey struct{}
+
+// WithPrimary pins the reads made with ctx to the primary, for operations that write what they read
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// UsesPrimary reports whether the reads made with ctx are pinned to the primary
+func UsesPrimary(ctx context.Context) bool {
+	pinned, _ := ctx.Value(primaryKey{}).(bool)
+	return pinned
+}
+
+// NewDatabase opens the database of the configured driver
+func NewDatabase(cfg *config.Config) (Database, error) {
+	switch cfg.GetDBDriver() {
+	case DriverPostgres:
+		return NewPostgresDB(cfg)
+	case DriverSQLite:
+		return NewSQLiteDB(cfg)
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", cfg.GetDBDriver())
+	}
+}
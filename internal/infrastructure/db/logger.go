@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"internal-transfer-microservice/pkg/logger"
+)
+
+// gormLogger writes GORM's logs through pkg/logger: failed queries as errors, queries slower than the
+// threshold as warnings and, at the info level, every query
+type gormLogger struct {
+	level         gormlogger.LogLevel
+	slowThreshold time.Duration
+	database      string
+}
+
+// newGormLogger logs the queries of the named database at level, one of silent, error, warn or info
+func newGormLogger(database, level string, slowThreshold time.Duration) gormlogger.Interface {
+	return &gormLogger{
+		level:         parseLogLevel(level),
+		slowThreshold: slowThreshold,
+		database:      database,
+	}
+}
+
+func parseLogLevel(level string) gormlogger.LogLevel {
+	switch level {
+	case "silent":
+		return gormlogger.Silent
+	case "error":
+		return gormlogger.Error
+	case "info":
+		return gormlogger.Info
+	default:
+		return gormlogger.Warn
+	}
+}
+
+func (l *gormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	copied := *l
+	copied.level = level
+	return &copied
+}
+
+func (l *gormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Info {
+		l.entry(ctx).Infof(msg, data...)
+	}
+}
+
+func (l *gormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		l.entry(ctx).Warnf(msg, data...)
+	}
+}
+
+func (l *gormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Error {
+		l.entry(ctx).Errorf(msg, data...)
+	}
+}
+
+func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+	elapsed := time.Since(begin)
+
+	switch {
+	// a missing record is an expected outcome the callers handle, not a failure
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= gormlogger.Error:
+		sql, rows := fc()
+		l.query(ctx, sql, rows, elapsed).WithError(err).Error("Query failed")
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= gormlogger.Warn:
+		sql, rows := fc()
+		l.query(ctx, sql, rows, elapsed).Warnf("Slow query, over %s", l.slowThreshold)
+	case l.level >= gormlogger.Info:
+		sql, rows := fc()
+		l.query(ctx, sql, rows, elapsed).Info("Query")
+	}
+}
+
+func (l *gormLogger) entry(ctx context.Context) logger.Logger {
+	return logger.GetLogger().WithContext(ctx).WithField("database", l.database)
+}
+
+func (l *gormLogger) query(ctx context.Context, sql string, rows int64, elapsed time.Duration) logger.Logger {
+	return l.entry(ctx).WithFields(logger.Fields{
+		"sql":        sql,
+		"rows":       rows,
+		"elapsed_ms": fmt.Sprintf("%.3f", float64(elapsed.Microseconds())/1000),
+	})
+}
@@ -1,33 +1,141 @@
 package db
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 
 	"internal-transfer-microservice/internal/config"
 )
 
+// replicaLagQuery returns how far a replica's replay is behind, in seconds. A replica that replayed
+// everything it received reports no lag, however long ago the primary last committed.
+const replicaLagQuery = `SELECT CASE
+	WHEN NOT pg_is_in_recovery() OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+	ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+END`
+
+// replica is a read-only connection with the outcome of its last health check
+type replica struct {
+	name    string
+	db      *gorm.DB
+	healthy atomic.Bool
+	lag     atomic.Int64
+}
+
 // PostgresDB implements Database interface
 type PostgresDB struct {
-	db *gorm.DB
+	db       *gorm.DB
+	replicas []*replica
+	maxLag   time.Duration
+	next     atomic.Uint64
+	stop     chan struct{}
+	stopped  sync.WaitGroup
 }
 
-// NewPostgresDB creates a new PostgreSQL database connection
+// NewPostgresDB creates a new PostgreSQL database connection, with connections to the configured replicas
+// whose health and lag are checked in the background
 func NewPostgresDB(cfg *config.Config) (Database, error) {
-	dsn := cfg.GetDBConnectionString()
-
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
-	})
+	db, err := openPostgres("primary", cfg.GetDBConnectionString(), cfg, &gorm.Config{})
 	if err != nil {
 		return nil, err
 	}
 
 	log.Println("Connected to PostgreSQL database")
-	return &PostgresDB{db: db}, nil
+	p := &PostgresDB{
+		db:     db,
+		maxLag: cfg.GetDBReplicaMaxLag(),
+		stop:   make(chan struct{}),
+	}
+
+	for i, replicaDSN := range cfg.GetDBReplicaDSNs() {
+		// replicas are connected lazily, so one being down does not stop the service from starting
+		name := fmt.Sprintf("replica %d", i+1)
+		replicaDB, err := openPostgres(name, replicaDSN, cfg, &gorm.Config{
+			DisableAutomaticPing:   true,
+			SkipDefaultTransaction: true,
+		})
+		if err != nil {
+			p.Close()
+			return nil, err
+		}
+		p.replicas = append(p.replicas, &replica{name: name, db: replicaDB})
+	}
+
+	if len(p.replicas) > 0 {
+		p.checkReplicas()
+		p.stopped.Add(1)
+		go p.watchReplicas(cfg.GetDBReplicaCheckInterval())
+	}
+	return p, nil
+}
+
+// openPostgres opens the named database with the configured pool, session timeouts and query log
+func openPostgres(name, dsn string, cfg *config.Config, gormConfig *gorm.Config) (*gorm.DB, error) {
+	gormConfig.Logger = newGormLogger(name, cfg.GetDBLogLevel(), cfg.GetDBSlowQueryThreshold())
+	db, err := gorm.Open(postgres.Open(sessionDSN(dsn, cfg)), gormConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(cfg.GetDBMaxOpenConns())
+	sqlDB.SetMaxIdleConns(cfg.GetDBMaxIdleConns())
+	sqlDB.SetConnMaxLifetime(cfg.GetDBConnMaxLifetime())
+	sqlDB.SetConnMaxIdleTime(cfg.GetDBConnMaxIdleTime())
+	return db, nil
+}
+
+// sessionDSN adds the configured statement and lock timeouts to dsn as run-time parameters, which every
+// session starts with. Timeouts the DSN already sets are kept.
+func sessionDSN(dsn string, cfg *config.Config) string {
+	params := [][2]string{
+		{"statement_timeout", milliseconds(cfg.GetDBStatementTimeout())},
+		{"lock_timeout", milliseconds(cfg.GetDBLockTimeout())},
+	}
+
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		parsed, err := url.Parse(dsn)
+		if err != nil {
+			// left for the driver to report
+			return dsn
+		}
+		query := parsed.Query()
+		for _, param := range params {
+			if param[1] != "" && !query.Has(param[0]) {
+				query.Set(param[0], param[1])
+			}
+		}
+		parsed.RawQuery = query.Encode()
+		return parsed.String()
+	}
+
+	for _, param := range params {
+		if param[1] != "" && !strings.Contains(dsn, param[0]+"=") {
+			dsn += " " + param[0] + "=" + param[1]
+		}
+	}
+	return dsn
+}
+
+// milliseconds formats a timeout the way Postgres reads it, empty when it is not set
+func milliseconds(timeout time.Duration) string {
+	if timeout <= 0 {
+		return ""
+	}
+	return strconv.FormatInt(timeout.Milliseconds(), 10)
 }
 
 // GetConnection returns the database connection
@@ -35,8 +143,80 @@ func (p *PostgresDB) GetConnection() *gorm.DB {
 	return p.db
 }
 
+// GetReadConnection returns the next healthy replica within the lag limit, round robin, or the primary
+func (p *PostgresDB) GetReadConnection(ctx context.Context) *gorm.DB {
+	if len(p.replicas) == 0 || UsesPrimary(ctx) {
+		return p.db
+	}
+	start := p.next.Add(1)
+	for i := range p.replicas {
+		r := p.replicas[(start+uint64(i))%uint64(len(p.replicas))]
+		if r.healthy.Load() && time.Duration(r.lag.Load()) <= p.maxLag {
+			return r.db
+		}
+	}
+	return p.db
+}
+
+// watchReplicas checks the replicas every interval until the database is closed
+func (p *PostgresDB) watchReplicas(interval time.Duration) {
+	defer p.stopped.Done()
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.checkReplicas()
+		}
+	}
+}
+
+// checkReplicas records whether every replica answers and how far behind the primary it is
+func (p *PostgresDB) checkReplicas() {
+	for _, r := range p.replicas {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		var lagSeconds float64
+		err := r.db.WithContext(ctx).Raw(replicaLagQuery).Scan(&lagSeconds).Error
+		cancel()
+
+		wasHealthy := r.healthy.Load()
+		if err != nil {
+			r.healthy.Store(false)
+			if wasHealthy {
+				log.Printf("Database %s is unhealthy, reading from the primary instead: %v", r.name, err)
+			}
+			continue
+		}
+		lag := time.Duration(lagSeconds * float64(time.Second))
+		wasLagging := time.Duration(r.lag.Swap(int64(lag))) > p.maxLag
+		r.healthy.Store(true)
+		if !wasHealthy {
+			log.Printf("Database %s is healthy", r.name)
+		}
+		if lagging := lag > p.maxLag; lagging && !wasLagging {
+			log.Printf("Database %s lags %s behind the primary, reading from the primary instead", r.name, lag.Round(time.Millisecond))
+		} else if !lagging && wasLagging {
+			log.Printf("Database %s caught up with the primary", r.name)
+		}
+	}
+}
+
 // Close closes the database connection
 func (p *PostgresDB) Close() error {
+	close(p.stop)
+	p.stopped.Wait()
+	for _, r := range p.replicas {
+		if sqlDB, err := r.db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	}
+
 	sqlDB, err := p.db.DB()
 	if err != nil {
 		return err
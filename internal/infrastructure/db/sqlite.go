@@ -0,0 +1,91 @@
+package db
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"internal-transfer-microservice/internal/config"
+)
+
+// sqliteMemory is the path of a database that lives in memory for as long as its one connection is open
+const sqliteMemory = ":memory:"
+
+// SQLiteDB implements Database interface over a SQLite file, for local development and tests. It has
+// no replicas, so reads are served by the same file.
+type SQLiteDB struct {
+	db *gorm.DB
+}
+
+// NewSQLiteDB opens the SQLite database at the configured path, creating it if it does not exist
+func NewSQLiteDB(cfg *config.Config) (Database, error) {
+	path := cfg.GetDBPath()
+	db, err := gorm.Open(sqlite.Open(sqliteDSN(path, cfg.GetDBLockTimeout())), &gorm.Config{
+		Logger: newGormLogger("sqlite", cfg.GetDBLogLevel(), cfg.GetDBSlowQueryThreshold()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	if path == sqliteMemory {
+		// every connection would open a database of its own, so the pool keeps exactly one, forever
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+		sqlDB.SetConnMaxIdleTime(0)
+	} else {
+		sqlDB.SetMaxOpenConns(cfg.GetDBMaxOpenConns())
+		sqlDB.SetMaxIdleConns(cfg.GetDBMaxIdleConns())
+		sqlDB.SetConnMaxLifetime(cfg.GetDBConnMaxLifetime())
+		sqlDB.SetConnMaxIdleTime(cfg.GetDBConnMaxIdleTime())
+	}
+
+	log.Printf("Connected to SQLite database %s", path)
+	return &SQLiteDB{db: db}, nil
+}
+
+// sqliteDSN opens path in WAL mode, so reads do not wait for writes, with transactions that take the
+// write lock as they begin, waiting up to lockTimeout for it. Taking it later could fail a transaction
+// that read before another one wrote, instead of waiting.
+func sqliteDSN(path string, lockTimeout time.Duration) string {
+	if lockTimeout <= 0 {
+		lockTimeout = 5 * time.Second
+	}
+	params := "_busy_timeout=" + strconv.FormatInt(lockTimeout.Milliseconds(), 10) + "&_txlock=immediate"
+	if path != sqliteMemory {
+		params += "&_journal_mode=WAL"
+	}
+
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	return path + separator + params
+}
+
+// GetConnection returns the database connection
+func (s *SQLiteDB) GetConnection() *gorm.DB {
+	return s.db
+}
+
+// GetReadConnection returns the database connection, as SQLite has no replicas
+func (s *SQLiteDB) GetReadConnection(ctx context.Context) *gorm.DB {
+	return s.db
+}
+
+// Close closes the database connection
+func (s *SQLiteDB) Close() error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
@@ -0,0 +1,70 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"internal-transfer-microservice/internal/config"
+)
+
+// fileRecord is one line of the event file
+type fileRecord struct {
+	Topic   string            `json:"topic"`
+	Key     string            `json:"key"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Payload json.RawMessage   `json:"payload"`
+}
+
+// FilePublisher implements EventPublisher by appending newline-delimited JSON to a file or standard output
+type FilePublisher struct {
+	mu     sync.Mutex
+	out    io.Writer
+	closer io.Closer
+}
+
+// Publish appends the message as one JSON line; a single file keeps every event in publishing order
+func (f *FilePublisher) Publish(ctx context.Context, topic string, message Message) error {
+	payload := json.RawMessage(message.Payload)
+	if !json.Valid(payload) {
+		// keep the line valid JSON even for payloads that are not
+		encoded, err := json.Marshal(string(message.Payload))
+		if err != nil {
+			return err
+		}
+		payload = encoded
+	}
+	line, err := json.Marshal(fileRecord{Topic: topic, Key: message.Key, Headers: message.Headers, Payload: payload})
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, err = f.out.Write(append(line, '\n'))
+	return err
+}
+
+// Close closes the file
+func (f *FilePublisher) Close() error {
+	if f.closer == nil {
+		return nil
+	}
+	return f.closer.Close()
+}
+
+// NewFilePublisher creates a publisher appending to the configured file, or writing to standard output for "-"
+func NewFilePublisher(cfg *config.Config) (EventPublisher, error) {
+	path := cfg.GetEventFilePath()
+	if path == "-" {
+		return &FilePublisher{out: os.Stdout}, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &FilePublisher{out: file, closer: file}, nil
+}
@@ -0,0 +1,22 @@
+package publisher
+
+import (
+	"context"
+)
+
+// Message is one event handed to an EventPublisher
+type Message struct {
+	// Key orders messages: brokers keep messages with the same key in publishing order
+	Key     string
+	Payload []byte
+	Headers map[string]string
+}
+
+// EventPublisher defines the interface for delivering events to downstream consumers
+type EventPublisher interface {
+	// Publish delivers message to topic, returning once the destination accepted it
+	Publish(ctx context.Context, topic string, message Message) error
+
+	// Close closes the publisher connection
+	Close() error
+}
@@ -0,0 +1,92 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/segmentio/kafka-go"
+
+	"internal-transfer-microservice/internal/config"
+	"internal-transfer-microservice/pkg/logger"
+)
+
+// KafkaPublisher implements EventPublisher for Kafka-protocol brokers
+type KafkaPublisher struct {
+	cfg     *config.Config
+	mu      sync.Mutex
+	writers map[string]*kafka.Writer
+}
+
+// Publish writes the message to the topic partition of its key, waiting for all in-sync replicas
+func (k *KafkaPublisher) Publish(ctx context.Context, topic string, message Message) error {
+	headers := make([]kafka.Header, 0, len(message.Headers))
+	for key, value := range message.Headers {
+		headers = append(headers, kafka.Header{Key: key, Value: []byte(value)})
+	}
+	return k.writer(topic).WriteMessages(ctx, kafka.Message{
+		Key:     []byte(message.Key),
+		Value:   message.Payload,
+		Headers: headers,
+	})
+}
+
+// writer returns the writer of topic, creating it on first use
+func (k *KafkaPublisher) writer(topic string) *kafka.Writer {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	writer, ok := k.writers[topic]
+	if !ok {
+		writer = kafka.NewWriter(kafka.WriterConfig{
+			Brokers: k.cfg.GetKafkaBrokers(),
+			Topic:   topic,
+			// messages with the same key, an account id, share a partition and keep their order
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: -1,
+			// one message in flight at a time, so a retried write cannot overtake the next one
+			BatchSize:    1,
+			WriteTimeout: k.cfg.GetKafkaWriteTimeout(),
+		})
+		k.writers[topic] = writer
+	}
+	return writer
+}
+
+// Close closes every writer
+func (k *KafkaPublisher) Close() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	var firstErr error
+	for topic, writer := range k.writers {
+		if err := writer.Close(); err != nil {
+			logger.Errorf("Failed to close Kafka writer for %s: %v", topic, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// NewKafkaPublisher creates a publisher writing to the configured Kafka brokers
+func NewKafkaPublisher(cfg *config.Config) (EventPublisher, error) {
+	if len(cfg.GetKafkaBrokers()) == 0 {
+		return nil, errors.New("no Kafka brokers configured")
+	}
+
+	// dial once so a wrong broker list fails at startup rather than on the first event
+	conn, err := kafka.Dial("tcp", cfg.GetKafkaBrokers()[0])
+	if err != nil {
+		return nil, err
+	}
+	conn.Close()
+
+	logger.Infof("Publishing events to Kafka brokers %v", cfg.GetKafkaBrokers())
+
+	return &KafkaPublisher{
+		cfg:     cfg,
+		writers: make(map[string]*kafka.Writer),
+	}, nil
+}
@@ -0,0 +1,30 @@
+package publisher
+
+import (
+	"context"
+
+	"internal-transfer-microservice/pkg/logger"
+)
+
+// LogPublisher implements EventPublisher by writing every message to the application log
+type LogPublisher struct{}
+
+// Publish logs the message
+func (l *LogPublisher) Publish(ctx context.Context, topic string, message Message) error {
+	logger.WithFields(logger.Fields{
+		"topic":   topic,
+		"key":     message.Key,
+		"headers": message.Headers,
+	}).Info(string(message.Payload))
+	return nil
+}
+
+// Close does nothing
+func (l *LogPublisher) Close() error {
+	return nil
+}
+
+// NewLogPublisher creates a publisher that only logs events
+func NewLogPublisher() EventPublisher {
+	return &LogPublisher{}
+}
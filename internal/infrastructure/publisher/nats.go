@@ -0,0 +1,69 @@
+package publisher
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"internal-transfer-microservice/internal/config"
+	"internal-transfer-microservice/pkg/logger"
+)
+
+// NATSPublisher implements EventPublisher for a NATS server
+type NATSPublisher struct {
+	conn         *nats.Conn
+	partitions   int
+	flushTimeout time.Duration
+}
+
+// Publish sends the message on the subject of its key's partition and waits for the server to receive it
+func (n *NATSPublisher) Publish(ctx context.Context, topic string, message Message) error {
+	msg := nats.NewMsg(n.subject(topic, message.Key))
+	msg.Data = message.Payload
+	msg.Header.Set("Nats-Msg-Id", message.Headers["event_id"])
+	for key, value := range message.Headers {
+		msg.Header.Set(key, value)
+	}
+	if err := n.conn.PublishMsg(msg); err != nil {
+		return err
+	}
+
+	flushCtx, cancel := context.WithTimeout(ctx, n.flushTimeout)
+	defer cancel()
+	return n.conn.FlushWithContext(flushCtx)
+}
+
+// subject returns the subject carrying the events of key; NATS keeps the order of messages on one subject
+func (n *NATSPublisher) subject(topic, key string) string {
+	if n.partitions <= 1 {
+		return topic
+	}
+	return topic + "." + strconv.Itoa(PartitionFor(key, n.partitions))
+}
+
+// Close drains and closes the NATS connection
+func (n *NATSPublisher) Close() error {
+	return n.conn.Drain()
+}
+
+// NewNATSPublisher creates a publisher connected to the configured NATS server
+func NewNATSPublisher(cfg *config.Config) (EventPublisher, error) {
+	conn, err := nats.Connect(cfg.GetNATSURL(), nats.Name("internal-transfer-microservice"))
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Publishing events to NATS at %s", conn.ConnectedUrl())
+
+	flushTimeout := cfg.GetNATSFlushTimeout()
+	if flushTimeout <= 0 {
+		flushTimeout = 5 * time.Second
+	}
+	return &NATSPublisher{
+		conn:         conn,
+		partitions:   cfg.GetNATSPartitions(),
+		flushTimeout: flushTimeout,
+	}, nil
+}
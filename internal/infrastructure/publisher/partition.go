@@ -0,0 +1,14 @@
+package publisher
+
+import "hash/fnv"
+
+// PartitionFor maps key onto one of n partitions with FNV-1a, the hash Kafka's hash balancer uses,
+// so events with the same key always land on the same partition
+func PartitionFor(key string, n int) int {
+	if n <= 1 {
+		return 0
+	}
+	hasher := fnv.New32a()
+	hasher.Write([]byte(key))
+	return int(hasher.Sum32() % uint32(n))
+}
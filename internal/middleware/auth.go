@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"internal-transfer-microservice/internal/auth"
+)
+
+// bearerPrefix starts the Authorization header of requests carrying a bearer token
+const bearerPrefix = "Bearer "
+
+// Authenticate puts the principal of the bearer token in the Authorization header into the request
+// context, in place of the X-Operator-Id header. Requests without a valid token are refused with 401,
+// and those whose token lacks a required scope with 403.
+func Authenticate(verifier auth.Verifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if len(header) < len(bearerPrefix) || !strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+			c.Header("WWW-Authenticate", `Bearer`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "A bearer token is required"})
+			return
+		}
+
+		principal, err := verifier.Verify(c.Request.Context(), strings.TrimSpace(header[len(bearerPrefix):]))
+		switch {
+		case errors.Is(err, auth.ErrInsufficientScope):
+			c.Header("WWW-Authenticate", `Bearer error="insufficient_scope"`)
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+// RequireScope refuses with 403 the requests whose principal, put into the request context by Authenticate,
+// was not granted scope
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, _ := auth.PrincipalFromContext(c.Request.Context())
+		if !principal.HasScope(scope) {
+			c.Header("WWW-Authenticate", `Bearer error="insufficient_scope"`)
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "The " + scope + " scope is required"})
+			return
+		}
+		c.Next()
+	}
+}
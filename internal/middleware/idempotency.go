@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"internal-transfer-microservice/internal/auth"
+	"internal-transfer-microservice/internal/infrastructure/cache"
+	"internal-transfer-microservice/pkg/logger"
+)
+
+const (
+	// IdempotencyHeader carries the key that makes retrying a POST safe
+	IdempotencyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from an earlier request with the same key
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// MaxIdempotencyKeyLength bounds the keys clients may send
+	MaxIdempotencyKeyLength = 255
+)
+
+// Error codes of the requests Idempotency refuses
+const (
+	CodeIdempotencyKeyInUse  = "IDEMPOTENCY_KEY_IN_USE"
+	CodeIdempotencyKeyReused = "IDEMPOTENCY_KEY_REUSED"
+)
+
+const idempotencyCacheKey = "idempotency:%s:%s"
+
+// idempotencyLockTTL bounds how long a key stays locked by a request that never finishes
+const idempotencyLockTTL = time.Minute
+
+// idempotentResponse is a stored response, with the fingerprint of the request that produced it
+type idempotentResponse struct {
+	Fingerprint string `json:"fingerprint"`
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+// Idempotency makes POST requests carrying an Idempotency-Key header safe to retry. The first response
+// to a key is stored in store for ttl and replayed to every retry, so a transfer is executed once however
+// often it is sent. Responses with a 5xx status are not stored, and their requests may be retried for real.
+//
+// Keys are scoped to the principal of the request. A retry sent while the first request is still being
+// processed is refused with 409, and a key reused for a different request with 422.
+func Idempotency(store cache.Cache, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyHeader)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > MaxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("%s must not be longer than %d characters", IdempotencyHeader, MaxIdempotencyKeyLength),
+			})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		fingerprint := requestFingerprint(c.Request, body)
+
+		ctx := c.Request.Context()
+		cacheKey := fmt.Sprintf(idempotencyCacheKey, auth.SubjectFromContext(ctx), key)
+		acquired, err := store.Lock(ctx, cacheKey, idempotencyLockTTL)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if !acquired {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+				"error":      "A request with this " + IdempotencyHeader + " is still being processed",
+				"error_code": CodeIdempotencyKeyInUse,
+			})
+			return
+		}
+		defer store.Release(ctx, cacheKey)
+
+		// the stored response is only read under the lock, so it cannot be stored in between
+		if raw, err := store.Get(ctx, cacheKey); err == nil {
+			var stored idempotentResponse
+			if json.Unmarshal([]byte(raw), &stored) == nil {
+				replay(c, &stored, fingerprint)
+				return
+			}
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		if recorder.Status() >= http.StatusInternalServerError {
+			return
+		}
+		stored, _ := json.Marshal(idempotentResponse{
+			Fingerprint: fingerprint,
+			Status:      recorder.Status(),
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		})
+		if err := store.Set(ctx, cacheKey, string(stored), ttl); err != nil {
+			logger.Warnf("Failed to store the response to %s %s for its %s: %v", c.Request.Method, c.Request.URL.Path, IdempotencyHeader, err)
+		}
+	}
+}
+
+// replay answers with the stored response, unless it was produced by a different request
+func replay(c *gin.Context, stored *idempotentResponse, fingerprint string) {
+	if stored.Fingerprint != fingerprint {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+			"error":      IdempotencyHeader + " was already used for a different request",
+			"error_code": CodeIdempotencyKeyReused,
+		})
+		return
+	}
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Data(stored.Status, stored.ContentType, stored.Body)
+	c.Abort()
+}
+
+// requestFingerprint identifies a request by its method, path and body
+func requestFingerprint(req *http.Request, body []byte) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %s\n", req.Method, req.URL.Path)
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
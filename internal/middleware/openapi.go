@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+)
+
+// openAPIValidator finds the operations of requests in an OpenAPI document and validates them against it
+type openAPIValidator struct {
+	router  routers.Router
+	options *openapi3filter.Options
+	// responseOptions also refuse responses with a status the operation does not list
+	responseOptions *openapi3filter.Options
+}
+
+func newOpenAPIValidator(spec *openapi3.T) (*openAPIValidator, error) {
+	router, err := gorillamux.NewRouter(spec)
+	if err != nil {
+		return nil, err
+	}
+	options := &openapi3filter.Options{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc}
+	// name the offending field instead of dumping the schema and value into the error
+	options.WithCustomSchemaErrorFunc(func(err *openapi3.SchemaError) string {
+		if pointer := err.JSONPointer(); len(pointer) > 0 {
+			return strings.Join(pointer, ".") + ": " + err.Reason
+		}
+		return err.Reason
+	})
+	responseOptions := *options
+	responseOptions.IncludeResponseStatus = true
+	return &openAPIValidator{router: router, options: options, responseOptions: &responseOptions}, nil
+}
+
+// requestInput returns what validating the request takes, or nil when the document does not describe it
+func (v *openAPIValidator) requestInput(req *http.Request) *openapi3filter.RequestValidationInput {
+	route, pathParams, err := v.router.FindRoute(req)
+	if err != nil {
+		return nil
+	}
+	return &openapi3filter.RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+		Options:    v.options,
+	}
+}
+
+// ValidateRequests refuses requests that do not match their operation in spec with 400. Requests to
+// routes spec does not describe are passed through.
+func ValidateRequests(spec *openapi3.T) (gin.HandlerFunc, error) {
+	validator, err := newOpenAPIValidator(spec)
+	if err != nil {
+		return nil, err
+	}
+	return func(c *gin.Context) {
+		input := validator.requestInput(c.Request)
+		if input == nil {
+			c.Next()
+			return
+		}
+		// the validator puts back the body it reads, so handlers can bind it
+		if err := openapi3filter.ValidateRequest(c.Request.Context(), input); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.Next()
+	}, nil
+}
+
+// responseRecorder keeps a copy of the response body written through it
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+// ValidateResponses passes every response that does not match its operation in spec to report, after it
+// was sent unchanged. Meant for tests and for spotting drift, as it keeps a copy of every response body.
+func ValidateResponses(spec *openapi3.T, report func(req *http.Request, err error)) (gin.HandlerFunc, error) {
+	validator, err := newOpenAPIValidator(spec)
+	if err != nil {
+		return nil, err
+	}
+	return func(c *gin.Context) {
+		input := validator.requestInput(c.Request)
+		if input == nil {
+			c.Next()
+			return
+		}
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		err := openapi3filter.ValidateResponse(c.Request.Context(), (&openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 recorder.Status(),
+			Header:                 recorder.Header(),
+			Options:                validator.responseOptions,
+		}).SetBodyBytes(recorder.body.Bytes()))
+		if err != nil {
+			report(c.Request, err)
+		}
+	}, nil
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"internal-transfer-microservice/internal/auth"
+)
+
+// OperatorHeader carries the identity of the operator calling the API
+const OperatorHeader = "X-Operator-Id"
+
+// OperatorIdentity puts the operator named in the X-Operator-Id header into the request context.
+// The header is trusted as sent, so it must only be reachable through an authenticating proxy.
+func OperatorIdentity() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if operator := c.GetHeader(OperatorHeader); operator != "" {
+			ctx := auth.WithPrincipal(c.Request.Context(), auth.Principal{Subject: operator})
+			c.Request = c.Request.WithContext(ctx)
+		}
+		c.Next()
+	}
+}
@@ -2,12 +2,29 @@ package repository
 
 import (
 	"context"
-	"errors"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/transfer"
 	"internal-transfer-microservice/internal/infrastructure/db"
+	"time"
 )
 
+// AccountWriter persists the balances of accounts within a transaction, so other repositories can
+// combine balance changes with their own writes whichever account store is configured
+type AccountWriter interface {
+	SaveBalances(tx *gorm.DB, txn *transfer.Model, accounts ...*account.Model) error
+	// BalancesCommitted is called once the transaction that saved the balances of accounts has committed
+	BalancesCommitted(ctx context.Context, accounts ...*account.Model)
+}
+
+// AccountStore is an account repository whose balance writes can join another repository's transaction
+type AccountStore interface {
+	account.Repository
+	AccountWriter
+}
+
 type AccountRepoImpl struct {
 	db db.Database
 }
@@ -17,15 +34,14 @@ func (a *AccountRepoImpl) GetConn() *gorm.DB {
 	return a.db.GetConnection()
 }
 
-func (a *AccountRepoImpl) UpdateAccountsInTx(ctx context.Context, srcAccount *account.Model, destAccount *account.Model) error {
+// GetReadConn Helper to get a connection for read-only queries, served by a replica unless ctx is pinned to the primary
+func (a *AccountRepoImpl) GetReadConn(ctx context.Context) *gorm.DB {
+	return a.db.GetReadConnection(ctx).WithContext(ctx)
+}
+
+func (a *AccountRepoImpl) UpdateAccountsInTx(ctx context.Context, txn *transfer.Model, accounts ...*account.Model) error {
 	err := a.GetConn().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		if err := tx.Save(srcAccount).Error; err != nil {
-			return err
-		}
-		if err := tx.Save(destAccount).Error; err != nil {
-			return err
-		}
-		return nil
+		return updateAccountsInTx(tx, a, txn, accounts...)
 	})
 	if err != nil {
 		return err
@@ -33,15 +49,64 @@ func (a *AccountRepoImpl) UpdateAccountsInTx(ctx context.Context, srcAccount *ac
 	return nil
 }
 
+func (a *AccountRepoImpl) SaveBalances(tx *gorm.DB, txn *transfer.Model, accounts ...*account.Model) error {
+	for _, acc := range accounts {
+		if err := saveBalance(tx, acc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BalancesCommitted has nothing to do, as accounts are always read from the database
+func (a *AccountRepoImpl) BalancesCommitted(ctx context.Context, accounts ...*account.Model) {}
+
+// updateAccountsInTx saves the balances of accounts with writer, then the transfer record with its limit counters and outbox event
+func updateAccountsInTx(tx *gorm.DB, writer AccountWriter, txn *transfer.Model, accounts ...*account.Model) error {
+	if err := writer.SaveBalances(tx, txn, accounts...); err != nil {
+		return err
+	}
+	if txn == nil {
+		return nil
+	}
+	// transfers held for approval already have a record, which is updated in place
+	if err := tx.Save(txn).Error; err != nil {
+		return err
+	}
+	// only completed customer transfers count towards velocity limits
+	if txn.Type == transfer.TypeTransfer && txn.Status == transfer.StatusCompleted && txn.ExecutedAt != nil {
+		if err := incrementLimitCounters(tx, txn.SourceAccountId, txn.Amount, *txn.ExecutedAt, txn.LimitCaps); err != nil {
+			return err
+		}
+	}
+	return recordTransferOutcome(tx, txn, accounts...)
+}
+
 func (a *AccountRepoImpl) GetAccount(ctx context.Context, accountId string) (*account.Model, error) {
 	var acc account.Model
-	err := a.GetConn().First(&acc, "account_id = ?", accountId)
+	err := a.GetReadConn(ctx).First(&acc, "account_id = ?", accountId)
 	if err.Error != nil {
 		return nil, err.Error
 	}
 	return &acc, nil
 }
 
+func (a *AccountRepoImpl) ListAccounts(ctx context.Context, ownerId, after string, limit int) ([]account.Model, error) {
+	var accounts []account.Model
+	query := a.GetReadConn(ctx).Where("account_id > ?", after).Order("account_id")
+	if ownerId != "" {
+		query = query.Where("owner_id = ?", ownerId)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&accounts)
+	if err.Error != nil {
+		return nil, err.Error
+	}
+	return accounts, nil
+}
+
 func (a *AccountRepoImpl) UpdateAccount(ctx context.Context, account *account.Model) error {
 	err := a.GetConn().Save(account)
 	if err.Error != nil {
@@ -54,12 +119,155 @@ func (a *AccountRepoImpl) CreateAccount(ctx context.Context, accountModel *accou
 	var tempAccount *account.Model
 	err := a.GetConn().First(&tempAccount, "account_id = ?", accountModel.AccountId)
 	if err.Error == nil {
-		return errors.New("account already exists")
+		return account.ErrAccountExists
 	}
-	err = a.GetConn().Create(accountModel)
+	return a.GetConn().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(accountModel).Error; err != nil {
+			return err
+		}
+		return recordAccountCreated(tx, accountModel)
+	})
+}
+
+func (a *AccountRepoImpl) GetTransferHistory(ctx context.Context, accountId string, limit int) ([]transfer.Model, error) {
+	var transfers []transfer.Model
+	err := a.GetReadConn(ctx).
+		Where("source_account_id = ? OR destination_account_id = ?", accountId, accountId).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&transfers)
 	if err.Error != nil {
-		return err.Error
+		return nil, err.Error
+	}
+	return transfers, nil
+}
+
+func (a *AccountRepoImpl) GetOutgoingVelocity(ctx context.Context, accountId string, since time.Time) (int, float64, error) {
+	var velocity struct {
+		Count  int
+		Amount float64
+	}
+	err := a.GetReadConn(ctx).Model(&transfer.Model{}).
+		Select("COUNT(*) AS count, COALESCE(SUM(amount), 0) AS amount").
+		Where("source_account_id = ? AND type = ? AND status = ? AND executed_at >= ?",
+			accountId, transfer.TypeTransfer, transfer.StatusCompleted, since).
+		Scan(&velocity)
+	if err.Error != nil {
+		return 0, 0, err.Error
+	}
+	return velocity.Count, velocity.Amount, nil
+}
+
+func (a *AccountRepoImpl) GetTransfer(ctx context.Context, transferId uuid.UUID) (*transfer.Model, error) {
+	var txn transfer.Model
+	err := a.GetReadConn(ctx).First(&txn, "id = ?", transferId)
+	if err.Error != nil {
+		return nil, err.Error
+	}
+	return &txn, nil
+}
+
+func (a *AccountRepoImpl) ListTransfersByStatus(ctx context.Context, statuses []string, limit int) ([]transfer.Model, error) {
+	var transfers []transfer.Model
+	err := a.GetReadConn(ctx).
+		Where("status IN ?", statuses).
+		Order("created_at").
+		Limit(limit).
+		Find(&transfers)
+	if err.Error != nil {
+		return nil, err.Error
+	}
+	return transfers, nil
+}
+
+func (a *AccountRepoImpl) ExpireTransfers(ctx context.Context, statuses []string, before time.Time) (int64, error) {
+	var expired int64
+	err := a.GetConn().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var transfers []transfer.Model
+		if err := tx.Where("status IN ? AND expires_at < ?", statuses, before).Find(&transfers).Error; err != nil {
+			return err
+		}
+		for i := range transfers {
+			txn := &transfers[i]
+			// a decision made since the read wins over the expiry
+			result := tx.Model(&transfer.Model{}).
+				Where("id = ? AND status IN ?", txn.ID, statuses).
+				Update("status", transfer.StatusExpired)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				continue
+			}
+			txn.Status = transfer.StatusExpired
+			if err := recordTransferOutcome(tx, txn); err != nil {
+				return err
+			}
+			expired++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return expired, nil
+}
+
+func (a *AccountRepoImpl) UpdateOverdraftLimit(ctx context.Context, acc *account.Model, change *account.OverdraftLimitChange) error {
+	return a.GetConn().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(acc).
+			Where("overdraft_limit_version = ?", change.Version-1).
+			Updates(map[string]interface{}{
+				"overdraft_limit":         change.NewLimit,
+				"overdraft_limit_version": change.Version,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return account.ErrVersionConflict
+		}
+		return recordOverdraftLimitChange(tx, change)
+	})
+}
+
+func (a *AccountRepoImpl) SetFrozen(ctx context.Context, acc *account.Model, changedBy, reason string) error {
+	return a.GetConn().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(acc).Update("frozen", acc.Frozen)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return recordFreezeChange(tx, acc, changedBy, reason)
+	})
+}
+
+func (a *AccountRepoImpl) GetOverdraftLimitHistory(ctx context.Context, accountId string) ([]account.OverdraftLimitChange, error) {
+	var changes []account.OverdraftLimitChange
+	err := a.GetReadConn(ctx).Where("account_id = ?", accountId).Order("version DESC").Find(&changes)
+	if err.Error != nil {
+		return nil, err.Error
+	}
+	return changes, nil
+}
+
+// saveBalance applies the balance change of acc to the stored balance, refusing it when the result would breach
+// the overdraft limit stored for the account. acc gets the resulting balance, with the changes of concurrent writers.
+func saveBalance(tx *gorm.DB, acc *account.Model) error {
+	change := acc.BalanceChange()
+	result := tx.Model(acc).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "balance"}}}).
+		Where("account_id = ? AND balance + ? >= -overdraft_limit", acc.AccountId, change).
+		Update("balance", gorm.Expr("balance + ?", change))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return account.ErrOverdraftLimitExceeded
 	}
+	acc.BalanceSaved(acc.Balance)
 	return nil
 }
 
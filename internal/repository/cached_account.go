@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
+	"gorm.io/gorm"
+
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/transfer"
+	"internal-transfer-microservice/internal/infrastructure/cache"
+	"internal-transfer-microservice/internal/infrastructure/db"
+	"internal-transfer-microservice/pkg/logger"
+)
+
+const (
+	accountCacheKey           = "account:%s"
+	accountCacheGenerationKey = "account:%s:generation"
+)
+
+// AccountCacheStatsName is the name the account cache publishes its hit rate under
+const AccountCacheStatsName = "accounts"
+
+// cachedAccount is a cache entry: the account, or nil when it does not exist, as read under Generation
+type cachedAccount struct {
+	Generation string         `json:"generation"`
+	Account    *account.Model `json:"account,omitempty"`
+	// Version is not part of the account's JSON form, but the event-sourced store checks it on writes
+	Version int `json:"version"`
+}
+
+// CachedAccountRepoImpl puts a read-through cache in front of GetAccount of another account store.
+//
+// Every write replaces the account's generation key once committed, and entries are only served while
+// they carry the current generation. A lookup reads the generation before the database, so an entry
+// loaded while a write was committing is never served afterwards, and transfers never see a stale balance.
+// Balances saved in another repository's transaction are invalidated by BalancesCommitted.
+type CachedAccountRepoImpl struct {
+	AccountStore
+	cache       cache.Cache
+	ttl         time.Duration
+	negativeTTL time.Duration
+	loads       singleflight.Group
+	stats       *cache.Stats
+}
+
+func (c *CachedAccountRepoImpl) GetAccount(ctx context.Context, accountId string) (*account.Model, error) {
+	generation := c.generation(ctx, accountId)
+	if raw, err := c.cache.Get(ctx, fmt.Sprintf(accountCacheKey, accountId)); err == nil {
+		var entry cachedAccount
+		if json.Unmarshal([]byte(raw), &entry) == nil && entry.Generation == generation {
+			if entry.Account == nil {
+				c.stats.NegativeHit()
+				return nil, gorm.ErrRecordNotFound
+			}
+			c.stats.Hit()
+			entry.Account.Version = entry.Version
+			return entry.Account, nil
+		}
+	}
+	c.stats.Miss()
+
+	// concurrent misses of the same account share one database read, which outlives a caller giving up.
+	// A read started under an older generation may predate a write, so it is not shared with later lookups.
+	// It is made on the primary, as a lagging replica could return a balance older than the generation.
+	loaded, err, _ := c.loads.Do(accountId+"@"+generation, func() (interface{}, error) {
+		return c.load(db.WithPrimary(context.WithoutCancel(ctx)), accountId, generation)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// callers change the accounts they get, so each gets its own copy
+	acc := *loaded.(*account.Model)
+	return &acc, nil
+}
+
+// load reads the account from the store and caches it under generation, which was read before, or caches
+// that it does not exist
+func (c *CachedAccountRepoImpl) load(ctx context.Context, accountId, generation string) (*account.Model, error) {
+	c.stats.Load()
+	acc, err := c.AccountStore.GetAccount(ctx, accountId)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	entry := cachedAccount{Generation: generation, Account: acc}
+	ttl := c.negativeTTL
+	if acc != nil {
+		entry.Version = acc.Version
+		ttl = c.ttl
+	}
+	if ttl > 0 {
+		if raw, marshalErr := json.Marshal(entry); marshalErr == nil {
+			if setErr := c.cache.Set(ctx, fmt.Sprintf(accountCacheKey, accountId), string(raw), ttl); setErr != nil {
+				c.stats.Error()
+			}
+		}
+	}
+	return acc, err
+}
+
+// generation returns the account's current generation, empty when no write replaced it within the TTL
+func (c *CachedAccountRepoImpl) generation(ctx context.Context, accountId string) string {
+	generation, err := c.cache.Get(ctx, fmt.Sprintf(accountCacheGenerationKey, accountId))
+	if err != nil {
+		return ""
+	}
+	return generation
+}
+
+// invalidate replaces the generation of every account, so their cached entries are no longer served
+func (c *CachedAccountRepoImpl) invalidate(ctx context.Context, accountIds ...string) {
+	// the generation must outlive every entry read under the previous one
+	ttl := 2 * c.ttl
+	if c.negativeTTL > c.ttl {
+		ttl = 2 * c.negativeTTL
+	}
+	for _, accountId := range accountIds {
+		if err := c.cache.Set(ctx, fmt.Sprintf(accountCacheGenerationKey, accountId), uuid.NewString(), ttl); err != nil {
+			c.stats.Error()
+			logger.Errorf("Failed to invalidate cached account %s: %v", accountId, err)
+			c.cache.Delete(ctx, fmt.Sprintf(accountCacheKey, accountId))
+		}
+	}
+}
+
+func accountIdsOf(accounts []*account.Model) []string {
+	accountIds := make([]string, len(accounts))
+	for i, acc := range accounts {
+		accountIds[i] = acc.AccountId
+	}
+	return accountIds
+}
+
+func (c *CachedAccountRepoImpl) CreateAccount(ctx context.Context, acc *account.Model) error {
+	err := c.AccountStore.CreateAccount(ctx, acc)
+	// a lookup may have cached that the account did not exist
+	c.invalidate(ctx, acc.AccountId)
+	return err
+}
+
+func (c *CachedAccountRepoImpl) UpdateAccount(ctx context.Context, acc *account.Model) error {
+	err := c.AccountStore.UpdateAccount(ctx, acc)
+	c.invalidate(ctx, acc.AccountId)
+	return err
+}
+
+func (c *CachedAccountRepoImpl) UpdateAccountsInTx(ctx context.Context, txn *transfer.Model, accounts ...*account.Model) error {
+	err := c.AccountStore.UpdateAccountsInTx(ctx, txn, accounts...)
+	c.invalidate(ctx, accountIdsOf(accounts)...)
+	return err
+}
+
+func (c *CachedAccountRepoImpl) UpdateOverdraftLimit(ctx context.Context, acc *account.Model, change *account.OverdraftLimitChange) error {
+	err := c.AccountStore.UpdateOverdraftLimit(ctx, acc, change)
+	c.invalidate(ctx, acc.AccountId)
+	return err
+}
+
+func (c *CachedAccountRepoImpl) SetFrozen(ctx context.Context, acc *account.Model, changedBy, reason string) error {
+	err := c.AccountStore.SetFrozen(ctx, acc, changedBy, reason)
+	c.invalidate(ctx, acc.AccountId)
+	return err
+}
+
+func (c *CachedAccountRepoImpl) BalancesCommitted(ctx context.Context, accounts ...*account.Model) {
+	c.AccountStore.BalancesCommitted(ctx, accounts...)
+	c.invalidate(ctx, accountIdsOf(accounts)...)
+}
+
+// NewCachedAccountRepo caches the accounts read from store for ttl, and missing accounts for negativeTTL
+func NewCachedAccountRepo(store AccountStore, accountCache cache.Cache, ttl, negativeTTL time.Duration) *CachedAccountRepoImpl {
+	return &CachedAccountRepoImpl{
+		AccountStore: store,
+		cache:        accountCache,
+		ttl:          ttl,
+		negativeTTL:  negativeTTL,
+		stats:        cache.NewStats(AccountCacheStatsName),
+	}
+}
@@ -0,0 +1,291 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/eventstore"
+	"internal-transfer-microservice/internal/domain/transfer"
+	"internal-transfer-microservice/internal/infrastructure/db"
+)
+
+// EventSourcedAccountRepoImpl stores accounts as append-only event streams. The accounts table is kept
+// as a projection, updated in the same transaction as every append, and doubles as the stream head
+// locked while appending. Transfer records and their queries are shared with AccountRepoImpl.
+type EventSourcedAccountRepoImpl struct {
+	*AccountRepoImpl
+	snapshotEvery int
+}
+
+func (e *EventSourcedAccountRepoImpl) GetAccount(ctx context.Context, accountId string) (*account.Model, error) {
+	return e.rehydrate(e.GetReadConn(ctx), accountId)
+}
+
+// rehydrate rebuilds an account from its latest snapshot and the events recorded after it
+func (e *EventSourcedAccountRepoImpl) rehydrate(tx *gorm.DB, accountId string) (*account.Model, error) {
+	var snapshots []eventstore.Snapshot
+	if err := tx.Where("account_id = ?", accountId).Limit(1).Find(&snapshots).Error; err != nil {
+		return nil, err
+	}
+	var snapshot *eventstore.Snapshot
+	after := 0
+	if len(snapshots) > 0 {
+		snapshot, after = &snapshots[0], snapshots[0].Version
+	}
+
+	var events []eventstore.Event
+	err := tx.Where("account_id = ? AND version > ?", accountId, after).Order("version").Find(&events)
+	if err.Error != nil {
+		return nil, err.Error
+	}
+	acc, rehydrateErr := eventstore.Rehydrate(snapshot, events)
+	if errors.Is(rehydrateErr, eventstore.ErrStreamNotFound) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return acc, rehydrateErr
+}
+
+func (e *EventSourcedAccountRepoImpl) CreateAccount(ctx context.Context, accountModel *account.Model) error {
+	var existing []account.Model
+	if err := e.GetConn().WithContext(ctx).Where("account_id = ?", accountModel.AccountId).Limit(1).Find(&existing).Error; err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return account.ErrAccountExists
+	}
+
+	return e.GetConn().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if accountModel.ID == uuid.Nil {
+			accountModel.ID = uuid.New()
+		}
+		opened := &account.Model{AccountId: accountModel.AccountId}
+		opened.ID = accountModel.ID
+		if err := tx.Create(opened).Error; err != nil {
+			return err
+		}
+		if err := e.append(tx, opened, nil, eventstore.Change{Type: eventstore.TypeOpened, Data: openedFrom(accountModel)}); err != nil {
+			return err
+		}
+		*accountModel = *opened
+		return recordAccountCreated(tx, accountModel)
+	})
+}
+
+func openedFrom(acc *account.Model) eventstore.Opened {
+	return eventstore.Opened{
+		Id:                    acc.ID,
+		HolderName:            acc.HolderName,
+		OwnerId:               acc.OwnerId,
+		AccountType:           acc.AccountType,
+		RatePlanId:            acc.RatePlanId,
+		Tier:                  acc.Tier,
+		InitialBalance:        acc.Balance,
+		OverdraftLimit:        acc.OverdraftLimit,
+		OverdraftLimitVersion: acc.OverdraftLimitVersion,
+		Frozen:                acc.Frozen,
+	}
+}
+
+func (e *EventSourcedAccountRepoImpl) UpdateAccount(ctx context.Context, acc *account.Model) error {
+	return e.GetConn().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		head, err := lockStreamHead(tx, acc)
+		if err != nil {
+			return err
+		}
+		if err := e.append(tx, head, nil, eventstore.Changes(head, acc)...); err != nil {
+			return err
+		}
+		*acc = *head
+		return nil
+	})
+}
+
+func (e *EventSourcedAccountRepoImpl) UpdateAccountsInTx(ctx context.Context, txn *transfer.Model, accounts ...*account.Model) error {
+	return e.GetConn().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return updateAccountsInTx(tx, e, txn, accounts...)
+	})
+}
+
+// SaveBalances appends a Credited or Debited event for the balance change of every account to the head of its
+// stream, refusing balances that would breach the overdraft limit of the stream. Balance changes apply to the
+// head whatever was appended since the account was read, as transfers do not lock the revenue account.
+func (e *EventSourcedAccountRepoImpl) SaveBalances(tx *gorm.DB, txn *transfer.Model, accounts ...*account.Model) error {
+	var transferId *uuid.UUID
+	if txn != nil {
+		// the record is created after the balances, so its id is settled here
+		if txn.ID == uuid.Nil {
+			txn.ID = uuid.New()
+		}
+		transferId = &txn.ID
+	}
+
+	for _, acc := range accounts {
+		head, err := lockHead(tx, acc.AccountId)
+		if err != nil {
+			return err
+		}
+		acc.Balance = head.Balance + acc.BalanceChange()
+		if acc.Balance < -head.OverdraftLimit {
+			return account.ErrOverdraftLimitExceeded
+		}
+
+		var changes []eventstore.Change
+		for _, change := range eventstore.Changes(head, acc) {
+			if change.Type == eventstore.TypeCredited || change.Type == eventstore.TypeDebited {
+				changes = append(changes, change)
+			}
+		}
+		if err := e.append(tx, head, transferId, changes...); err != nil {
+			return err
+		}
+		acc.Version, acc.UpdatedAt = head.Version, head.UpdatedAt
+		acc.BalanceSaved(head.Balance)
+	}
+	return nil
+}
+
+func (e *EventSourcedAccountRepoImpl) UpdateOverdraftLimit(ctx context.Context, acc *account.Model, change *account.OverdraftLimitChange) error {
+	return e.GetConn().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		head, err := lockStreamHead(tx, acc)
+		if err != nil {
+			return err
+		}
+		if head.OverdraftLimitVersion != change.Version-1 {
+			return account.ErrVersionConflict
+		}
+		err = e.append(tx, head, nil, eventstore.Change{Type: eventstore.TypeOverdraftLimitChanged, Data: eventstore.OverdraftLimitChanged{
+			PreviousLimit: change.PreviousLimit,
+			Limit:         change.NewLimit,
+			LimitVersion:  change.Version,
+		}})
+		if err != nil {
+			return err
+		}
+		*acc = *head
+		return recordOverdraftLimitChange(tx, change)
+	})
+}
+
+func (e *EventSourcedAccountRepoImpl) SetFrozen(ctx context.Context, acc *account.Model, changedBy, reason string) error {
+	return e.GetConn().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		head, err := lockStreamHead(tx, acc)
+		if err != nil {
+			return err
+		}
+		if head.Frozen != acc.Frozen {
+			change := eventstore.Change{Type: eventstore.TypeUnfrozen, Data: eventstore.Unfrozen{}}
+			if acc.Frozen {
+				change = eventstore.Change{Type: eventstore.TypeFrozen, Data: eventstore.Frozen{Reason: reason}}
+			}
+			if err := e.append(tx, head, nil, change); err != nil {
+				return err
+			}
+		}
+		frozen := acc.Frozen
+		*acc = *head
+		acc.Frozen = frozen
+		return recordFreezeChange(tx, acc, changedBy, reason)
+	})
+}
+
+// SeedStreams opens a stream for every account row that has none, with the row's current state,
+// so an existing database can switch to the event-sourced store. It returns the number of streams opened.
+func (e *EventSourcedAccountRepoImpl) SeedStreams(ctx context.Context) (int, error) {
+	var accounts []account.Model
+	if err := e.GetConn().WithContext(ctx).Where("version = 0").Order("account_id").Find(&accounts).Error; err != nil {
+		return 0, err
+	}
+
+	seeded := 0
+	for i := range accounts {
+		err := e.GetConn().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			head, err := lockStreamHead(tx, &accounts[i])
+			if err != nil || head.Version != 0 {
+				// the account was opened through the event store in the meantime
+				return err
+			}
+			return e.append(tx, head, nil, eventstore.Change{Type: eventstore.TypeOpened, Data: openedFrom(head)})
+		})
+		if err != nil {
+			return seeded, err
+		}
+		seeded++
+	}
+	return seeded, nil
+}
+
+// lockStreamHead locks the projection row of acc, which serialises appends to its stream,
+// and fails with ErrVersionConflict if events were appended since acc was read
+func lockStreamHead(tx *gorm.DB, acc *account.Model) (*account.Model, error) {
+	head, err := lockHead(tx, acc.AccountId)
+	if err != nil {
+		return nil, err
+	}
+	if head.Version != acc.Version {
+		return nil, account.ErrVersionConflict
+	}
+	return head, nil
+}
+
+// lockHead locks the projection row of an account, whatever its version
+func lockHead(tx *gorm.DB, accountId string) (*account.Model, error) {
+	var head account.Model
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&head, "account_id = ?", accountId).Error; err != nil {
+		return nil, err
+	}
+	return &head, nil
+}
+
+// append numbers changes after the version of head, stores them, folds them into head and saves it as the
+// projection, taking a snapshot whenever the stream crosses a multiple of snapshotEvery
+func (e *EventSourcedAccountRepoImpl) append(tx *gorm.DB, head *account.Model, transferId *uuid.UUID, changes ...eventstore.Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	previous := head.Version
+	now := time.Now().UTC()
+	events := make([]eventstore.Event, 0, len(changes))
+	for _, change := range changes {
+		event, err := eventstore.NewEvent(head.AccountId, change.Type, change.Data, transferId, now)
+		if err != nil {
+			return err
+		}
+		event.Version = head.Version + 1
+		if err := eventstore.Apply(head, event); err != nil {
+			return err
+		}
+		events = append(events, *event)
+	}
+	if err := tx.Create(&events).Error; err != nil {
+		return err
+	}
+	if err := tx.Save(head).Error; err != nil {
+		return err
+	}
+
+	if e.snapshotEvery > 0 && head.Version/e.snapshotEvery > previous/e.snapshotEvery {
+		state, err := json.Marshal(head)
+		if err != nil {
+			return err
+		}
+		snapshot := eventstore.Snapshot{AccountId: head.AccountId, Version: head.Version, State: string(state), TakenAt: now}
+		return tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&snapshot).Error
+	}
+	return nil
+}
+
+// NewEventSourcedAccountRepo creates an event-sourced account repository snapshotting every snapshotEvery events
+func NewEventSourcedAccountRepo(db db.Database, snapshotEvery int) *EventSourcedAccountRepoImpl {
+	return &EventSourcedAccountRepoImpl{
+		AccountRepoImpl: NewAccountRepo(db),
+		snapshotEvery:   snapshotEvery,
+	}
+}
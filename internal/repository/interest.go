@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/interest"
+	"internal-transfer-microservice/internal/domain/transfer"
+	"internal-transfer-microservice/internal/infrastructure/db"
+)
+
+var ErrAccrualsAlreadyPosted = errors.New("interest accruals already posted")
+
+type InterestRepoImpl struct {
+	db       db.Database
+	accounts AccountWriter
+}
+
+// GetConn Helper to get the DB connection
+func (i *InterestRepoImpl) GetConn() *gorm.DB {
+	return i.db.GetConnection()
+}
+
+func (i *InterestRepoImpl) ListInterestBearingAccounts(ctx context.Context) ([]account.Model, error) {
+	var accounts []account.Model
+	err := i.GetConn().WithContext(ctx).Where("rate_plan_id <> ''").Order("account_id").Find(&accounts)
+	if err.Error != nil {
+		return nil, err.Error
+	}
+	return accounts, nil
+}
+
+func (i *InterestRepoImpl) ListTransfersSince(ctx context.Context, accountId string, since time.Time, withFees bool) ([]transfer.Model, error) {
+	// interest postings are completed when they are created and record no execution time
+	query := i.GetConn().WithContext(ctx).
+		Where("status = ? AND COALESCE(executed_at, created_at) >= ?", transfer.StatusCompleted, since)
+	if withFees {
+		query = query.Where("(source_account_id = ? OR destination_account_id = ? OR fee > 0)", accountId, accountId)
+	} else {
+		query = query.Where("(source_account_id = ? OR destination_account_id = ?)", accountId, accountId)
+	}
+
+	var transfers []transfer.Model
+	err := query.Find(&transfers)
+	if err.Error != nil {
+		return nil, err.Error
+	}
+	return transfers, nil
+}
+
+func (i *InterestRepoImpl) CreateAccrual(ctx context.Context, accrual *interest.Accrual) (bool, error) {
+	err := i.GetConn().WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(accrual)
+	if err.Error != nil {
+		return false, err.Error
+	}
+	return err.RowsAffected > 0, nil
+}
+
+func (i *InterestRepoImpl) GetUnpostedAccruals(ctx context.Context, through string) (map[string][]interest.Accrual, error) {
+	var accruals []interest.Accrual
+	err := i.GetConn().WithContext(ctx).
+		Where("transfer_id IS NULL AND accrual_date <= ?", through).
+		Order("account_id, accrual_date").
+		Find(&accruals)
+	if err.Error != nil {
+		return nil, err.Error
+	}
+
+	byAccount := make(map[string][]interest.Accrual)
+	for _, accrual := range accruals {
+		byAccount[accrual.AccountId] = append(byAccount[accrual.AccountId], accrual)
+	}
+	return byAccount, nil
+}
+
+func (i *InterestRepoImpl) PostAccruals(ctx context.Context, txn *transfer.Model, accrualIds []uuid.UUID, accounts ...*account.Model) error {
+	err := i.GetConn().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := i.accounts.SaveBalances(tx, txn, accounts...); err != nil {
+			return err
+		}
+		if err := tx.Create(txn).Error; err != nil {
+			return err
+		}
+		if err := recordTransferOutcome(tx, txn, accounts...); err != nil {
+			return err
+		}
+		result := tx.Model(&interest.Accrual{}).
+			Where("id IN ? AND transfer_id IS NULL", accrualIds).
+			Update("transfer_id", txn.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		// another posting run got to some of these accruals first
+		if result.RowsAffected != int64(len(accrualIds)) {
+			return ErrAccrualsAlreadyPosted
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	i.accounts.BalancesCommitted(ctx, accounts...)
+	return nil
+}
+
+// NewInterestRepo creates the interest repository, posting balances through the configured account store
+func NewInterestRepo(db db.Database, accounts AccountWriter) *InterestRepoImpl {
+	return &InterestRepoImpl{
+		db:       db,
+		accounts: accounts,
+	}
+}
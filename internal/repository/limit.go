@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"internal-transfer-microservice/internal/domain/limit"
+	"internal-transfer-microservice/internal/infrastructure/db"
+)
+
+type LimitRepoImpl struct {
+	db db.Database
+}
+
+// GetConn Helper to get the DB connection
+func (l *LimitRepoImpl) GetConn() *gorm.DB {
+	return l.db.GetConnection()
+}
+
+// GetReadConn Helper to get a connection for read-only queries, served by a replica unless ctx is pinned to the primary
+func (l *LimitRepoImpl) GetReadConn(ctx context.Context) *gorm.DB {
+	return l.db.GetReadConnection(ctx).WithContext(ctx)
+}
+
+func (l *LimitRepoImpl) GetCounters(ctx context.Context, accountId string, periods ...string) (map[string]limit.Counter, error) {
+	var counters []limit.Counter
+	err := l.GetReadConn(ctx).Where("account_id = ? AND period IN ?", accountId, periods).Find(&counters)
+	if err.Error != nil {
+		return nil, err.Error
+	}
+
+	byPeriod := make(map[string]limit.Counter, len(counters))
+	for _, counter := range counters {
+		byPeriod[counter.Period] = counter
+	}
+	return byPeriod, nil
+}
+
+// periodCap is the limit of one counter period, with the codes reported when a transfer would exceed it
+type periodCap struct {
+	period     string
+	amount     float64
+	count      int
+	amountCode string
+	countCode  string
+}
+
+func periodCaps(caps *limit.Caps, at time.Time) []periodCap {
+	if caps == nil {
+		caps = &limit.Caps{}
+	}
+	return []periodCap{
+		{limit.DailyPeriod(at), caps.DailyAmount, caps.DailyCount, limit.CodeDailyAmount, limit.CodeDailyCount},
+		{limit.MonthlyPeriod(at), caps.MonthlyAmount, caps.MonthlyCount, limit.CodeMonthlyAmount, limit.CodeMonthlyCount},
+	}
+}
+
+// exceeds returns the code of the cap that adding amount to counter would exceed, or an empty string
+func (p periodCap) exceeds(counter limit.Counter, amount float64) string {
+	switch {
+	case p.count > 0 && counter.Count+1 > p.count:
+		return p.countCode
+	case p.amount > 0 && counter.Amount+amount > p.amount:
+		return p.amountCode
+	}
+	return ""
+}
+
+// incrementLimitCounters adds an outgoing transfer to the account's daily and monthly counters within tx. Counters
+// are only incremented within caps, in the statement that increments them, so concurrent transfers cannot exceed
+// them together; a transfer that would fails with an *limit.ExceededError.
+func incrementLimitCounters(tx *gorm.DB, accountId string, amount float64, at time.Time, caps *limit.Caps) error {
+	for _, p := range periodCaps(caps, at) {
+		if code := p.exceeds(limit.Counter{}, amount); code != "" {
+			return &limit.ExceededError{Code: code}
+		}
+		var within []clause.Expression
+		if p.amount > 0 {
+			within = append(within, gorm.Expr("transfer_limit_counters.amount + ? <= ?", amount, p.amount))
+		}
+		if p.count > 0 {
+			within = append(within, gorm.Expr("transfer_limit_counters.count + 1 <= ?", p.count))
+		}
+		result := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "account_id"}, {Name: "period"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"amount":     gorm.Expr("transfer_limit_counters.amount + ?", amount),
+				"count":      gorm.Expr("transfer_limit_counters.count + 1"),
+				"updated_at": at,
+			}),
+			Where: clause.Where{Exprs: within},
+		}).Create(&limit.Counter{
+			AccountId: accountId,
+			Period:    p.period,
+			Amount:    amount,
+			Count:     1,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			var counter limit.Counter
+			if err := tx.Where("account_id = ? AND period = ?", accountId, p.period).First(&counter).Error; err != nil {
+				return err
+			}
+			code := p.exceeds(counter, amount)
+			if code == "" {
+				code = p.amountCode
+			}
+			return &limit.ExceededError{Code: code}
+		}
+	}
+	return nil
+}
+
+func NewLimitRepo(db db.Database) *LimitRepoImpl {
+	return &LimitRepoImpl{
+		db: db,
+	}
+}
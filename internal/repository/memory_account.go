@@ -0,0 +1,366 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/limit"
+	"internal-transfer-microservice/internal/domain/transfer"
+)
+
+// MemoryAccountRepoImpl keeps accounts, transfers and their limit counters in memory, for demos and tests.
+//
+// Every write is a transaction: it is checked in full before anything is stored, and is either stored
+// entirely or not at all. Accounts and transfers are copied in and out, so changes callers make to what
+// they read are not seen by anyone else until they are written. It writes no outbox events.
+type MemoryAccountRepoImpl struct {
+	accounts        map[string]*account.Model
+	transfers       []*transfer.Model
+	overdraftLimits []account.OverdraftLimitChange
+	// counters are keyed by account id, then period
+	counters map[string]map[string]limit.Counter
+	mu       sync.RWMutex
+}
+
+func (m *MemoryAccountRepoImpl) GetAccount(ctx context.Context, accountId string) (*account.Model, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	acc, exists := m.accounts[accountId]
+	if !exists {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *acc
+	return &copied, nil
+}
+
+func (m *MemoryAccountRepoImpl) ListAccounts(ctx context.Context, ownerId, after string, limit int) ([]account.Model, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	accounts := make([]account.Model, 0, len(m.accounts))
+	for _, acc := range m.accounts {
+		if acc.AccountId > after && (ownerId == "" || acc.OwnerId == ownerId) {
+			accounts = append(accounts, *acc)
+		}
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].AccountId < accounts[j].AccountId })
+	if limit > 0 && len(accounts) > limit {
+		accounts = accounts[:limit]
+	}
+	return accounts, nil
+}
+
+func (m *MemoryAccountRepoImpl) UpdateAccount(ctx context.Context, acc *account.Model) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.accounts[acc.AccountId]; !exists {
+		return gorm.ErrRecordNotFound
+	}
+	now := time.Now()
+	acc.UpdatedAt = &now
+	stored := *acc
+	m.accounts[acc.AccountId] = &stored
+	return nil
+}
+
+func (m *MemoryAccountRepoImpl) CreateAccount(ctx context.Context, acc *account.Model) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.accounts[acc.AccountId]; exists {
+		return account.ErrAccountExists
+	}
+	if acc.ID == uuid.Nil {
+		acc.ID = uuid.New()
+	}
+	now := time.Now()
+	if acc.CreatedAt == nil {
+		acc.CreatedAt = &now
+	}
+	acc.UpdatedAt = &now
+	// the column defaults of the accounts table
+	if acc.AccountType == "" {
+		acc.AccountType = account.DefaultAccountType
+	}
+	if acc.Tier == "" {
+		acc.Tier = account.DefaultTier
+	}
+	stored := *acc
+	m.accounts[acc.AccountId] = &stored
+	return nil
+}
+
+func (m *MemoryAccountRepoImpl) UpdateAccountsInTx(ctx context.Context, txn *transfer.Model, accounts ...*account.Model) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkBalances(accounts...); err != nil {
+		return err
+	}
+	// only completed customer transfers count towards velocity limits
+	countsTowardsLimits := txn != nil && txn.Type == transfer.TypeTransfer && txn.Status == transfer.StatusCompleted && txn.ExecutedAt != nil
+	if countsTowardsLimits {
+		if err := m.checkLimitCounters(txn.SourceAccountId, txn.Amount, *txn.ExecutedAt, txn.LimitCaps); err != nil {
+			return err
+		}
+	}
+	m.saveBalances(accounts...)
+	if txn == nil {
+		return nil
+	}
+	m.saveTransfer(txn)
+	if countsTowardsLimits {
+		m.incrementLimitCounters(txn.SourceAccountId, txn.Amount, *txn.ExecutedAt)
+	}
+	return nil
+}
+
+// SaveBalances stores the balances of accounts at once, as there is no database transaction to join:
+// they are not rolled back with tx
+func (m *MemoryAccountRepoImpl) SaveBalances(tx *gorm.DB, txn *transfer.Model, accounts ...*account.Model) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkBalances(accounts...); err != nil {
+		return err
+	}
+	m.saveBalances(accounts...)
+	return nil
+}
+
+// BalancesCommitted has nothing to do, as balances are stored when they are saved
+func (m *MemoryAccountRepoImpl) BalancesCommitted(ctx context.Context, accounts ...*account.Model) {}
+
+// checkBalances refuses balances of missing accounts and balances that breach the stored overdraft limit
+func (m *MemoryAccountRepoImpl) checkBalances(accounts ...*account.Model) error {
+	for _, acc := range accounts {
+		stored, exists := m.accounts[acc.AccountId]
+		if !exists {
+			return gorm.ErrRecordNotFound
+		}
+		if stored.Balance+acc.BalanceChange() < -stored.OverdraftLimit {
+			return account.ErrOverdraftLimitExceeded
+		}
+	}
+	return nil
+}
+
+// saveBalances applies the balance changes of accounts checked by checkBalances, and nothing else of them
+func (m *MemoryAccountRepoImpl) saveBalances(accounts ...*account.Model) {
+	now := time.Now()
+	for _, acc := range accounts {
+		stored := m.accounts[acc.AccountId]
+		stored.Balance += acc.BalanceChange()
+		stored.UpdatedAt = &now
+		acc.BalanceSaved(stored.Balance)
+	}
+}
+
+// saveTransfer records txn, or updates its record when it was recorded before
+func (m *MemoryAccountRepoImpl) saveTransfer(txn *transfer.Model) {
+	now := time.Now()
+	if txn.ID == uuid.Nil {
+		txn.ID = uuid.New()
+	}
+	if txn.CreatedAt == nil {
+		txn.CreatedAt = &now
+	}
+	txn.UpdatedAt = &now
+
+	stored := *txn
+	for i, existing := range m.transfers {
+		if existing.ID == txn.ID {
+			m.transfers[i] = &stored
+			return
+		}
+	}
+	m.transfers = append(m.transfers, &stored)
+}
+
+// checkLimitCounters refuses an outgoing transfer that would take the account's counters beyond caps
+func (m *MemoryAccountRepoImpl) checkLimitCounters(accountId string, amount float64, at time.Time, caps *limit.Caps) error {
+	for _, p := range periodCaps(caps, at) {
+		if code := p.exceeds(m.counters[accountId][p.period], amount); code != "" {
+			return &limit.ExceededError{Code: code}
+		}
+	}
+	return nil
+}
+
+// incrementLimitCounters adds an outgoing transfer to the account's daily and monthly counters
+func (m *MemoryAccountRepoImpl) incrementLimitCounters(accountId string, amount float64, at time.Time) {
+	byPeriod, exists := m.counters[accountId]
+	if !exists {
+		byPeriod = make(map[string]limit.Counter)
+		m.counters[accountId] = byPeriod
+	}
+	for _, period := range []string{limit.DailyPeriod(at), limit.MonthlyPeriod(at)} {
+		counter := byPeriod[period]
+		counter.AccountId, counter.Period = accountId, period
+		counter.Amount += amount
+		counter.Count++
+		byPeriod[period] = counter
+	}
+}
+
+func (m *MemoryAccountRepoImpl) GetCounters(ctx context.Context, accountId string, periods ...string) (map[string]limit.Counter, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	byPeriod := make(map[string]limit.Counter, len(periods))
+	for _, period := range periods {
+		if counter, exists := m.counters[accountId][period]; exists {
+			byPeriod[period] = counter
+		}
+	}
+	return byPeriod, nil
+}
+
+func (m *MemoryAccountRepoImpl) GetTransferHistory(ctx context.Context, accountId string, limit int) ([]transfer.Model, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// transfers are kept in the order they were created, and the history starts with the latest
+	var history []transfer.Model
+	for i := len(m.transfers) - 1; i >= 0 && len(history) < limit; i-- {
+		txn := m.transfers[i]
+		if txn.SourceAccountId == accountId || txn.DestinationAccountId == accountId {
+			history = append(history, *txn)
+		}
+	}
+	return history, nil
+}
+
+func (m *MemoryAccountRepoImpl) GetOutgoingVelocity(ctx context.Context, accountId string, since time.Time) (int, float64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	count, amount := 0, 0.0
+	for _, txn := range m.transfers {
+		if txn.SourceAccountId != accountId || txn.Type != transfer.TypeTransfer || txn.Status != transfer.StatusCompleted {
+			continue
+		}
+		if txn.ExecutedAt == nil || txn.ExecutedAt.Before(since) {
+			continue
+		}
+		count++
+		amount += txn.Amount
+	}
+	return count, amount, nil
+}
+
+func (m *MemoryAccountRepoImpl) GetTransfer(ctx context.Context, transferId uuid.UUID) (*transfer.Model, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, txn := range m.transfers {
+		if txn.ID == transferId {
+			copied := *txn
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MemoryAccountRepoImpl) ListTransfersByStatus(ctx context.Context, statuses []string, limit int) ([]transfer.Model, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var transfers []transfer.Model
+	for _, txn := range m.transfers {
+		if len(transfers) == limit {
+			break
+		}
+		if hasStatus(txn, statuses) {
+			transfers = append(transfers, *txn)
+		}
+	}
+	return transfers, nil
+}
+
+func (m *MemoryAccountRepoImpl) ExpireTransfers(ctx context.Context, statuses []string, before time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var expired int64
+	for _, txn := range m.transfers {
+		if hasStatus(txn, statuses) && txn.ExpiresAt != nil && txn.ExpiresAt.Before(before) {
+			txn.Status = transfer.StatusExpired
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+func hasStatus(txn *transfer.Model, statuses []string) bool {
+	for _, status := range statuses {
+		if txn.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MemoryAccountRepoImpl) UpdateOverdraftLimit(ctx context.Context, acc *account.Model, change *account.OverdraftLimitChange) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, exists := m.accounts[acc.AccountId]
+	if !exists {
+		return gorm.ErrRecordNotFound
+	}
+	if stored.OverdraftLimitVersion != change.Version-1 {
+		return account.ErrVersionConflict
+	}
+
+	if change.ID == uuid.Nil {
+		change.ID = uuid.New()
+	}
+	now := time.Now()
+	change.CreatedAt, change.UpdatedAt = &now, &now
+	stored.OverdraftLimit, acc.OverdraftLimit = change.NewLimit, change.NewLimit
+	stored.OverdraftLimitVersion, acc.OverdraftLimitVersion = change.Version, change.Version
+	m.overdraftLimits = append(m.overdraftLimits, *change)
+	return nil
+}
+
+func (m *MemoryAccountRepoImpl) SetFrozen(ctx context.Context, acc *account.Model, changedBy, reason string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, exists := m.accounts[acc.AccountId]
+	if !exists {
+		return gorm.ErrRecordNotFound
+	}
+	stored.Frozen = acc.Frozen
+	return nil
+}
+
+func (m *MemoryAccountRepoImpl) GetOverdraftLimitHistory(ctx context.Context, accountId string) ([]account.OverdraftLimitChange, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	history := []account.OverdraftLimitChange{}
+	for i := len(m.overdraftLimits) - 1; i >= 0; i-- {
+		if m.overdraftLimits[i].AccountId == accountId {
+			history = append(history, m.overdraftLimits[i])
+		}
+	}
+	return history, nil
+}
+
+// NewMemoryAccountRepo creates an empty in-memory account repository
+func NewMemoryAccountRepo() *MemoryAccountRepoImpl {
+	return &MemoryAccountRepoImpl{
+		accounts: make(map[string]*account.Model),
+		counters: make(map[string]map[string]limit.Counter),
+	}
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"internal-transfer-microservice/internal/domain/migration"
+	"internal-transfer-microservice/internal/infrastructure/db"
+)
+
+// migrationLockName names the Postgres advisory lock held while migrating, so concurrent migrators take turns
+const migrationLockName = "schema_migrations"
+
+// createSchemaMigrations creates the table recording the applied migrations, with the given time type
+const createSchemaMigrations = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version bigint PRIMARY KEY,
+	name text NOT NULL,
+	checksum text NOT NULL,
+	applied_at %s NOT NULL
+)`
+
+type MigrationRepoImpl struct {
+	db db.Database
+}
+
+// GetConn Helper to get the DB connection
+func (m *MigrationRepoImpl) GetConn() *gorm.DB {
+	return m.db.GetConnection()
+}
+
+// isPostgres reports whether the database has the advisory locks and session timeouts of Postgres
+func (m *MigrationRepoImpl) isPostgres() bool {
+	return m.GetConn().Dialector.Name() == "postgres"
+}
+
+// Lock takes a session-level advisory lock on a connection of its own, which it keeps until released,
+// so the migrations themselves can run on any connection of the pool. Waiting for another migrator is not
+// subject to the configured statement and lock timeouts.
+//
+// SQLite databases are local to a process and run one write transaction at a time, so they are not locked.
+func (m *MigrationRepoImpl) Lock(ctx context.Context) (func(), error) {
+	if !m.isPostgres() {
+		return func() {}, nil
+	}
+	sqlDB, err := m.GetConn().DB()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	release := func() {
+		// the connection goes back to the pool rather than being closed, so the session is restored
+		conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock(hashtext($1))", migrationLockName)
+		conn.ExecContext(context.Background(), "RESET statement_timeout")
+		conn.ExecContext(context.Background(), "RESET lock_timeout")
+		conn.Close()
+	}
+	for _, statement := range []string{"SET statement_timeout = 0", "SET lock_timeout = 0"} {
+		if _, err := conn.ExecContext(ctx, statement); err != nil {
+			release()
+			return nil, err
+		}
+	}
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock(hashtext($1))", migrationLockName); err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
+}
+
+func (m *MigrationRepoImpl) EnsureTable(ctx context.Context) error {
+	timeType := "timestamptz"
+	if !m.isPostgres() {
+		// the SQLite driver converts datetime columns to time.Time
+		timeType = "datetime"
+	}
+	return m.GetConn().WithContext(ctx).Exec(fmt.Sprintf(createSchemaMigrations, timeType)).Error
+}
+
+func (m *MigrationRepoImpl) ListApplied(ctx context.Context) ([]migration.Applied, error) {
+	var applied []migration.Applied
+	err := m.GetConn().WithContext(ctx).Order("version").Find(&applied)
+	if err.Error != nil {
+		return nil, err.Error
+	}
+	return applied, nil
+}
+
+func (m *MigrationRepoImpl) Apply(ctx context.Context, script migration.Migration, appliedAt time.Time) error {
+	return m.GetConn().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := m.disableStatementTimeout(tx); err != nil {
+			return err
+		}
+		if err := tx.Exec(script.Up).Error; err != nil {
+			return err
+		}
+		return tx.Create(&migration.Applied{
+			Version:   script.Version,
+			Name:      script.Name,
+			Checksum:  script.Checksum(),
+			AppliedAt: appliedAt,
+		}).Error
+	})
+}
+
+func (m *MigrationRepoImpl) Revert(ctx context.Context, script migration.Migration) error {
+	return m.GetConn().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := m.disableStatementTimeout(tx); err != nil {
+			return err
+		}
+		if err := tx.Exec(script.Down).Error; err != nil {
+			return err
+		}
+		return tx.Where("version = ?", script.Version).Delete(&migration.Applied{}).Error
+	})
+}
+
+// disableStatementTimeout lets the migration in tx run as long as it needs. The lock timeout still applies,
+// so a migration waiting behind long transactions fails instead of blocking every query queued behind it.
+func (m *MigrationRepoImpl) disableStatementTimeout(tx *gorm.DB) error {
+	if !m.isPostgres() {
+		return nil
+	}
+	return tx.Exec("SET LOCAL statement_timeout = 0").Error
+}
+
+func NewMigrationRepo(db db.Database) *MigrationRepoImpl {
+	return &MigrationRepoImpl{
+		db: db,
+	}
+}
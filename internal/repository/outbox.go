@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/event"
+	"internal-transfer-microservice/internal/domain/transfer"
+	"internal-transfer-microservice/internal/infrastructure/db"
+)
+
+type OutboxRepoImpl struct {
+	db db.Database
+}
+
+// GetConn Helper to get the DB connection
+func (o *OutboxRepoImpl) GetConn() *gorm.DB {
+	return o.db.GetConnection()
+}
+
+func (o *OutboxRepoImpl) ListPendingEvents(ctx context.Context, limit int) ([]event.Outbox, error) {
+	var events []event.Outbox
+	err := o.GetConn().WithContext(ctx).
+		Where("sent_at IS NULL").
+		Order("sequence").
+		Limit(limit).
+		Find(&events)
+	if err.Error != nil {
+		return nil, err.Error
+	}
+	return events, nil
+}
+
+func (o *OutboxRepoImpl) MarkEventSent(ctx context.Context, eventId uuid.UUID, sentAt time.Time) error {
+	return o.GetConn().WithContext(ctx).Model(&event.Outbox{}).
+		Where("id = ?", eventId).
+		Updates(map[string]interface{}{
+			"sent_at":  sentAt,
+			"attempts": gorm.Expr("attempts + 1"),
+		}).Error
+}
+
+func (o *OutboxRepoImpl) RecordEventFailure(ctx context.Context, eventId uuid.UUID, reason string) error {
+	return o.GetConn().WithContext(ctx).Model(&event.Outbox{}).
+		Where("id = ?", eventId).
+		Updates(map[string]interface{}{
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": reason,
+		}).Error
+}
+
+// recordAccountCreated adds the AccountCreated event of acc to the outbox within tx
+func recordAccountCreated(tx *gorm.DB, acc *account.Model) error {
+	outbox, err := event.NewOutbox(event.TypeAccountCreated, event.AggregateAccount, acc.AccountId, acc.AccountId, event.AccountCreated{
+		AccountId:      acc.AccountId,
+		HolderName:     acc.HolderName,
+		OwnerId:        acc.OwnerId,
+		AccountType:    acc.AccountType,
+		Tier:           acc.Tier,
+		RatePlanId:     acc.RatePlanId,
+		InitialBalance: acc.Balance,
+	}, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	return tx.Create(outbox).Error
+}
+
+// recordTransferOutcome adds the TransferCompleted or TransferFailed event of txn to the outbox within tx,
+// ordered with the events of the source account, and queues the webhooks subscribed to either account.
+// Completed transfers carry the balances of the accounts they changed. Transfers still waiting for a decision record nothing.
+func recordTransferOutcome(tx *gorm.DB, txn *transfer.Model, accounts ...*account.Model) error {
+	var outbox *event.Outbox
+	var err error
+	switch {
+	case txn.Status == transfer.StatusCompleted:
+		occurredAt := time.Now().UTC()
+		if txn.ExecutedAt != nil {
+			occurredAt = *txn.ExecutedAt
+		}
+		balances := make(map[string]float64, len(accounts))
+		for _, acc := range accounts {
+			balances[acc.AccountId] = acc.Balance
+		}
+		outbox, err = event.NewOutbox(event.TypeTransferCompleted, event.AggregateTransfer, txn.ID.String(), txn.SourceAccountId, event.TransferCompleted{
+			TransferId:           txn.ID.String(),
+			SourceAccountId:      txn.SourceAccountId,
+			DestinationAccountId: txn.DestinationAccountId,
+			Amount:               txn.Amount,
+			Fee:                  txn.Fee,
+			Type:                 txn.Type,
+			ExecutedAt:           txn.ExecutedAt,
+			Balances:             balances,
+		}, occurredAt)
+	case txn.IsFailed():
+		outbox, err = event.NewOutbox(event.TypeTransferFailed, event.AggregateTransfer, txn.ID.String(), txn.SourceAccountId, event.TransferFailed{
+			TransferId:           txn.ID.String(),
+			SourceAccountId:      txn.SourceAccountId,
+			DestinationAccountId: txn.DestinationAccountId,
+			Amount:               txn.Amount,
+			Type:                 txn.Type,
+			Status:               txn.Status,
+			Reason:               txn.FailureReason,
+		}, time.Now().UTC())
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := tx.Create(outbox).Error; err != nil {
+		return err
+	}
+	return enqueueWebhooks(tx, outbox, txn.SourceAccountId, txn.DestinationAccountId)
+}
+
+// recordOverdraftLimitChange stores the audit record of an overdraft limit change and adds its event to the outbox within tx
+func recordOverdraftLimitChange(tx *gorm.DB, change *account.OverdraftLimitChange) error {
+	if err := tx.Create(change).Error; err != nil {
+		return err
+	}
+	outbox, err := event.NewOutbox(event.TypeOverdraftLimitChanged, event.AggregateAccount, change.AccountId, change.AccountId, event.OverdraftLimitChanged{
+		AccountId:     change.AccountId,
+		PreviousLimit: change.PreviousLimit,
+		Limit:         change.NewLimit,
+		Version:       change.Version,
+		ChangedBy:     change.ChangedBy,
+		Reason:        change.Reason,
+	}, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	return tx.Create(outbox).Error
+}
+
+// recordFreezeChange adds the AccountFrozen or AccountUnfrozen event of acc's new state to the outbox within tx
+func recordFreezeChange(tx *gorm.DB, acc *account.Model, changedBy, reason string) error {
+	eventType := event.TypeAccountUnfrozen
+	if acc.Frozen {
+		eventType = event.TypeAccountFrozen
+	}
+	outbox, err := event.NewOutbox(eventType, event.AggregateAccount, acc.AccountId, acc.AccountId, event.AccountFreezeChanged{
+		AccountId: acc.AccountId,
+		ChangedBy: changedBy,
+		Reason:    reason,
+	}, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	return tx.Create(outbox).Error
+}
+
+func NewOutboxRepo(db db.Database) *OutboxRepoImpl {
+	return &OutboxRepoImpl{
+		db: db,
+	}
+}
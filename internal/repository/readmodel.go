@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/event"
+	"internal-transfer-microservice/internal/domain/readmodel"
+	"internal-transfer-microservice/internal/domain/transfer"
+	"internal-transfer-microservice/internal/infrastructure/db"
+)
+
+// readModelBatchSize bounds the number of rows read or inserted per statement when rebuilding the read model
+const readModelBatchSize = 500
+
+type ReadModelRepoImpl struct {
+	db db.Database
+}
+
+// GetConn Helper to get the DB connection
+func (r *ReadModelRepoImpl) GetConn() *gorm.DB {
+	return r.db.GetConnection()
+}
+
+// GetReadConn Helper to get a connection for read-only queries, served by a replica unless ctx is pinned to the primary
+func (r *ReadModelRepoImpl) GetReadConn(ctx context.Context) *gorm.DB {
+	return r.db.GetReadConnection(ctx).WithContext(ctx)
+}
+
+func (r *ReadModelRepoImpl) GetSummary(ctx context.Context, accountId string) (*readmodel.AccountSummary, error) {
+	var summary readmodel.AccountSummary
+	err := r.GetReadConn(ctx).First(&summary, "account_id = ?", accountId)
+	if errors.Is(err.Error, gorm.ErrRecordNotFound) {
+		return nil, readmodel.ErrSummaryNotFound
+	}
+	if err.Error != nil {
+		return nil, err.Error
+	}
+	return &summary, nil
+}
+
+func (r *ReadModelRepoImpl) GetFlowTotals(ctx context.Context, accountId string, since string) (float64, float64, error) {
+	var totals struct {
+		Inflow  float64
+		Outflow float64
+	}
+	err := r.GetReadConn(ctx).Model(&readmodel.DailyFlow{}).
+		Select("COALESCE(SUM(inflow), 0) AS inflow, COALESCE(SUM(outflow), 0) AS outflow").
+		Where("account_id = ? AND day >= ?", accountId, since).
+		Scan(&totals)
+	if err.Error != nil {
+		return 0, 0, err.Error
+	}
+	return totals.Inflow, totals.Outflow, nil
+}
+
+func (r *ReadModelRepoImpl) ListUnprojectedEvents(ctx context.Context, limit int) ([]event.Outbox, error) {
+	var events []event.Outbox
+	err := r.GetConn().WithContext(ctx).
+		Where("projected_at IS NULL").
+		Order("sequence").
+		Limit(limit).
+		Find(&events)
+	if err.Error != nil {
+		return nil, err.Error
+	}
+	return events, nil
+}
+
+func (r *ReadModelRepoImpl) ApplyProjection(ctx context.Context, projection *readmodel.Projection, projectedAt time.Time) error {
+	return r.GetConn().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		marked := tx.Model(&event.Outbox{}).
+			Where("id = ? AND projected_at IS NULL", projection.EventId).
+			Update("projected_at", projectedAt)
+		if marked.Error != nil {
+			return marked.Error
+		}
+		if marked.RowsAffected == 0 {
+			// another projector or a rebuild projected the event already
+			return nil
+		}
+
+		if projection.Opened != nil {
+			opened := *projection.Opened
+			opened.UpdatedAt = projectedAt
+			// a rebuild may have written the summary already
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&opened).Error; err != nil {
+				return err
+			}
+		}
+
+		if accountIds := projection.AccountIds(); len(accountIds) > 0 {
+			var rows []readmodel.AccountSummary
+			if err := tx.Where("account_id IN ?", accountIds).Find(&rows).Error; err != nil {
+				return err
+			}
+			summaries := make(map[string]*readmodel.AccountSummary, len(rows))
+			for i := range rows {
+				summaries[rows[i].AccountId] = &rows[i]
+			}
+			projection.Apply(summaries, projectedAt)
+			for _, summary := range summaries {
+				if err := tx.Save(summary).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		if projection.Transfer != nil {
+			for _, flow := range projection.Transfer.Flows() {
+				if err := addDailyFlow(tx, flow); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// addDailyFlow adds the inflow and outflow of flow to the account's totals for the day within tx
+func addDailyFlow(tx *gorm.DB, flow readmodel.DailyFlow) error {
+	return tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "account_id"}, {Name: "day"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"inflow":  gorm.Expr("account_daily_flows.inflow + ?", flow.Inflow),
+			"outflow": gorm.Expr("account_daily_flows.outflow + ?", flow.Outflow),
+		}),
+	}).Create(&flow).Error
+}
+
+func (r *ReadModelRepoImpl) Rebuild(ctx context.Context, since string, rebuiltAt time.Time) (int, error) {
+	written := 0
+	// the snapshot of a repeatable read transaction holds exactly the accounts, transfers and events committed
+	// when it began, so the events it marks projected are those the rebuilt model covers
+	err := r.GetConn().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&readmodel.AccountSummary{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("1 = 1").Delete(&readmodel.DailyFlow{}).Error; err != nil {
+			return err
+		}
+
+		var accounts []account.Model
+		if err := tx.Order("account_id").Find(&accounts).Error; err != nil {
+			return err
+		}
+		summaries := make([]readmodel.AccountSummary, len(accounts))
+		byAccount := make(map[string]*readmodel.AccountSummary, len(accounts))
+		for i := range accounts {
+			acc := &accounts[i]
+			summaries[i] = readmodel.AccountSummary{
+				AccountId:             acc.AccountId,
+				HolderName:            acc.HolderName,
+				OwnerId:               acc.OwnerId,
+				AccountType:           acc.AccountType,
+				RatePlanId:            acc.RatePlanId,
+				Tier:                  acc.Tier,
+				Balance:               acc.Balance,
+				OverdraftLimit:        acc.OverdraftLimit,
+				OverdraftLimitVersion: acc.OverdraftLimitVersion,
+				Frozen:                acc.Frozen,
+				UpdatedAt:             rebuiltAt,
+			}
+			byAccount[acc.AccountId] = &summaries[i]
+		}
+
+		var flows []readmodel.DailyFlow
+		flowIndex := make(map[[2]string]int)
+		var batch []transfer.Model
+		result := tx.Where("status = ? AND executed_at IS NOT NULL", transfer.StatusCompleted).
+			FindInBatches(&batch, readModelBatchSize, func(_ *gorm.DB, _ int) error {
+				for i := range batch {
+					txn := &batch[i]
+					activity := &readmodel.TransferActivity{
+						TransferId:           txn.ID.String(),
+						SourceAccountId:      txn.SourceAccountId,
+						DestinationAccountId: txn.DestinationAccountId,
+						Amount:               txn.Amount,
+						Fee:                  txn.Fee,
+						ExecutedAt:           txn.ExecutedAt.UTC(),
+					}
+					for _, accountId := range []string{txn.SourceAccountId, txn.DestinationAccountId} {
+						if summary, ok := byAccount[accountId]; ok {
+							summary.RecordTransfer(activity)
+						}
+					}
+					if readmodel.Day(activity.ExecutedAt) < since {
+						continue
+					}
+					for _, flow := range activity.Flows() {
+						key := [2]string{flow.AccountId, flow.Day}
+						if index, ok := flowIndex[key]; ok {
+							flows[index].Inflow += flow.Inflow
+							flows[index].Outflow += flow.Outflow
+							continue
+						}
+						flowIndex[key] = len(flows)
+						flows = append(flows, flow)
+					}
+				}
+				return nil
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if len(summaries) > 0 {
+			if err := tx.CreateInBatches(summaries, readModelBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		if len(flows) > 0 {
+			if err := tx.CreateInBatches(flows, readModelBatchSize).Error; err != nil {
+				return err
+			}
+		}
+
+		written = len(summaries)
+		return tx.Model(&event.Outbox{}).
+			Where("projected_at IS NULL").
+			Update("projected_at", rebuiltAt).Error
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return 0, err
+	}
+	return written, nil
+}
+
+func NewReadModelRepo(db db.Database) *ReadModelRepoImpl {
+	return &ReadModelRepoImpl{
+		db: db,
+	}
+}
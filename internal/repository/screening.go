@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"internal-transfer-microservice/internal/domain/screening"
+	"internal-transfer-microservice/internal/infrastructure/db"
+)
+
+// watchlistBatchSize bounds the number of rows inserted per statement when replacing the watchlist
+const watchlistBatchSize = 500
+
+type ScreeningRepoImpl struct {
+	db db.Database
+}
+
+// GetConn Helper to get the DB connection
+func (s *ScreeningRepoImpl) GetConn() *gorm.DB {
+	return s.db.GetConnection()
+}
+
+// GetReadConn Helper to get a connection for read-only queries, served by a replica unless ctx is pinned to the primary
+func (s *ScreeningRepoImpl) GetReadConn(ctx context.Context) *gorm.DB {
+	return s.db.GetReadConnection(ctx).WithContext(ctx)
+}
+
+func (s *ScreeningRepoImpl) ReplaceWatchlist(ctx context.Context, entries []screening.WatchlistEntry) error {
+	return s.GetConn().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&screening.WatchlistEntry{}).Error; err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(entries, watchlistBatchSize).Error
+	})
+}
+
+func (s *ScreeningRepoImpl) ListWatchlist(ctx context.Context) ([]screening.WatchlistEntry, error) {
+	var entries []screening.WatchlistEntry
+	err := s.GetConn().WithContext(ctx).Order("entry_id").Find(&entries)
+	if err.Error != nil {
+		return nil, err.Error
+	}
+	return entries, nil
+}
+
+func (s *ScreeningRepoImpl) CreateHits(ctx context.Context, hits []screening.Hit) error {
+	if len(hits) == 0 {
+		return nil
+	}
+	return s.GetConn().WithContext(ctx).Create(&hits).Error
+}
+
+func (s *ScreeningRepoImpl) ListHits(ctx context.Context, status string, limit int) ([]screening.Hit, error) {
+	var hits []screening.Hit
+	query := s.GetReadConn(ctx)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("created_at DESC").Limit(limit).Find(&hits)
+	if err.Error != nil {
+		return nil, err.Error
+	}
+	return hits, nil
+}
+
+func (s *ScreeningRepoImpl) GetHit(ctx context.Context, hitId uuid.UUID) (*screening.Hit, error) {
+	var hit screening.Hit
+	err := s.GetReadConn(ctx).First(&hit, "id = ?", hitId)
+	if err.Error != nil {
+		return nil, err.Error
+	}
+	return &hit, nil
+}
+
+func (s *ScreeningRepoImpl) UpdateHit(ctx context.Context, hit *screening.Hit) error {
+	return s.GetConn().WithContext(ctx).Save(hit).Error
+}
+
+func NewScreeningRepo(db db.Database) *ScreeningRepoImpl {
+	return &ScreeningRepoImpl{
+		db: db,
+	}
+}
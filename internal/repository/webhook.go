@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"internal-transfer-microservice/internal/domain/event"
+	"internal-transfer-microservice/internal/domain/webhook"
+	"internal-transfer-microservice/internal/infrastructure/db"
+)
+
+type WebhookRepoImpl struct {
+	db db.Database
+}
+
+// GetConn Helper to get the DB connection
+func (w *WebhookRepoImpl) GetConn() *gorm.DB {
+	return w.db.GetConnection()
+}
+
+// GetReadConn Helper to get a connection for read-only queries, served by a replica unless ctx is pinned to the primary
+func (w *WebhookRepoImpl) GetReadConn(ctx context.Context) *gorm.DB {
+	return w.db.GetReadConnection(ctx).WithContext(ctx)
+}
+
+func (w *WebhookRepoImpl) CreateSubscription(ctx context.Context, subscription *webhook.Subscription) error {
+	return w.GetConn().WithContext(ctx).Create(subscription).Error
+}
+
+func (w *WebhookRepoImpl) GetSubscription(ctx context.Context, subscriptionId uuid.UUID) (*webhook.Subscription, error) {
+	var subscription webhook.Subscription
+	err := w.GetReadConn(ctx).Where("id = ?", subscriptionId).First(&subscription)
+	if err.Error != nil {
+		return nil, err.Error
+	}
+	return &subscription, nil
+}
+
+func (w *WebhookRepoImpl) ListSubscriptions(ctx context.Context) ([]webhook.Subscription, error) {
+	var subscriptions []webhook.Subscription
+	err := w.GetReadConn(ctx).Order("created_at").Find(&subscriptions)
+	if err.Error != nil {
+		return nil, err.Error
+	}
+	return subscriptions, nil
+}
+
+func (w *WebhookRepoImpl) UpdateSubscription(ctx context.Context, subscription *webhook.Subscription) error {
+	return w.GetConn().WithContext(ctx).Save(subscription).Error
+}
+
+func (w *WebhookRepoImpl) ListDeliveries(ctx context.Context, subscriptionId uuid.UUID, limit int) ([]webhook.Delivery, error) {
+	var deliveries []webhook.Delivery
+	err := w.GetReadConn(ctx).
+		Where("subscription_id = ?", subscriptionId).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&deliveries)
+	if err.Error != nil {
+		return nil, err.Error
+	}
+	return deliveries, nil
+}
+
+func (w *WebhookRepoImpl) GetDelivery(ctx context.Context, deliveryId uuid.UUID) (*webhook.Delivery, error) {
+	var delivery webhook.Delivery
+	err := w.GetReadConn(ctx).Where("id = ?", deliveryId).First(&delivery)
+	if err.Error != nil {
+		return nil, err.Error
+	}
+	return &delivery, nil
+}
+
+func (w *WebhookRepoImpl) ListAttempts(ctx context.Context, deliveryId uuid.UUID) ([]webhook.DeliveryAttempt, error) {
+	var attempts []webhook.DeliveryAttempt
+	err := w.GetReadConn(ctx).Where("delivery_id = ?", deliveryId).Order("created_at").Find(&attempts)
+	if err.Error != nil {
+		return nil, err.Error
+	}
+	return attempts, nil
+}
+
+func (w *WebhookRepoImpl) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]webhook.Delivery, error) {
+	var deliveries []webhook.Delivery
+	err := w.GetConn().WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", webhook.DeliveryPending, now).
+		Order("next_attempt_at").
+		Limit(limit).
+		Find(&deliveries)
+	if err.Error != nil {
+		return nil, err.Error
+	}
+	return deliveries, nil
+}
+
+func (w *WebhookRepoImpl) ClaimDelivery(ctx context.Context, delivery *webhook.Delivery, leaseUntil time.Time) (bool, error) {
+	result := w.GetConn().WithContext(ctx).Model(&webhook.Delivery{}).
+		Where("id = ? AND status = ? AND attempts = ?", delivery.ID, webhook.DeliveryPending, delivery.Attempts).
+		Updates(map[string]interface{}{"attempts": gorm.Expr("attempts + 1"), "next_attempt_at": leaseUntil})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	delivery.Attempts++
+	delivery.NextAttemptAt = &leaseUntil
+	return true, nil
+}
+
+func (w *WebhookRepoImpl) RecordAttempt(ctx context.Context, delivery *webhook.Delivery, attempt *webhook.DeliveryAttempt) error {
+	return w.GetConn().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// the claim holds while the delivery is pending with the attempts counted by it
+		result := tx.Model(delivery).
+			Where("status = ? AND attempts = ?", webhook.DeliveryPending, delivery.Attempts).
+			Select("*").Updates(delivery)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return webhook.ErrDeliveryReclaimed
+		}
+		return tx.Create(attempt).Error
+	})
+}
+
+func (w *WebhookRepoImpl) UpdateDelivery(ctx context.Context, delivery *webhook.Delivery) error {
+	return w.GetConn().WithContext(ctx).Save(delivery).Error
+}
+
+// enqueueWebhooks queues a delivery of outbox within tx for every active subscription of accountIds wanting its type
+func enqueueWebhooks(tx *gorm.DB, outbox *event.Outbox, accountIds ...string) error {
+	var subscriptions []webhook.Subscription
+	if err := tx.Where("active = ? AND account_id IN ?", true, accountIds).Find(&subscriptions).Error; err != nil {
+		return err
+	}
+
+	var deliveries []webhook.Delivery
+	for i := range subscriptions {
+		if !subscriptions[i].Subscribes(outbox.EventType) {
+			continue
+		}
+		deliveries = append(deliveries, webhook.Delivery{
+			SubscriptionId: subscriptions[i].ID,
+			EventId:        outbox.ID,
+			EventType:      outbox.EventType,
+			Status:         webhook.DeliveryPending,
+			NextAttemptAt:  &outbox.OccurredAt,
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	// the database assigns the sequence, so read it back before rendering the envelope
+	if err := tx.Model(&event.Outbox{}).Select("sequence").Where("id = ?", outbox.ID).Scan(&outbox.Sequence).Error; err != nil {
+		return err
+	}
+	payload, err := json.Marshal(outbox.ToEnvelope())
+	if err != nil {
+		return err
+	}
+	for i := range deliveries {
+		deliveries[i].Payload = string(payload)
+	}
+	return tx.Create(&deliveries).Error
+}
+
+func NewWebhookRepo(db db.Database) *WebhookRepoImpl {
+	return &WebhookRepoImpl{
+		db: db,
+	}
+}
@@ -10,8 +10,21 @@ import (
 func SetupAccountRoutes(router *gin.Engine, accountController *controller.AccountController) {
 	accountRoutes := router.Group("/api/v1/accounts")
 	{
+		accountRoutes.GET("", accountController.ListAccounts)
 		accountRoutes.GET("/:id", accountController.GetAccount)
+		accountRoutes.GET("/:id/transfers", accountController.GetTransferHistory)
+		accountRoutes.PUT("/:id/overdraft", accountController.UpdateOverdraftLimit)
+		accountRoutes.GET("/:id/overdraft/history", accountController.GetOverdraftLimitHistory)
+		accountRoutes.PUT("/:id/freeze", accountController.FreezeAccount)
+		accountRoutes.GET("/:id/limits", accountController.GetRemainingLimits)
 		accountRoutes.POST("", accountController.CreateAccount)
 		accountRoutes.POST("/transfer", accountController.TransferMoney)
 	}
+
+	transferRoutes := router.Group("/api/v1/transfers")
+	{
+		transferRoutes.GET("/pending", accountController.ListPendingTransfers)
+		transferRoutes.POST("/:id/approve", accountController.ApproveTransfer)
+		transferRoutes.POST("/:id/reject", accountController.RejectTransfer)
+	}
 }
@@ -0,0 +1,333 @@
+package routes
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"internal-transfer-microservice/api"
+	"internal-transfer-microservice/internal/auth"
+	"internal-transfer-microservice/internal/config"
+	"internal-transfer-microservice/internal/controller"
+	"internal-transfer-microservice/internal/domain/access"
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/infrastructure/cache"
+	"internal-transfer-microservice/internal/middleware"
+	"internal-transfer-microservice/internal/repository"
+	"internal-transfer-microservice/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ginParam matches the path parameters of gin routes, written {name} in OpenAPI paths
+var ginParam = regexp.MustCompile(`:(\w+)`)
+
+func TestAccountRoutesMatchOpenAPI(t *testing.T) {
+	// Setup
+	spec, err := api.Load()
+	if err != nil {
+		t.Fatalf("Expected the OpenAPI document to load, got %v", err)
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	SetupAccountRoutes(router, controller.NewAccountController(nil))
+
+	registered := map[string]bool{}
+	for _, route := range router.Routes() {
+		registered[route.Method+" "+ginParam.ReplaceAllString(route.Path, "{$1}")] = true
+	}
+	documented := map[string]bool{}
+	for path, item := range spec.Paths.Map() {
+		for method := range item.Operations() {
+			documented[method+" "+path] = true
+		}
+	}
+
+	// Test case: every route is documented, and every documented operation is routed
+	var missing, unrouted []string
+	for route := range registered {
+		if !documented[route] {
+			missing = append(missing, route)
+		}
+	}
+	for operation := range documented {
+		if !registered[operation] {
+			unrouted = append(unrouted, operation)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(unrouted)
+	if len(missing) > 0 {
+		t.Errorf("Expected every route in the OpenAPI document, got undocumented %v", missing)
+	}
+	if len(unrouted) > 0 {
+		t.Errorf("Expected every documented operation to be routed, got unrouted %v", unrouted)
+	}
+}
+
+// newTestAccountRouter serves the account routes from memory to the callers identity identifies, validating
+// requests and responses against the OpenAPI document. Transfers above 1000 wait for approval, and the limit
+// per transfer is 5000.
+func newTestAccountRouter(t *testing.T, identity gin.HandlerFunc, opts ...service.Option) http.Handler {
+	spec, err := api.Load()
+	if err != nil {
+		t.Fatalf("Expected the OpenAPI document to load, got %v", err)
+	}
+	validateResponses, err := middleware.ValidateResponses(spec, func(req *http.Request, err error) {
+		t.Errorf("Expected the response to %s %s to match the OpenAPI document, got %v", req.Method, req.URL, err)
+	})
+	if err != nil {
+		t.Fatalf("Expected response validation, got %v", err)
+	}
+	validateRequests, err := middleware.ValidateRequests(spec)
+	if err != nil {
+		t.Fatalf("Expected request validation, got %v", err)
+	}
+
+	repo := repository.NewMemoryAccountRepo()
+	cfg := &config.Config{
+		Limits: config.LimitsConfig{
+			Tiers: []config.LimitTierConfig{{Tier: "", PerTransferMax: 5000}},
+		},
+	}
+	opts = append([]service.Option{
+		service.WithLimitEngine(service.NewLimitEngine(repo, cfg)),
+		service.WithApprovalPolicy(1000, time.Hour),
+	}, opts...)
+	accountService := service.NewAccountService(repo, cache.NewMemoryCache(), opts...)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.ContextWithFallback = true
+	router.Use(identity, validateResponses, validateRequests)
+	SetupAccountRoutes(router, controller.NewAccountController(accountService))
+	return router
+}
+
+// serve sends a request with a JSON body, on behalf of operator when it is set
+func serve(handler http.Handler, method, path, body, operator string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if operator != "" {
+		req.Header.Set(middleware.OperatorHeader, operator)
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestAccountRoutesConformToOpenAPI(t *testing.T) {
+	// Setup
+	router := newTestAccountRouter(t, middleware.OperatorIdentity())
+	expectStatus := func(recorder *httptest.ResponseRecorder, status int, call string) {
+		t.Helper()
+		if recorder.Code != status {
+			t.Errorf("Expected %s to answer %d, got %d: %s", call, status, recorder.Code, recorder.Body)
+		}
+	}
+
+	// Test case: creating accounts
+	expectStatus(serve(router, "POST", "/api/v1/accounts", `{"account_id": "src", "initial_balance": 10000}`, ""), 201, "create")
+	expectStatus(serve(router, "POST", "/api/v1/accounts", `{"account_id": "dst"}`, ""), 201, "create")
+	expectStatus(serve(router, "POST", "/api/v1/accounts", `{"account_id": "src"}`, ""), 409, "duplicate create")
+	expectStatus(serve(router, "POST", "/api/v1/accounts", `{"holder_name": "No Id"}`, ""), 400, "create without id")
+
+	// Test case: reading accounts
+	expectStatus(serve(router, "GET", "/api/v1/accounts", "", ""), 200, "list")
+	expectStatus(serve(router, "GET", "/api/v1/accounts?after=dst&limit=1", "", ""), 200, "list a page")
+	expectStatus(serve(router, "GET", "/api/v1/accounts?limit=0", "", ""), 400, "list an empty page")
+	expectStatus(serve(router, "GET", "/api/v1/accounts/src", "", ""), 200, "get")
+	expectStatus(serve(router, "GET", "/api/v1/accounts/missing", "", ""), 404, "get missing")
+	expectStatus(serve(router, "GET", "/api/v1/accounts/src/limits", "", ""), 200, "limits")
+	expectStatus(serve(router, "GET", "/api/v1/accounts/missing/limits", "", ""), 404, "limits of missing")
+
+	// Test case: transfers that execute, wait for approval, or are refused
+	expectStatus(serve(router, "POST", "/api/v1/accounts/transfer", `{"source_account_id": "src", "destination_account_id": "dst", "amount": 100}`, "maker"), 200, "transfer")
+	expectStatus(serve(router, "POST", "/api/v1/accounts/transfer", `{"source_account_id": "dst", "destination_account_id": "src", "amount": 900}`, "maker"), 200, "transfer without funds")
+	expectStatus(serve(router, "POST", "/api/v1/accounts/transfer", `{"source_account_id": "src", "destination_account_id": "dst", "amount": 0}`, "maker"), 400, "transfer of nothing")
+	expectStatus(serve(router, "POST", "/api/v1/accounts/transfer", `{"source_account_id": "src", "destination_account_id": "dst", "amount": 6000}`, "maker"), 422, "transfer above the limit")
+	expectStatus(serve(router, "POST", "/api/v1/accounts/transfer", `{"source_account_id": "src", "destination_account_id": "missing", "amount": 100}`, "maker"), 404, "transfer to missing")
+	expectStatus(serve(router, "GET", "/api/v1/accounts/src/transfers", "", ""), 200, "history")
+	expectStatus(serve(router, "GET", "/api/v1/accounts/missing/transfers", "", ""), 404, "history of missing")
+
+	// Test case: approving and rejecting pending transfers
+	var held account.TransferResponse
+	recorder := serve(router, "POST", "/api/v1/accounts/transfer", `{"source_account_id": "src", "destination_account_id": "dst", "amount": 1500}`, "maker")
+	expectStatus(recorder, 202, "transfer above the approval threshold")
+	json.Unmarshal(recorder.Body.Bytes(), &held)
+	if held.Transfer == nil {
+		t.Fatalf("Expected the held transfer, got %s", recorder.Body)
+	}
+	approvePath := "/api/v1/transfers/" + held.Transfer.TransferId + "/approve"
+	expectStatus(serve(router, "GET", "/api/v1/transfers/pending", "", ""), 200, "pending")
+	expectStatus(serve(router, "POST", approvePath, "", ""), 401, "approve without operator")
+	expectStatus(serve(router, "POST", approvePath, "", "maker"), 403, "approve by the initiator")
+	expectStatus(serve(router, "POST", approvePath, "", "checker"), 200, "approve")
+	expectStatus(serve(router, "POST", approvePath, "", "checker"), 409, "approve twice")
+	expectStatus(serve(router, "POST", "/api/v1/transfers/7b0d5a52-3f38-4bb2-9b6e-07d7d3b1c0aa/approve", "", "checker"), 404, "approve of missing")
+
+	recorder = serve(router, "POST", "/api/v1/accounts/transfer", `{"source_account_id": "src", "destination_account_id": "dst", "amount": 1500}`, "maker")
+	json.Unmarshal(recorder.Body.Bytes(), &held)
+	rejectPath := "/api/v1/transfers/" + held.Transfer.TransferId + "/reject"
+	expectStatus(serve(router, "POST", rejectPath, `{"reason": "unexpected payee"}`, ""), 401, "reject without operator")
+	expectStatus(serve(router, "POST", rejectPath, `{"reason": "unexpected payee"}`, "checker"), 200, "reject")
+	expectStatus(serve(router, "POST", rejectPath, `{"reason": "unexpected payee"}`, "checker"), 409, "reject twice")
+
+	// Test case: changing overdraft limits
+	expectStatus(serve(router, "PUT", "/api/v1/accounts/src/overdraft", `{"limit": 100, "reason": "review"}`, ""), 401, "overdraft change without operator")
+	expectStatus(serve(router, "PUT", "/api/v1/accounts/src/overdraft", `{"limit": 100, "reason": "review"}`, "ops"), 200, "overdraft change")
+	expectStatus(serve(router, "PUT", "/api/v1/accounts/src/overdraft", `{"limit": 200, "expected_version": 0}`, "ops"), 409, "stale overdraft change")
+	expectStatus(serve(router, "PUT", "/api/v1/accounts/src/overdraft", `{"limit": -1}`, "ops"), 400, "negative overdraft")
+	expectStatus(serve(router, "PUT", "/api/v1/accounts/missing/overdraft", `{"limit": 100}`, "ops"), 404, "overdraft of missing")
+	expectStatus(serve(router, "GET", "/api/v1/accounts/src/overdraft/history", "", ""), 200, "overdraft history")
+	expectStatus(serve(router, "GET", "/api/v1/accounts/dst/overdraft/history", "", ""), 200, "empty overdraft history")
+	expectStatus(serve(router, "GET", "/api/v1/accounts/missing/overdraft/history", "", ""), 404, "overdraft history of missing")
+
+	// Test case: freezing and unfreezing accounts
+	expectStatus(serve(router, "PUT", "/api/v1/accounts/dst/freeze", `{"frozen": true, "reason": "fraud report"}`, ""), 401, "freeze without operator")
+	expectStatus(serve(router, "PUT", "/api/v1/accounts/dst/freeze", `{"frozen": true, "reason": "fraud report"}`, "oncall"), 200, "freeze")
+	expectStatus(serve(router, "PUT", "/api/v1/accounts/dst/freeze", `{"frozen": "yes"}`, "oncall"), 400, "malformed freeze")
+	expectStatus(serve(router, "PUT", "/api/v1/accounts/missing/freeze", `{"frozen": true}`, "oncall"), 404, "freeze of missing")
+	expectStatus(serve(router, "POST", "/api/v1/accounts/transfer", `{"source_account_id": "src", "destination_account_id": "dst", "amount": 100}`, "maker"), 422, "transfer to frozen")
+	expectStatus(serve(router, "PUT", "/api/v1/accounts/dst/freeze", `{"frozen": false}`, "oncall"), 200, "unfreeze")
+	expectStatus(serve(router, "POST", "/api/v1/accounts/transfer", `{"source_account_id": "src", "destination_account_id": "dst", "amount": 100}`, "maker"), 200, "transfer to unfrozen")
+}
+
+func TestAccountRoutesWithBearerTokens(t *testing.T) {
+	// Setup
+	secret := []byte("test-secret")
+	verifier, err := auth.NewJWTVerifier(&config.Config{Auth: config.AuthConfig{
+		Issuer:         "https://id.example.com",
+		Audience:       "transfers",
+		ClockSkew:      time.Minute,
+		RequiredScopes: []string{"transfers"},
+		HMACSecret:     string(secret),
+	}})
+	if err != nil {
+		t.Fatalf("Expected a verifier, got %v", err)
+	}
+	router := newTestAccountRouter(t, middleware.Authenticate(verifier))
+	token := func(subject, scope string, expiresIn time.Duration, audience string) string {
+		signed, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"iss":   "https://id.example.com",
+			"aud":   audience,
+			"sub":   subject,
+			"scope": scope,
+			"iat":   time.Now().Unix(),
+			"exp":   time.Now().Add(expiresIn).Unix(),
+		}).SignedString(secret)
+		return signed
+	}
+	serveWithToken := func(method, path, body, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		// the header is not trusted once callers authenticate
+		req.Header.Set(middleware.OperatorHeader, "checker")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+	expectStatus := func(recorder *httptest.ResponseRecorder, status int, call string) {
+		t.Helper()
+		if recorder.Code != status {
+			t.Errorf("Expected %s to answer %d, got %d: %s", call, status, recorder.Code, recorder.Body)
+		}
+	}
+	maker := token("maker", "accounts:read transfers", time.Hour, "transfers")
+
+	// Test case: requests without a valid token are refused with 401
+	recorder := serveWithToken("GET", "/api/v1/accounts", "", "")
+	expectStatus(recorder, 401, "list without a token")
+	if recorder.Header().Get("WWW-Authenticate") != "Bearer" {
+		t.Errorf("Expected a Bearer challenge, got %q", recorder.Header().Get("WWW-Authenticate"))
+	}
+	expectStatus(serveWithToken("GET", "/api/v1/accounts", "", "not-a-jwt"), 401, "list with a malformed token")
+	expectStatus(serveWithToken("GET", "/api/v1/accounts", "", token("maker", "transfers", -2*time.Minute, "transfers")), 401, "list with an expired token")
+	expectStatus(serveWithToken("GET", "/api/v1/accounts", "", token("maker", "transfers", time.Hour, "payments")), 401, "list with a token for another audience")
+
+	// Test case: tokens expired within the clock skew are still accepted
+	expectStatus(serveWithToken("GET", "/api/v1/accounts", "", token("maker", "transfers", -30*time.Second, "transfers")), 200, "list with a token expired within the skew")
+
+	// Test case: tokens without the required scope are refused with 403
+	recorder = serveWithToken("GET", "/api/v1/accounts", "", token("maker", "accounts:read", time.Hour, "transfers"))
+	expectStatus(recorder, 403, "list without the required scope")
+	if !strings.Contains(recorder.Header().Get("WWW-Authenticate"), "insufficient_scope") {
+		t.Errorf("Expected an insufficient_scope challenge, got %q", recorder.Header().Get("WWW-Authenticate"))
+	}
+
+	// Test case: the token's subject is the operator, whatever X-Operator-Id says
+	expectStatus(serveWithToken("POST", "/api/v1/accounts", `{"account_id": "src", "initial_balance": 10000}`, maker), 201, "create")
+	expectStatus(serveWithToken("POST", "/api/v1/accounts", `{"account_id": "dst"}`, maker), 201, "create")
+	var held account.TransferResponse
+	recorder = serveWithToken("POST", "/api/v1/accounts/transfer", `{"source_account_id": "src", "destination_account_id": "dst", "amount": 1500}`, maker)
+	expectStatus(recorder, 202, "transfer above the approval threshold")
+	json.Unmarshal(recorder.Body.Bytes(), &held)
+	if held.Transfer == nil || held.Transfer.InitiatedBy != "maker" {
+		t.Fatalf("Expected a transfer initiated by maker, got %s", recorder.Body)
+	}
+	approvePath := "/api/v1/transfers/" + held.Transfer.TransferId + "/approve"
+	expectStatus(serveWithToken("POST", approvePath, "", maker), 403, "approve by the initiator")
+	expectStatus(serveWithToken("POST", approvePath, "", token("checker", "transfers", time.Hour, "transfers")), 200, "approve")
+}
+
+func TestAccountRoutesWithOwnership(t *testing.T) {
+	// Setup
+	principals := map[string]auth.Principal{
+		"admin": {Subject: "svc-back-office", Scopes: []string{access.AdminScope}},
+		"alice": {Subject: "alice"},
+		"bob":   {Subject: "bob"},
+	}
+	router := newTestAccountRouter(t, func(c *gin.Context) {
+		if principal, ok := principals[c.GetHeader(middleware.OperatorHeader)]; ok {
+			c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+		}
+	}, service.WithAccessPolicy(service.NewOwnershipPolicy()))
+	expectStatus := func(recorder *httptest.ResponseRecorder, status int, call string) {
+		t.Helper()
+		if recorder.Code != status {
+			t.Errorf("Expected %s to answer %d, got %d: %s", call, status, recorder.Code, recorder.Body)
+		}
+	}
+	expectStatus(serve(router, "POST", "/api/v1/accounts", `{"account_id": "alice-1", "owner_id": "alice", "initial_balance": 1000}`, "admin"), 201, "create for alice")
+	expectStatus(serve(router, "POST", "/api/v1/accounts", `{"account_id": "bob-1", "owner_id": "bob", "initial_balance": 1000}`, "admin"), 201, "create for bob")
+
+	// Test case: only admins create accounts, and they name the owner
+	expectStatus(serve(router, "POST", "/api/v1/accounts", `{"account_id": "alice-2"}`, "alice"), 403, "create by alice")
+	expectStatus(serve(router, "POST", "/api/v1/accounts", `{"account_id": "alice-2", "owner_id": "alice"}`, "alice"), 403, "create by alice for herself")
+	expectStatus(serve(router, "POST", "/api/v1/accounts", `{"account_id": "house"}`, "admin"), 400, "create by the admin without owner")
+
+	// Test case: callers read and debit their own accounts, and are refused with 403 on the others
+	recorder := serve(router, "GET", "/api/v1/accounts/alice-1", "", "alice")
+	expectStatus(recorder, 200, "get own account")
+	if !strings.Contains(recorder.Body.String(), `"owner_id":"alice"`) {
+		t.Errorf("Expected the account to name its owner, got %s", recorder.Body)
+	}
+	expectStatus(serve(router, "GET", "/api/v1/accounts/bob-1", "", "alice"), 403, "get another's account")
+	expectStatus(serve(router, "POST", "/api/v1/accounts/transfer", `{"source_account_id": "alice-1", "destination_account_id": "bob-1", "amount": 100}`, "alice"), 200, "debit own account")
+	expectStatus(serve(router, "POST", "/api/v1/accounts/transfer", `{"source_account_id": "bob-1", "destination_account_id": "alice-1", "amount": 100}`, "alice"), 403, "debit another's account")
+
+	// Test case: callers list their own accounts, and admins all of them
+	var page account.ListAccountsResponse
+	json.Unmarshal(serve(router, "GET", "/api/v1/accounts", "", "bob").Body.Bytes(), &page)
+	if len(page.Accounts) != 1 || page.Accounts[0].AccountId != "bob-1" {
+		t.Errorf("Expected bob to list bob-1 only, got %+v", page.Accounts)
+	}
+	json.Unmarshal(serve(router, "GET", "/api/v1/accounts", "", "admin").Body.Bytes(), &page)
+	if len(page.Accounts) != 2 {
+		t.Errorf("Expected the admin to list 2 accounts, got %+v", page.Accounts)
+	}
+
+	// Test case: requests without a principal are refused with 401
+	expectStatus(serve(router, "GET", "/api/v1/accounts/alice-1", "", ""), 401, "get without a principal")
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"expvar"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupDebugRoutes sets up the runtime counters route behind guards, which are run before it
+func SetupDebugRoutes(router *gin.Engine, guards ...gin.HandlerFunc) {
+	router.GET("/debug/vars", append(guards, gin.WrapH(expvar.Handler()))...)
+}
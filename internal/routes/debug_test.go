@@ -0,0 +1,51 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"internal-transfer-microservice/internal/auth"
+	"internal-transfer-microservice/internal/config"
+	"internal-transfer-microservice/internal/domain/access"
+	"internal-transfer-microservice/internal/middleware"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDebugRoutesRequireAdminScope(t *testing.T) {
+	// Setup
+	secret := []byte("test-secret")
+	verifier, err := auth.NewJWTVerifier(&config.Config{Auth: config.AuthConfig{HMACSecret: string(secret)}})
+	if err != nil {
+		t.Fatalf("Expected a verifier, got %v", err)
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Authenticate(verifier))
+	SetupDebugRoutes(router, middleware.RequireScope(access.AdminScope))
+	serveWithScope := func(scope string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/debug/vars", nil)
+		if scope != "" {
+			signed, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+				"sub":   "operator",
+				"scope": scope,
+				"exp":   time.Now().Add(time.Hour).Unix(),
+			}).SignedString(secret)
+			req.Header.Set("Authorization", "Bearer "+signed)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	// Test case: the runtime counters need a token granted the admin scope
+	if recorder := serveWithScope(""); recorder.Code != 401 {
+		t.Errorf("Expected 401 without a token, got %d", recorder.Code)
+	}
+	if recorder := serveWithScope("transfers"); recorder.Code != 403 {
+		t.Errorf("Expected 403 without the admin scope, got %d", recorder.Code)
+	}
+	if recorder := serveWithScope("transfers " + access.AdminScope); recorder.Code != 200 {
+		t.Errorf("Expected 200 with the admin scope, got %d: %s", recorder.Code, recorder.Body)
+	}
+}
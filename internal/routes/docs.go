@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"internal-transfer-microservice/internal/controller"
+)
+
+// SetupDocsRoutes sets up the OpenAPI document and Swagger UI routes
+func SetupDocsRoutes(router *gin.Engine, docsController *controller.DocsController) {
+	router.GET("/openapi.json", docsController.GetOpenAPI)
+	router.GET("/docs", docsController.SwaggerUI)
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"internal-transfer-microservice/internal/controller"
+)
+
+// SetupScreeningRoutes sets up the watchlist screening routes
+func SetupScreeningRoutes(router *gin.Engine, screeningController *controller.ScreeningController) {
+	screeningRoutes := router.Group("/api/v1/screening")
+	{
+		screeningRoutes.GET("/hits", screeningController.ListHits)
+		screeningRoutes.POST("/hits/:id/review", screeningController.ReviewHit)
+	}
+}
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"internal-transfer-microservice/internal/controller"
+)
+
+// SetupStreamRoutes sets up the real-time account event stream routes
+func SetupStreamRoutes(router *gin.Engine, streamController *controller.StreamController) {
+	accountRoutes := router.Group("/api/v1/accounts")
+	{
+		accountRoutes.GET("/:id/events", streamController.StreamAccountEvents)
+	}
+}
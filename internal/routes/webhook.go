@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"internal-transfer-microservice/internal/controller"
+)
+
+// SetupWebhookRoutes sets up the webhook subscription and delivery routes
+func SetupWebhookRoutes(router *gin.Engine, webhookController *controller.WebhookController) {
+	webhookRoutes := router.Group("/api/v1/webhooks")
+	{
+		webhookRoutes.POST("", webhookController.CreateSubscription)
+		webhookRoutes.GET("", webhookController.ListSubscriptions)
+		webhookRoutes.DELETE("/:id", webhookController.DeleteSubscription)
+		webhookRoutes.GET("/:id/deliveries", webhookController.ListDeliveries)
+		webhookRoutes.GET("/:id/deliveries/:deliveryId", webhookController.GetDelivery)
+		webhookRoutes.POST("/:id/deliveries/:deliveryId/replay", webhookController.ReplayDelivery)
+	}
+}
@@ -0,0 +1,208 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/stream"
+	"internal-transfer-microservice/internal/domain/transfer"
+	transferv1 "internal-transfer-microservice/proto/transfer/v1"
+)
+
+// AccountServer serves transferv1.AccountService with the services behind the REST API
+type AccountServer struct {
+	transferv1.UnimplementedAccountServiceServer
+	accountService account.Service
+	streamService  stream.Service
+}
+
+// NewAccountServer creates a new AccountServer
+func NewAccountServer(accountService account.Service, streamService stream.Service) *AccountServer {
+	return &AccountServer{
+		accountService: accountService,
+		streamService:  streamService,
+	}
+}
+
+func (s *AccountServer) GetAccount(ctx context.Context, req *transferv1.GetAccountRequest) (*transferv1.Account, error) {
+	if req.GetAccountId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "account_id is required")
+	}
+
+	response, err := s.accountService.GetAccount(ctx, req.GetAccountId())
+	if err != nil {
+		return nil, statusFromError(err, "")
+	}
+	return toAccount(response), nil
+}
+
+func (s *AccountServer) CreateAccount(ctx context.Context, req *transferv1.CreateAccountRequest) (*transferv1.CreateAccountResponse, error) {
+	if req.GetAccountId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "account_id is required")
+	}
+
+	response, err := s.accountService.CreateAccount(ctx, account.CreateAccountRequest{
+		AccountId:      req.GetAccountId(),
+		HolderName:     req.GetHolderName(),
+		OwnerId:        req.GetOwnerId(),
+		InitialBalance: req.GetInitialBalance(),
+		AccountType:    req.GetAccountType(),
+		RatePlanId:     req.GetRatePlanId(),
+		Tier:           req.GetTier(),
+	})
+	if err != nil {
+		return nil, statusFromError(err, "")
+	}
+	return &transferv1.CreateAccountResponse{Message: response.Message}, nil
+}
+
+func (s *AccountServer) Transfer(ctx context.Context, req *transferv1.TransferRequest) (*transferv1.TransferResponse, error) {
+	// the account service validates transfers as well; these spare it the calls it would refuse
+	switch {
+	case req.GetSourceAccountId() == "" || req.GetDestinationAccountId() == "":
+		return nil, status.Error(codes.InvalidArgument, "source_account_id and destination_account_id are required")
+	case req.GetSourceAccountId() == req.GetDestinationAccountId():
+		return nil, status.Error(codes.InvalidArgument, "source and destination accounts must differ")
+	case req.GetAmount() <= 0:
+		return nil, status.Error(codes.InvalidArgument, "amount must be positive")
+	}
+
+	response, err := s.accountService.TxnAccount(ctx, req.GetSourceAccountId(), req.GetDestinationAccountId(), req.GetAmount())
+	if err != nil {
+		return nil, statusFromError(err, response.Message)
+	}
+	if response.ErrorCode != "" {
+		// refusals without an error, such as a lack of funds, carry their code as the REST API's error_code
+		return nil, statusWithReason(codes.FailedPrecondition, response.Message, response.ErrorCode)
+	}
+	return &transferv1.TransferResponse{
+		Message:  response.Message,
+		Transfer: toTransfer(response.Transfer),
+	}, nil
+}
+
+// StreamBalances sends the balance events of the account's stream. When events were dropped from the
+// backlog before the client resumed, the current balance is read and sent in their place.
+func (s *AccountServer) StreamBalances(req *transferv1.StreamBalancesRequest, srv transferv1.AccountService_StreamBalancesServer) error {
+	if req.GetAccountId() == "" {
+		return status.Error(codes.InvalidArgument, "account_id is required")
+	}
+
+	var lastEventId string
+	if req.GetLastEventId() > 0 {
+		lastEventId = strconv.FormatUint(req.GetLastEventId(), 10)
+	}
+	ctx := srv.Context()
+	events, err := s.streamService.Subscribe(ctx, req.GetAccountId(), lastEventId)
+	if err != nil {
+		return statusFromError(err, "")
+	}
+
+	for event := range events {
+		var update *transferv1.BalanceUpdate
+		switch event.Type {
+		case stream.EventBalance:
+			var changed stream.BalanceChanged
+			if err := json.Unmarshal(event.Data, &changed); err != nil {
+				return status.Errorf(codes.Internal, "decoding balance event %d: %v", event.ID, err)
+			}
+			update = &transferv1.BalanceUpdate{
+				AccountId:        changed.AccountId,
+				Balance:          changed.Balance,
+				AvailableBalance: changed.AvailableBalance,
+				TransferId:       changed.TransferId,
+				ChangedAt:        timestamppb.New(changed.ChangedAt),
+			}
+		case stream.EventReset:
+			response, err := s.accountService.GetAccount(ctx, req.GetAccountId())
+			if err != nil {
+				return statusFromError(err, "")
+			}
+			update = &transferv1.BalanceUpdate{
+				AccountId:        response.AccountId,
+				Balance:          response.Balance,
+				AvailableBalance: response.AvailableBalance,
+				ChangedAt:        timestamppb.Now(),
+			}
+		default:
+			continue
+		}
+		update.EventId = event.ID
+		if err := srv.Send(update); err != nil {
+			return err
+		}
+	}
+	// the stream only ends on its own when the server shuts down
+	return ctx.Err()
+}
+
+func toAccount(response *account.GetAccountResponse) *transferv1.Account {
+	acc := &transferv1.Account{
+		AccountId:             response.AccountId,
+		HolderName:            response.HolderName,
+		OwnerId:               response.OwnerId,
+		Balance:               response.Balance,
+		AccountType:           response.AccountType,
+		RatePlanId:            response.RatePlanId,
+		Tier:                  response.Tier,
+		OverdraftLimit:        response.OverdraftLimit,
+		OverdraftLimitVersion: int32(response.OverdraftLimitVersion),
+		OverdraftUsed:         response.OverdraftUsed,
+		AvailableBalance:      response.AvailableBalance,
+		Frozen:                response.Frozen,
+	}
+	if activity := response.Activity; activity != nil {
+		acc.Activity = &transferv1.Activity{
+			Inflow_30D:  activity.Inflow30d,
+			Outflow_30D: activity.Outflow30d,
+			AsOf:        timestamppb.New(activity.AsOf),
+		}
+		if last := activity.LastTransfer; last != nil {
+			acc.Activity.LastTransfer = &transferv1.LastTransfer{
+				TransferId:     last.TransferId,
+				Direction:      last.Direction,
+				CounterpartyId: last.CounterpartyId,
+				Amount:         last.Amount,
+				ExecutedAt:     toTimestamp(last.ExecutedAt),
+			}
+		}
+	}
+	return acc
+}
+
+func toTransfer(response *transfer.Response) *transferv1.Transfer {
+	if response == nil {
+		return nil
+	}
+	return &transferv1.Transfer{
+		TransferId:           response.TransferId,
+		SourceAccountId:      response.SourceAccountId,
+		DestinationAccountId: response.DestinationAccountId,
+		Amount:               response.Amount,
+		Fee:                  response.Fee,
+		Type:                 response.Type,
+		Status:               response.Status,
+		FailureReason:        response.FailureReason,
+		InitiatedBy:          response.InitiatedBy,
+		ReviewedBy:           response.ReviewedBy,
+		ReviewedAt:           toTimestamp(response.ReviewedAt),
+		ExpiresAt:            toTimestamp(response.ExpiresAt),
+		ExecutedAt:           toTimestamp(response.ExecutedAt),
+		CreatedAt:            toTimestamp(response.CreatedAt),
+	}
+}
+
+// toTimestamp converts an optional time, leaving the field unset when there is none
+func toTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
@@ -0,0 +1,66 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+
+	"internal-transfer-microservice/internal/domain/access"
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/limit"
+	"internal-transfer-microservice/internal/service"
+)
+
+// errorDomain names this service in the ErrorInfo details of the errors it returns
+const errorDomain = "transfer.v1"
+
+// statusFromError maps an error of the account and stream services to a gRPC status, with the same meaning
+// as the HTTP status the REST API answers with. message replaces the error text when it is set.
+func statusFromError(err error, message string) error {
+	if message == "" {
+		message = err.Error()
+	}
+
+	var exceeded *limit.ExceededError
+	code := codes.Internal
+	switch {
+	case errors.As(err, &exceeded):
+		// the limit code tells clients which limit was hit, as error_code does in the REST API
+		return statusWithReason(codes.ResourceExhausted, message, exceeded.Code)
+	case errors.Is(err, service.ErrOperatorRequired):
+		code = codes.Unauthenticated
+	case errors.Is(err, access.ErrForbidden):
+		code = codes.PermissionDenied
+	case errors.Is(err, service.ErrAccountNotFound), errors.Is(err, gorm.ErrRecordNotFound):
+		code = codes.NotFound
+	case errors.Is(err, account.ErrAccountExists):
+		code = codes.AlreadyExists
+	case errors.Is(err, service.ErrTransferDenied), errors.Is(err, service.ErrTransferBlocked), errors.Is(err, account.ErrAccountFrozen):
+		code = codes.FailedPrecondition
+	case errors.Is(err, service.ErrTransferAccountsRequired), errors.Is(err, service.ErrSameAccountTransfer),
+		errors.Is(err, service.ErrInvalidAmount), errors.Is(err, service.ErrInvalidLastEventId),
+		errors.Is(err, service.ErrOwnerRequired):
+		code = codes.InvalidArgument
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	}
+	return status.Error(code, message)
+}
+
+// statusWithReason returns a status carrying reason in its ErrorInfo details
+func statusWithReason(code codes.Code, message, reason string) error {
+	st, err := status.New(code, message).WithDetails(&errdetails.ErrorInfo{
+		Reason: reason,
+		Domain: errorDomain,
+	})
+	if err != nil {
+		return status.Error(code, message)
+	}
+	return st.Err()
+}
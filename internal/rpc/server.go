@@ -0,0 +1,199 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	"internal-transfer-microservice/internal/auth"
+	"internal-transfer-microservice/internal/middleware"
+	"internal-transfer-microservice/pkg/logger"
+	transferv1 "internal-transfer-microservice/proto/transfer/v1"
+)
+
+// Server is the gRPC server of the API, serving AccountService along with the standard health service
+// and server reflection
+type Server struct {
+	server *grpc.Server
+	health *health.Server
+	// verifier checks the bearer tokens of calls, or is nil to trust the x-operator-id metadata
+	verifier auth.Verifier
+	// streams ends the open balance streams when shutdown starts, as they never finish on their own
+	streams     context.Context
+	stopStreams context.CancelFunc
+}
+
+// NewServer creates a new Server serving accountServer. With a verifier, calls must carry a bearer token
+// in their authorization metadata, as REST requests do in their Authorization header.
+func NewServer(accountServer *AccountServer, verifier auth.Verifier) *Server {
+	s := &Server{health: health.NewServer(), verifier: verifier}
+	s.streams, s.stopStreams = context.WithCancel(context.Background())
+	s.server = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(logUnary, recoverUnary, s.identityUnary),
+		grpc.ChainStreamInterceptor(logStream, recoverStream, s.identityStream, s.endStreamsOnShutdown),
+	)
+
+	transferv1.RegisterAccountServiceServer(s.server, accountServer)
+	healthpb.RegisterHealthServer(s.server, s.health)
+	reflection.Register(s.server)
+	s.health.SetServingStatus(transferv1.AccountService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	return s
+}
+
+// Serve accepts connections on listener until the server is shut down
+func (s *Server) Serve(listener net.Listener) error {
+	return s.server.Serve(listener)
+}
+
+// Shutdown reports the server as not serving, ends the open streams and waits for the other calls to
+// finish, closing them when ctx is done first
+func (s *Server) Shutdown(ctx context.Context) {
+	s.health.Shutdown()
+	s.stopStreams()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.server.Stop()
+	}
+}
+
+// endStreamsOnShutdown ends the stream with an Unavailable status when shutdown starts, so clients
+// reconnect to another instance and resume where they left off
+func (s *Server) endStreamsOnShutdown(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, cancel := context.WithCancel(ss.Context())
+	defer cancel()
+	stop := context.AfterFunc(s.streams, cancel)
+	defer stop()
+
+	err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	if s.streams.Err() != nil {
+		return status.Error(codes.Unavailable, "server is shutting down")
+	}
+	return err
+}
+
+// contextStream replaces the context of a server stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// operatorIdentity puts the operator named in the x-operator-id metadata into ctx, trusted as sent like
+// the X-Operator-Id header of the REST API
+func operatorIdentity(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if operators := md.Get(middleware.OperatorHeader); len(operators) > 0 && operators[0] != "" {
+		return auth.WithPrincipal(ctx, auth.Principal{Subject: operators[0]})
+	}
+	return ctx
+}
+
+// identity puts the caller into ctx: the principal of the bearer token in the authorization metadata
+// when the server verifies tokens, or else the operator named in x-operator-id. Health checks and
+// reflection need no token.
+func (s *Server) identity(ctx context.Context, method string) (context.Context, error) {
+	if s.verifier == nil {
+		return operatorIdentity(ctx), nil
+	}
+	if !strings.HasPrefix(method, "/"+transferv1.AccountService_ServiceDesc.ServiceName+"/") {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 || !strings.HasPrefix(strings.ToLower(values[0]), "bearer ") {
+		return ctx, status.Error(codes.Unauthenticated, "a bearer token is required")
+	}
+	principal, err := s.verifier.Verify(ctx, strings.TrimSpace(values[0][len("bearer "):]))
+	switch {
+	case errors.Is(err, auth.ErrInsufficientScope):
+		return ctx, status.Error(codes.PermissionDenied, err.Error())
+	case err != nil:
+		return ctx, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return auth.WithPrincipal(ctx, principal), nil
+}
+
+func (s *Server) identityUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := s.identity(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) identityStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.identity(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
+
+// recovered turns a panic of a handler into an Internal status, as gin.Recovery does for REST handlers
+func recovered(method string, p interface{}) error {
+	logger.Errorf("panic serving %s: %v\n%s", method, p, debug.Stack())
+	return status.Error(codes.Internal, "internal error")
+}
+
+func recoverUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = recovered(info.FullMethod, p)
+		}
+	}()
+	return handler(ctx, req)
+}
+
+func recoverStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = recovered(info.FullMethod, p)
+		}
+	}()
+	return handler(srv, ss)
+}
+
+// logCall logs a finished call with its status, as gin.Logger does for REST requests
+func logCall(method string, start time.Time, err error) {
+	logger.WithFields(logger.Fields{
+		"method":   method,
+		"code":     status.Code(err).String(),
+		"duration": time.Since(start).String(),
+	}).Info("gRPC call")
+}
+
+func logUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	logCall(info.FullMethod, start, err)
+	return resp, err
+}
+
+func logStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	logCall(info.FullMethod, start, err)
+	return err
+}
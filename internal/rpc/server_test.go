@@ -0,0 +1,211 @@
+package rpc
+
+import (
+	"context"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"internal-transfer-microservice/internal/auth"
+	"internal-transfer-microservice/internal/domain/transfer"
+	"internal-transfer-microservice/internal/infrastructure/broker"
+	"internal-transfer-microservice/internal/infrastructure/cache"
+	"internal-transfer-microservice/internal/repository"
+	"internal-transfer-microservice/internal/service"
+	transferv1 "internal-transfer-microservice/proto/transfer/v1"
+	"net"
+	"testing"
+	"time"
+)
+
+// newTestClient serves the account and stream services from memory over an in-process connection,
+// authenticating calls with verifier when it is not nil
+func newTestClient(t *testing.T, verifier auth.Verifier) (*grpc.ClientConn, *Server) {
+	repo := repository.NewMemoryAccountRepo()
+	streamService := service.NewStreamService(broker.NewMemoryBroker(10), repo, nil)
+	accountService := service.NewAccountService(repo, cache.NewMemoryCache(), service.WithNotifier(streamService))
+	server := NewServer(NewAccountServer(accountService, streamService), verifier)
+
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to dial the test server: %v", err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		server.Shutdown(ctx)
+	})
+	return conn, server
+}
+
+func TestAccountServer(t *testing.T) {
+	// Setup
+	conn, _ := newTestClient(t, nil)
+	client := transferv1.NewAccountServiceClient(conn)
+	ctx := context.Background()
+	client.CreateAccount(ctx, &transferv1.CreateAccountRequest{AccountId: "src", InitialBalance: 100})
+	client.CreateAccount(ctx, &transferv1.CreateAccountRequest{AccountId: "dst"})
+
+	// Test case: domain errors map to their status codes
+	_, err := client.CreateAccount(ctx, &transferv1.CreateAccountRequest{AccountId: "src"})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Errorf("Expected AlreadyExists for a duplicate account, got %v", err)
+	}
+	_, err = client.GetAccount(ctx, &transferv1.GetAccountRequest{AccountId: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for a missing account, got %v", err)
+	}
+	_, err = client.Transfer(ctx, &transferv1.TransferRequest{SourceAccountId: "src", DestinationAccountId: "dst", Amount: -5})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for a negative amount, got %v", err)
+	}
+	_, err = client.Transfer(ctx, &transferv1.TransferRequest{SourceAccountId: "missing", DestinationAccountId: "dst", Amount: 5})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for a missing source account, got %v", err)
+	}
+
+	// Test case: a transfer refused for lack of funds fails with its error code as the reason
+	_, err = client.Transfer(ctx, &transferv1.TransferRequest{SourceAccountId: "src", DestinationAccountId: "dst", Amount: 500})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition for insufficient funds, got %v", err)
+	}
+	var reason string
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			reason = info.GetReason()
+		}
+	}
+	if reason != transfer.CodeInsufficientFunds {
+		t.Errorf("Expected reason %s, got %q", transfer.CodeInsufficientFunds, reason)
+	}
+
+	// Test case: a transfer is streamed to the balance streams of its accounts
+	streamCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	balances, err := client.StreamBalances(streamCtx, &transferv1.StreamBalancesRequest{AccountId: "dst"})
+	if err != nil {
+		t.Fatalf("Expected the stream to open, got %v", err)
+	}
+	// the stream only subscribes once the server receives the call, which the first receive waits for,
+	// so the transfer is retried until an update arrives
+	received := make(chan *transferv1.BalanceUpdate, 1)
+	go func() {
+		update, err := balances.Recv()
+		if err == nil {
+			received <- update
+		}
+		close(received)
+	}()
+	var response *transferv1.TransferResponse
+	var update *transferv1.BalanceUpdate
+	for update == nil {
+		response, err = client.Transfer(ctx, &transferv1.TransferRequest{SourceAccountId: "src", DestinationAccountId: "dst", Amount: 10})
+		if err != nil {
+			t.Fatalf("Expected the transfer to succeed, got %v", err)
+		}
+		select {
+		case update = <-received:
+			if update == nil {
+				t.Fatal("Expected a balance update before the stream ended")
+			}
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	if response.GetTransfer().GetStatus() != transfer.StatusCompleted {
+		t.Errorf("Expected a completed transfer, got %q", response.GetTransfer().GetStatus())
+	}
+	if update.GetAccountId() != "dst" || update.GetBalance() <= 0 || update.GetEventId() == 0 {
+		t.Errorf("Expected a numbered credit to dst, got %v", update)
+	}
+}
+
+func TestServerHealthAndShutdown(t *testing.T) {
+	// Setup
+	conn, server := newTestClient(t, nil)
+	ctx := context.Background()
+	transferv1.NewAccountServiceClient(conn).CreateAccount(ctx, &transferv1.CreateAccountRequest{AccountId: "acc"})
+	health := healthpb.NewHealthClient(conn)
+
+	// Test case: the account service reports serving
+	resp, err := health.Check(ctx, &healthpb.HealthCheckRequest{Service: transferv1.AccountService_ServiceDesc.ServiceName})
+	if err != nil || resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Expected SERVING, got %v (%v)", resp.GetStatus(), err)
+	}
+
+	// Test case: shutdown ends open balance streams with Unavailable
+	balances, err := transferv1.NewAccountServiceClient(conn).StreamBalances(ctx, &transferv1.StreamBalancesRequest{AccountId: "acc"})
+	if err != nil {
+		t.Fatalf("Expected the stream to open, got %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	shutdownCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	server.Shutdown(shutdownCtx)
+	if _, err := balances.Recv(); status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected Unavailable once shutdown starts, got %v", err)
+	}
+}
+
+// tokenVerifier accepts the tokens it maps to a principal, refusing principals without the transfers scope
+type tokenVerifier map[string]auth.Principal
+
+func (v tokenVerifier) Verify(_ context.Context, token string) (auth.Principal, error) {
+	principal, ok := v[token]
+	if !ok {
+		return auth.Principal{}, auth.ErrInvalidToken
+	}
+	if !principal.HasScope("transfers") {
+		return principal, auth.ErrInsufficientScope
+	}
+	return principal, nil
+}
+
+func TestServerBearerTokens(t *testing.T) {
+	// Setup
+	conn, _ := newTestClient(t, tokenVerifier{
+		"maker-token":    {Subject: "maker", Scopes: []string{"transfers"}},
+		"readonly-token": {Subject: "reader"},
+	})
+	client := transferv1.NewAccountServiceClient(conn)
+	health := healthpb.NewHealthClient(conn)
+	bearer := func(token string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token, "x-operator-id", "spoofed")
+	}
+
+	// Test case: calls without a valid token are Unauthenticated, and those lacking a scope PermissionDenied
+	_, err := client.GetAccount(context.Background(), &transferv1.GetAccountRequest{AccountId: "acc"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated without a token, got %v", err)
+	}
+	_, err = client.GetAccount(bearer("forged"), &transferv1.GetAccountRequest{AccountId: "acc"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated for an unknown token, got %v", err)
+	}
+	_, err = client.GetAccount(bearer("readonly-token"), &transferv1.GetAccountRequest{AccountId: "acc"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied without the scope, got %v", err)
+	}
+
+	// Test case: calls carry the subject of the token rather than the x-operator-id metadata
+	client.CreateAccount(bearer("maker-token"), &transferv1.CreateAccountRequest{AccountId: "src", InitialBalance: 100})
+	client.CreateAccount(bearer("maker-token"), &transferv1.CreateAccountRequest{AccountId: "dst"})
+	response, err := client.Transfer(bearer("maker-token"), &transferv1.TransferRequest{SourceAccountId: "src", DestinationAccountId: "dst", Amount: 10})
+	if err != nil || response.GetTransfer().GetInitiatedBy() != "maker" {
+		t.Errorf("Expected a transfer initiated by maker, got %v (%v)", response, err)
+	}
+
+	// Test case: the health service needs no token
+	if _, err := health.Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Errorf("Expected health checks without a token, got %v", err)
+	}
+}
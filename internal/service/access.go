@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"internal-transfer-microservice/internal/auth"
+	"internal-transfer-microservice/internal/domain/access"
+	"internal-transfer-microservice/internal/domain/account"
+)
+
+// OwnershipPolicy lets principals read and debit the accounts they own, and principals granted
+// access.AdminScope take every action on every account
+type OwnershipPolicy struct{}
+
+// NewOwnershipPolicy creates the access policy of a service whose callers are authenticated
+func NewOwnershipPolicy() access.Policy {
+	return OwnershipPolicy{}
+}
+
+func (OwnershipPolicy) Authorize(principal auth.Principal, action string, acc *account.Model) error {
+	if principal.HasScope(access.AdminScope) {
+		return nil
+	}
+	if acc == nil {
+		return fmt.Errorf("%w: %s needs the %s scope", access.ErrForbidden, action, access.AdminScope)
+	}
+	owned := acc.OwnerId != "" && acc.OwnerId == principal.Subject
+	if !owned || action != access.ActionRead && action != access.ActionDebit {
+		return fmt.Errorf("%w: %s account %s", access.ErrForbidden, action, acc.AccountId)
+	}
+	return nil
+}
+
+func (OwnershipPolicy) ListableOwner(principal auth.Principal) string {
+	if principal.HasScope(access.AdminScope) {
+		return ""
+	}
+	return principal.Subject
+}
+
+// accessGuard enforces an access policy on behalf of the principal of the request. Without a policy
+// every action is allowed, as the callers of a service without authentication cannot be told apart.
+type accessGuard struct {
+	policy access.Policy
+}
+
+// authorize fails with ErrOperatorRequired when ctx carries no principal, or with the refusal of the policy
+func (g accessGuard) authorize(ctx context.Context, action string, acc *account.Model) error {
+	if g.policy == nil {
+		return nil
+	}
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok || principal.Subject == "" {
+		return ErrOperatorRequired
+	}
+	return g.policy.Authorize(principal, action, acc)
+}
+
+// listableOwner returns the owner whose accounts the principal of ctx may list, or an empty string for all of them
+func (g accessGuard) listableOwner(ctx context.Context) (string, error) {
+	if g.policy == nil {
+		return "", nil
+	}
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok || principal.Subject == "" {
+		return "", ErrOperatorRequired
+	}
+	return g.policy.ListableOwner(principal), nil
+}
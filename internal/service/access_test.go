@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"internal-transfer-microservice/internal/auth"
+	"internal-transfer-microservice/internal/domain/access"
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/infrastructure/cache"
+	"internal-transfer-microservice/internal/repository"
+	"testing"
+)
+
+func adminContext(subject string) context.Context {
+	return auth.WithPrincipal(context.Background(), auth.Principal{Subject: subject, Scopes: []string{access.AdminScope}})
+}
+
+func TestOwnershipPolicy(t *testing.T) {
+	// Setup
+	repo := repository.NewMemoryAccountRepo()
+	service := NewAccountService(repo, cache.NewMemoryCache(), WithAccessPolicy(NewOwnershipPolicy()))
+	admin := adminContext("svc-back-office")
+
+	for _, req := range []account.CreateAccountRequest{
+		{AccountId: "alice-1", OwnerId: "alice", InitialBalance: 1000.0},
+		{AccountId: "alice-2", OwnerId: "alice", InitialBalance: 0},
+		{AccountId: "bob-1", OwnerId: "bob", InitialBalance: 1000.0},
+	} {
+		if _, err := service.CreateAccount(admin, req); err != nil {
+			t.Fatalf("Expected the admin to create %s, got %v", req.AccountId, err)
+		}
+	}
+	if _, err := service.CreateAccount(admin, account.CreateAccountRequest{AccountId: "house", OwnerId: "svc-back-office"}); err != nil {
+		t.Fatalf("Expected the admin to create house, got %v", err)
+	}
+
+	// Test case: admins name the owner of the accounts they create, rather than owning them all
+	if _, err := service.CreateAccount(admin, account.CreateAccountRequest{AccountId: "unowned"}); !errors.Is(err, ErrOwnerRequired) {
+		t.Errorf("Expected ErrOwnerRequired creating an account without owner as the admin, got %v", err)
+	}
+	if _, err := repo.GetAccount(context.Background(), "unowned"); err == nil {
+		t.Errorf("Expected the account without owner not to be created")
+	}
+
+	// Test case: non-admins create no accounts, not even their own, as they would set the balance
+	for _, req := range []account.CreateAccountRequest{
+		{AccountId: "alice-3", InitialBalance: 1000000.0},
+		{AccountId: "alice-3", OwnerId: "alice", InitialBalance: 1000000.0},
+	} {
+		if _, err := service.CreateAccount(operatorContext("alice"), req); !errors.Is(err, access.ErrForbidden) {
+			t.Errorf("Expected ErrForbidden creating %+v as alice, got %v", req, err)
+		}
+	}
+
+	// Test case: owners read their accounts, and no one else's
+	if response, err := service.GetAccount(operatorContext("alice"), "alice-1"); err != nil || response.OwnerId != "alice" {
+		t.Errorf("Expected alice to read alice-1, got %+v (%v)", response, err)
+	}
+	if _, err := service.GetAccount(operatorContext("alice"), "bob-1"); !errors.Is(err, access.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden reading bob-1 as alice, got %v", err)
+	}
+	if _, err := service.GetTransferHistory(operatorContext("alice"), "bob-1"); !errors.Is(err, access.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden reading the transfers of bob-1 as alice, got %v", err)
+	}
+
+	// Test case: owners only list their accounts, and admins list them all
+	page, err := service.ListAccounts(operatorContext("alice"), "", 0)
+	if err != nil || len(page.Accounts) != 2 {
+		t.Errorf("Expected alice to list 2 accounts, got %+v (%v)", page, err)
+	}
+	page, err = service.ListAccounts(admin, "", 0)
+	if err != nil || len(page.Accounts) != 4 {
+		t.Errorf("Expected the admin to list 4 accounts, got %+v (%v)", page, err)
+	}
+
+	// Test case: owners debit their accounts, into any account
+	if _, err := service.TxnAccount(operatorContext("alice"), "alice-1", "bob-1", 100.0); err != nil {
+		t.Errorf("Expected alice to debit alice-1, got %v", err)
+	}
+	if _, err := service.TxnAccount(operatorContext("alice"), "bob-1", "alice-2", 100.0); !errors.Is(err, access.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden debiting bob-1 as alice, got %v", err)
+	}
+	// a negative amount from an owned account would debit the destination
+	if _, err := service.TxnAccount(operatorContext("alice"), "alice-1", "bob-1", -100.0); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("Expected ErrInvalidAmount for a negative amount, got %v", err)
+	}
+	bob, _ := repo.GetAccount(context.Background(), "bob-1")
+	if bob.Balance != 1100.0 {
+		t.Errorf("Expected the refused transfers to leave bob-1 at 1100, got %f", bob.Balance)
+	}
+
+	// Test case: admins debit any account
+	if _, err := service.TxnAccount(admin, "bob-1", "alice-2", 100.0); err != nil {
+		t.Errorf("Expected the admin to debit bob-1, got %v", err)
+	}
+
+	// Test case: owners neither create accounts nor change their settings
+	if _, err := service.CreateAccount(operatorContext("alice"), account.CreateAccountRequest{AccountId: "alice-3"}); !errors.Is(err, access.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden creating an account as alice, got %v", err)
+	}
+	if _, err := service.SetAccountFrozen(operatorContext("alice"), "alice-1", account.FreezeAccountRequest{Frozen: true}); !errors.Is(err, access.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden freezing alice-1 as alice, got %v", err)
+	}
+	if _, err := service.ListPendingTransfers(operatorContext("alice")); !errors.Is(err, access.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden listing held transfers as alice, got %v", err)
+	}
+	if _, err := service.ListPendingTransfers(admin); err != nil {
+		t.Errorf("Expected the admin to list held transfers, got %v", err)
+	}
+
+	// Test case: requests without a principal are refused
+	if _, err := service.GetAccount(context.Background(), "alice-1"); !errors.Is(err, ErrOperatorRequired) {
+		t.Errorf("Expected ErrOperatorRequired, got %v", err)
+	}
+	if _, err := service.ListAccounts(context.Background(), "", 0); !errors.Is(err, ErrOperatorRequired) {
+		t.Errorf("Expected ErrOperatorRequired, got %v", err)
+	}
+}
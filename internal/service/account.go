@@ -3,53 +3,250 @@ package service
 import (
 	"context"
 	"errors"
-	"fmt"
+	"github.com/google/uuid"
+	"internal-transfer-microservice/internal/auth"
+	"internal-transfer-microservice/internal/domain/access"
 	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/fee"
+	"internal-transfer-microservice/internal/domain/limit"
+	"internal-transfer-microservice/internal/domain/readmodel"
+	"internal-transfer-microservice/internal/domain/risk"
+	"internal-transfer-microservice/internal/domain/screening"
+	"internal-transfer-microservice/internal/domain/stream"
+	"internal-transfer-microservice/internal/domain/transfer"
 	"internal-transfer-microservice/internal/infrastructure/cache"
+	"internal-transfer-microservice/internal/infrastructure/db"
+	"internal-transfer-microservice/pkg/logger"
 	"time"
 )
 
-const UpdateAccountResourceLockKey = "update_account:%s"
+// TransferHistoryLimit caps the number of transfers returned by GetTransferHistory
+const TransferHistoryLimit = 100
 
-var ErrAccountNotFound = errors.New("account not found")
+// DefaultListAccountsLimit is the page size of ListAccounts when none is given, and MaxListAccountsLimit caps it
+const (
+	DefaultListAccountsLimit = 50
+	MaxListAccountsLimit     = 200
+)
+
+var (
+	ErrAccountNotFound          = errors.New("account not found")
+	ErrOwnerRequired            = errors.New("owner_id is required when an admin creates an account")
+	ErrTransferAccountsRequired = errors.New("source and destination accounts are required")
+	ErrSameAccountTransfer      = errors.New("source and destination accounts must differ")
+	ErrInvalidAmount            = errors.New("amount must be positive")
+	ErrInvalidOverdraftLimit    = errors.New("overdraft limit must not be negative")
+	ErrOverdraftLimitBelowUsage = errors.New("overdraft limit is below the overdraft currently in use")
+	ErrTransferDenied           = errors.New("transfer denied by risk screening")
+	ErrTransferBlocked          = errors.New("transfer blocked by watchlist screening")
+)
 
 type AccountServiceImpl struct {
-	cache            cache.Cache
-	repo             account.Repository
-	lockPollInterval time.Duration
+	accountLocker
+	accessGuard
+	repo        account.Repository
+	feeEngine   fee.Engine
+	limitEngine limit.Engine
+
+	riskEvaluator      risk.RiskEvaluator
+	riskVelocityWindow time.Duration
+	screener           screening.Screener
+
+	approvalThreshold float64
+	approvalExpiry    time.Duration
+
+	notifier  stream.Notifier
+	readModel readmodel.Repository
 }
 
-func (a *AccountServiceImpl) acquireLockWithPolling(ctx context.Context, key string, lockTTL, waitTimeout time.Duration) (token string, err error) {
-	deadline := time.Now().Add(waitTimeout)
-	for {
-		_, err = a.cache.Lock(ctx, key, 0)
-		if err == nil {
-			return key, nil
-		}
-		if time.Now().After(deadline) {
-			return "", fmt.Errorf("timed out waiting for %s", key)
-		}
-		time.Sleep(a.lockPollInterval)
+// Option configures an optional collaborator of AccountServiceImpl
+type Option func(*AccountServiceImpl)
+
+// WithFeeEngine charges transfer fees computed by engine
+func WithFeeEngine(engine fee.Engine) Option {
+	return func(a *AccountServiceImpl) {
+		a.feeEngine = engine
+	}
+}
+
+// WithLimitEngine enforces the velocity and transfer limits evaluated by engine
+func WithLimitEngine(engine limit.Engine) Option {
+	return func(a *AccountServiceImpl) {
+		a.limitEngine = engine
+	}
+}
+
+// WithRiskEvaluator screens every transfer with evaluator, measuring recent velocity over velocityWindow
+func WithRiskEvaluator(evaluator risk.RiskEvaluator, velocityWindow time.Duration) Option {
+	return func(a *AccountServiceImpl) {
+		a.riskEvaluator = evaluator
+		a.riskVelocityWindow = velocityWindow
+	}
+}
+
+// WithScreener checks the holders of both accounts of every transfer against the watchlist of screener
+func WithScreener(screener screening.Screener) Option {
+	return func(a *AccountServiceImpl) {
+		a.screener = screener
+	}
+}
+
+// WithApprovalPolicy holds transfers above threshold for a second operator's approval, expiring them after expiry
+func WithApprovalPolicy(threshold float64, expiry time.Duration) Option {
+	return func(a *AccountServiceImpl) {
+		a.approvalThreshold = threshold
+		a.approvalExpiry = expiry
+	}
+}
+
+// WithNotifier publishes balance and transfer changes to the account event streams
+func WithNotifier(notifier stream.Notifier) Option {
+	return func(a *AccountServiceImpl) {
+		a.notifier = notifier
+	}
+}
+
+// WithReadModel serves account reads from the read model, leaving transfers on the transactional path
+func WithReadModel(repo readmodel.Repository) Option {
+	return func(a *AccountServiceImpl) {
+		a.readModel = repo
+	}
+}
+
+// WithAccessPolicy restricts every caller to the accounts and actions policy allows it
+func WithAccessPolicy(policy access.Policy) Option {
+	return func(a *AccountServiceImpl) {
+		a.policy = policy
 	}
 }
 
 func (a *AccountServiceImpl) GetAccount(ctx context.Context, accountId string) (*account.GetAccountResponse, error) {
+	if a.readModel != nil {
+		response, err := a.getAccountSummary(ctx, accountId)
+		// accounts the projector has not caught up with yet are read from the primary
+		if !errors.Is(err, readmodel.ErrSummaryNotFound) {
+			return response, err
+		}
+	}
+
 	acc, err := a.repo.GetAccount(ctx, accountId)
 	if err != nil {
 		return nil, err
 	}
-	response := &account.GetAccountResponse{
-		AccountId: acc.AccountId,
-		Balance:   acc.Balance,
+	if err := a.authorize(ctx, access.ActionRead, acc); err != nil {
+		return nil, err
+	}
+	return toGetAccountResponse(acc), nil
+}
+
+// getAccountSummary reads the account from the read model, with its last transfer and recent flows
+func (a *AccountServiceImpl) getAccountSummary(ctx context.Context, accountId string) (*account.GetAccountResponse, error) {
+	summary, err := a.readModel.GetSummary(ctx, accountId)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.authorize(ctx, access.ActionRead, summary.Account()); err != nil {
+		return nil, err
+	}
+	since := readmodel.Day(time.Now().AddDate(0, 0, 1-readmodel.FlowWindowDays))
+	inflow, outflow, err := a.readModel.GetFlowTotals(ctx, accountId, since)
+	if err != nil {
+		return nil, err
+	}
+
+	response := toGetAccountResponse(summary.Account())
+	response.Activity = &account.Activity{
+		Inflow30d:  inflow,
+		Outflow30d: outflow,
+		AsOf:       summary.UpdatedAt,
+	}
+	if summary.LastTransferId != "" {
+		response.Activity.LastTransfer = &account.LastTransfer{
+			TransferId:     summary.LastTransferId,
+			Direction:      summary.LastTransferDirection,
+			CounterpartyId: summary.LastTransferCounterparty,
+			Amount:         summary.LastTransferAmount,
+			ExecutedAt:     summary.LastTransferAt,
+		}
+	}
+	return response, nil
+}
+
+// ListAccounts reads accounts from the primary store, so they come without the read model's activity
+func (a *AccountServiceImpl) ListAccounts(ctx context.Context, after string, limit int) (*account.ListAccountsResponse, error) {
+	if limit <= 0 {
+		limit = DefaultListAccountsLimit
+	}
+	limit = min(limit, MaxListAccountsLimit)
+	ownerId, err := a.listableOwner(ctx)
+	if err != nil {
+		return nil, err
 	}
 
+	// one account more than the page tells whether there is a next page
+	accounts, err := a.repo.ListAccounts(ctx, ownerId, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	response := &account.ListAccountsResponse{Accounts: make([]*account.GetAccountResponse, 0, len(accounts))}
+	if len(accounts) > limit {
+		accounts = accounts[:limit]
+		response.NextAfter = accounts[limit-1].AccountId
+	}
+	for i := range accounts {
+		response.Accounts = append(response.Accounts, toGetAccountResponse(&accounts[i]))
+	}
 	return response, nil
 }
 
-func (a *AccountServiceImpl) CreateAccount(ctx context.Context, accountId string, balance float64) (account.ApiResponse, error) {
+func toGetAccountResponse(acc *account.Model) *account.GetAccountResponse {
+	return &account.GetAccountResponse{
+		AccountId:             acc.AccountId,
+		HolderName:            acc.HolderName,
+		OwnerId:               acc.OwnerId,
+		Balance:               acc.Balance,
+		AccountType:           acc.AccountType,
+		RatePlanId:            acc.RatePlanId,
+		Tier:                  acc.Tier,
+		OverdraftLimit:        acc.OverdraftLimit,
+		OverdraftLimitVersion: acc.OverdraftLimitVersion,
+		OverdraftUsed:         acc.OverdraftUsed(),
+		AvailableBalance:      acc.AvailableBalance(),
+		Frozen:                acc.Frozen,
+	}
+}
+
+func (a *AccountServiceImpl) CreateAccount(ctx context.Context, req account.CreateAccountRequest) (account.ApiResponse, error) {
+	accountType := req.AccountType
+	if accountType == "" {
+		accountType = account.DefaultAccountType
+	}
+	tier := req.Tier
+	if tier == "" {
+		tier = account.DefaultTier
+	}
+	// accounts belong to whoever creates them unless the request names their owner. Only admins create accounts
+	// once callers authenticate, and they open them for others, so they must name the owner rather than own
+	// every account they create; non-admins may not create accounts, even their own, as they set the balance.
+	principal, _ := auth.PrincipalFromContext(ctx)
+	ownerId := req.OwnerId
+	if ownerId == "" {
+		if principal.HasScope(access.AdminScope) {
+			return account.ApiResponse{Message: "Account owner is required"}, ErrOwnerRequired
+		}
+		ownerId = principal.Subject
+	}
 	newAccount := &account.Model{
-		AccountId: accountId,
-		Balance:   balance,
+		AccountId:   req.AccountId,
+		HolderName:  req.HolderName,
+		OwnerId:     ownerId,
+		Balance:     req.InitialBalance,
+		AccountType: accountType,
+		RatePlanId:  req.RatePlanId,
+		Tier:        tier,
+	}
+	if err := a.authorize(ctx, access.ActionManage, newAccount); err != nil {
+		return account.ApiResponse{Message: "Not allowed to create accounts"}, err
 	}
 
 	err := a.repo.CreateAccount(ctx, newAccount)
@@ -60,54 +257,456 @@ func (a *AccountServiceImpl) CreateAccount(ctx context.Context, accountId string
 	return account.ApiResponse{Message: "Account created successfully"}, nil
 }
 
-func (a *AccountServiceImpl) TxnAccount(ctx context.Context, sourceAccountId, destAccountId string, amount float64) (account.ApiResponse, error) {
-	lock1Key := fmt.Sprintf(UpdateAccountResourceLockKey, sourceAccountId)
-	lock2Key := fmt.Sprintf(UpdateAccountResourceLockKey, destAccountId)
-	if sourceAccountId > destAccountId {
-		lock1Key, lock2Key = lock2Key, lock1Key // to avoid deadlock, in case of concurrent txn (A->B) & (B->A)
+func (a *AccountServiceImpl) TxnAccount(ctx context.Context, sourceAccountId, destAccountId string, amount float64) (account.TransferResponse, error) {
+	if err := validateTransfer(sourceAccountId, destAccountId, amount); err != nil {
+		return account.TransferResponse{}, err
 	}
 
+	// the balances read here are written back, so they must not come from a lagging replica
+	ctx = db.WithPrimary(ctx)
+
 	// resource locking
-	resource1, err := a.acquireLockWithPolling(ctx, lock1Key, 0, 100*time.Millisecond)
+	release, err := a.lockTransferAccounts(ctx, sourceAccountId, destAccountId)
 	if err != nil {
-		return account.ApiResponse{Message: "Failed to acquire lock for transaction"}, err
+		return account.TransferResponse{Message: "Failed to acquire lock for transaction"}, err
 	}
-	defer a.cache.Release(ctx, resource1)
+	defer release()
 
-	resource2, err := a.acquireLockWithPolling(ctx, lock2Key, 0, 100*time.Millisecond)
+	now := time.Now().UTC()
+	txn := &transfer.Model{
+		SourceAccountId:      sourceAccountId,
+		DestinationAccountId: destAccountId,
+		Amount:               amount,
+		Type:                 transfer.TypeTransfer,
+		InitiatedBy:          auth.SubjectFromContext(ctx),
+	}
+	txn.CreatedAt = &now
+	return a.executeTransfer(ctx, txn)
+}
+
+// validateTransfer refuses transfers every transport must refuse, whatever its own validation lets through
+func validateTransfer(sourceAccountId, destAccountId string, amount float64) error {
+	switch {
+	case sourceAccountId == "" || destAccountId == "":
+		return ErrTransferAccountsRequired
+	case sourceAccountId == destAccountId:
+		// the account would be read twice and both copies written back, the credit overwriting the debit
+		return ErrSameAccountTransfer
+	case !(amount > 0):
+		// a negative amount would move money out of the destination, which the caller need not be allowed to debit
+		return ErrInvalidAmount
+	}
+	return nil
+}
+
+// lockTransferAccounts locks the accounts a transfer between source and destination checks before moving the money.
+// The revenue account is not locked: nothing is checked on it, and its fee credit is applied to the stored balance,
+// so every transfer charging a fee need not queue behind the same account.
+func (a *AccountServiceImpl) lockTransferAccounts(ctx context.Context, sourceAccountId, destAccountId string) (func(), error) {
+	return a.lockAccounts(ctx, sourceAccountId, destAccountId)
+}
+
+// executeTransfer runs the checks of a transfer whose accounts are locked and moves the money once they pass.
+// Transfers an operator approved out of a pending state skip risk screening and the approval gate.
+func (a *AccountServiceImpl) executeTransfer(ctx context.Context, txn *transfer.Model) (account.TransferResponse, error) {
+	approved := txn.ReviewedBy != ""
+	persisted := txn.ID != uuid.Nil
+	now := time.Now().UTC()
+
+	sourceAccount, err := a.repo.GetAccount(ctx, txn.SourceAccountId)
 	if err != nil {
-		return account.ApiResponse{Message: "Failed to acquire lock for transaction"}, err
+		return a.abortTransfer(ctx, txn, persisted, account.TransferResponse{Message: "Source account not found", ErrorCode: transfer.CodeAccountNotFound}, ErrAccountNotFound)
+	}
+	// the caller moving the money, whether initiator or approver, must be allowed to debit the source
+	if err := a.authorize(ctx, access.ActionDebit, sourceAccount); err != nil {
+		return account.TransferResponse{Message: "Not allowed to debit the source account"}, err
 	}
-	defer a.cache.Release(ctx, resource2)
 
-	sourceAccount, err := a.repo.GetAccount(ctx, sourceAccountId)
+	// the counters read here may be stale by the time the transfer is recorded, so this check refuses transfers
+	// early, and the transaction recording the transfer only increments the counters within the caps
+	if a.limitEngine != nil {
+		if err := a.limitEngine.Check(ctx, sourceAccount.AccountId, sourceAccount.Tier, txn.Amount, now); err != nil {
+			var exceeded *limit.ExceededError
+			if errors.As(err, &exceeded) {
+				return a.abortTransfer(ctx, txn, persisted, limitExceededResponse(exceeded), err)
+			}
+			return account.TransferResponse{Message: "Failed to evaluate transfer limits"}, err
+		}
+		caps := a.limitEngine.Caps(sourceAccount.Tier)
+		txn.LimitCaps = &caps
+	}
+
+	revenueAccountId := ""
+	txn.Fee, txn.FeeBreakdown = 0, nil
+	if a.feeEngine != nil && txn.SourceAccountId != a.feeEngine.RevenueAccountId() {
+		revenueAccountId = a.feeEngine.RevenueAccountId()
+		txn.FeeBreakdown = a.feeEngine.Calculate(sourceAccount.AccountType, txn.Amount)
+	}
+	if txn.FeeBreakdown != nil {
+		txn.Fee = txn.FeeBreakdown.Total
+	}
+
+	if sourceAccount.AvailableBalance() < txn.Amount+txn.Fee {
+		return a.abortTransfer(ctx, txn, persisted, account.TransferResponse{Message: "Insufficient balance", ErrorCode: transfer.CodeInsufficientFunds}, nil)
+	}
+
+	destAccount, err := a.repo.GetAccount(ctx, txn.DestinationAccountId)
 	if err != nil {
-		return account.ApiResponse{Message: "Source account not found"}, ErrAccountNotFound
+		return a.abortTransfer(ctx, txn, persisted, account.TransferResponse{Message: "Destination account not found", ErrorCode: transfer.CodeAccountNotFound}, ErrAccountNotFound)
 	}
 
-	if sourceAccount.Balance < amount {
-		return account.ApiResponse{Message: "Insufficient balance"}, nil
+	if sourceAccount.Frozen || destAccount.Frozen {
+		return a.abortTransfer(ctx, txn, persisted, account.TransferResponse{Message: "Account is frozen", ErrorCode: transfer.CodeAccountFrozen}, account.ErrAccountFrozen)
+	}
+
+	if !approved {
+		if response, held, err := a.holdTransfer(ctx, txn, sourceAccount, destAccount, now); held || err != nil {
+			return response, err
+		}
+	}
+
+	updated := []*account.Model{sourceAccount, destAccount}
+	sourceAccount.Debit(txn.Amount + txn.Fee)
+	destAccount.Credit(txn.Amount)
+	if txn.Fee > 0 {
+		revenueAccount := destAccount
+		if revenueAccountId != txn.DestinationAccountId {
+			revenueAccount, err = a.repo.GetAccount(ctx, revenueAccountId)
+			if err != nil {
+				return account.TransferResponse{Message: "Fee revenue account not found"}, ErrAccountNotFound
+			}
+			updated = append(updated, revenueAccount)
+		}
+		revenueAccount.Credit(txn.Fee)
 	}
 
-	destAccount, err := a.repo.GetAccount(ctx, destAccountId)
+	txn.Status = transfer.StatusCompleted
+	txn.ExecutedAt = &now
+	err = a.saveTransfer(ctx, txn, updated...)
+	if errors.Is(err, account.ErrOverdraftLimitExceeded) {
+		return a.abortTransfer(ctx, txn, persisted, account.TransferResponse{Message: "Insufficient balance", ErrorCode: transfer.CodeInsufficientFunds}, nil)
+	}
+	var exceeded *limit.ExceededError
+	if errors.As(err, &exceeded) {
+		// concurrent transfers took the allowance the check saw
+		exceeded.Remaining, _ = a.limitEngine.Remaining(ctx, sourceAccount.AccountId, sourceAccount.Tier, now)
+		return a.abortTransfer(ctx, txn, persisted, limitExceededResponse(exceeded), err)
+	}
 	if err != nil {
-		return account.ApiResponse{Message: "Destination account not found"}, ErrAccountNotFound
+		return account.TransferResponse{Message: "Transaction failed during database update"}, err
 	}
 
-	sourceAccount.Balance -= amount
-	destAccount.Balance += amount
-	err = a.repo.UpdateAccountsInTx(ctx, sourceAccount, destAccount)
+	return account.TransferResponse{Message: "Transaction completed successfully", Transfer: txn.ToResponse()}, nil
+}
+
+func limitExceededResponse(exceeded *limit.ExceededError) account.TransferResponse {
+	return account.TransferResponse{
+		Message:   "Transfer limit exceeded",
+		ErrorCode: exceeded.Code,
+		Remaining: exceeded.Remaining,
+	}
+}
+
+// holdTransfer records transfers that watchlist screening, risk screening or the approval threshold stop
+// from executing right away. It reports whether the transfer was held.
+func (a *AccountServiceImpl) holdTransfer(ctx context.Context, txn *transfer.Model, source, dest *account.Model, now time.Time) (account.TransferResponse, bool, error) {
+	message := ""
+	if a.screener != nil {
+		// hits reference the transfer, so it gets its id before it is recorded
+		if txn.ID == uuid.Nil {
+			txn.ID = uuid.New()
+		}
+		matches, err := a.screener.Screen(ctx, txn.ID,
+			screening.Party{AccountId: source.AccountId, Name: source.HolderName},
+			screening.Party{AccountId: dest.AccountId, Name: dest.HolderName},
+		)
+		if err != nil {
+			return account.TransferResponse{Message: "Failed to screen transfer against the watchlist"}, false, err
+		}
+		switch screening.StrongestAction(matches) {
+		case screening.ActionBlock:
+			txn.Status, message = transfer.StatusBlocked, "Transfer blocked by watchlist screening"
+			txn.FailureReason = message
+		case screening.ActionFlag:
+			txn.Status, message = transfer.StatusPendingReview, "Transfer held for watchlist review"
+		}
+	}
+	if a.riskEvaluator != nil && txn.Status != transfer.StatusBlocked {
+		decision, err := a.screenTransfer(ctx, source, dest, txn)
+		if err != nil {
+			return account.TransferResponse{Message: "Failed to screen transfer"}, false, err
+		}
+		txn.RiskDecision = decision
+		switch {
+		case decision.Outcome == risk.OutcomeDeny:
+			txn.Status, message = transfer.StatusDenied, "Transfer denied by risk screening"
+		case decision.Outcome == risk.OutcomeReview && message == "":
+			txn.Status, message = transfer.StatusPendingReview, "Transfer held for manual review"
+		}
+	}
+	if message == "" && a.approvalThreshold > 0 && txn.Amount > a.approvalThreshold {
+		txn.Status, message = transfer.StatusPendingApproval, "Transfer pending approval"
+	}
+	if message == "" {
+		return account.TransferResponse{}, false, nil
+	}
+
+	if txn.IsPending() && a.approvalExpiry > 0 {
+		expiresAt := now.Add(a.approvalExpiry)
+		txn.ExpiresAt = &expiresAt
+	}
+	// the transfer is recorded with its status, but no money moves
+	if err := a.saveTransfer(ctx, txn); err != nil {
+		return account.TransferResponse{Message: "Transaction failed during database update"}, true, err
+	}
+	response := account.TransferResponse{Message: message, Transfer: txn.ToResponse()}
+	switch txn.Status {
+	case transfer.StatusDenied:
+		response.ErrorCode = transfer.CodeDenied
+		return response, true, ErrTransferDenied
+	case transfer.StatusBlocked:
+		response.ErrorCode = transfer.CodeBlocked
+		return response, true, ErrTransferBlocked
+	}
+	return response, true, nil
+}
+
+// abortTransfer marks a previously held transfer as failed when it can no longer execute, then returns response
+func (a *AccountServiceImpl) abortTransfer(ctx context.Context, txn *transfer.Model, persisted bool, response account.TransferResponse, err error) (account.TransferResponse, error) {
+	if !persisted {
+		return response, err
+	}
+
+	txn.Status = transfer.StatusFailed
+	txn.FailureReason = response.Message
+	txn.ExecutedAt = nil
+	if saveErr := a.saveTransfer(ctx, txn); saveErr != nil {
+		return response, saveErr
+	}
+	response.Transfer = txn.ToResponse()
+	return response, err
+}
+
+// saveTransfer records txn along with the updated accounts, then publishes the changes to the streams
+// of the accounts involved. It runs under the locks of the source and destination, so their streams see
+// changes in order; the revenue account, which is not locked, publishes its committed balance in turn.
+func (a *AccountServiceImpl) saveTransfer(ctx context.Context, txn *transfer.Model, accounts ...*account.Model) error {
+	if err := a.repo.UpdateAccountsInTx(ctx, txn, accounts...); err != nil {
+		return err
+	}
+	if a.notifier == nil {
+		return nil
+	}
+
+	// the change is committed, so a stream that misses it only degrades the dashboards
+	for _, acc := range accounts {
+		if acc.AccountId == txn.SourceAccountId || acc.AccountId == txn.DestinationAccountId {
+			a.notifyBalance(ctx, acc, txn)
+		} else {
+			a.notifyCommittedBalance(ctx, acc.AccountId, txn)
+		}
+	}
+	for _, accountId := range []string{txn.SourceAccountId, txn.DestinationAccountId} {
+		if err := a.notifier.Notify(ctx, accountId, stream.EventTransfer, txn.ToResponse()); err != nil {
+			logger.Errorf("Failed to publish transfer %s to account %s: %v", txn.ID, accountId, err)
+		}
+	}
+	return nil
+}
+
+// notifyBalance publishes the balance of acc, as changed by txn, to the stream of the account
+func (a *AccountServiceImpl) notifyBalance(ctx context.Context, acc *account.Model, txn *transfer.Model) {
+	balance := stream.BalanceChanged{
+		AccountId:        acc.AccountId,
+		Balance:          acc.Balance,
+		AvailableBalance: acc.AvailableBalance(),
+		TransferId:       txn.ID.String(),
+		ChangedAt:        time.Now().UTC(),
+	}
+	if err := a.notifier.Notify(ctx, acc.AccountId, stream.EventBalance, balance); err != nil {
+		logger.Errorf("Failed to publish balance of account %s: %v", acc.AccountId, err)
+	}
+}
+
+// notifyCommittedBalance publishes the balance of an account txn changed without locking it. Transfers committed
+// concurrently may publish in any order, so each reads the balance under the account lock, and the last
+// to publish sends the latest balance.
+func (a *AccountServiceImpl) notifyCommittedBalance(ctx context.Context, accountId string, txn *transfer.Model) {
+	release, err := a.lockAccounts(ctx, accountId)
 	if err != nil {
-		return account.ApiResponse{Message: "Transaction failed during database update"}, err
+		logger.Errorf("Failed to publish balance of account %s: %v", accountId, err)
+		return
 	}
+	defer release()
 
-	return account.ApiResponse{Message: "Transaction completed successfully"}, nil
+	acc, err := a.repo.GetAccount(db.WithPrimary(ctx), accountId)
+	if err != nil {
+		logger.Errorf("Failed to publish balance of account %s: %v", accountId, err)
+		return
+	}
+	a.notifyBalance(ctx, acc, txn)
+}
+
+// screenTransfer builds the risk context of txn and asks the risk evaluator for a decision
+func (a *AccountServiceImpl) screenTransfer(ctx context.Context, source, dest *account.Model, txn *transfer.Model) (*risk.Decision, error) {
+	recentCount, recentAmount, err := a.repo.GetOutgoingVelocity(ctx, source.AccountId, txn.CreatedAt.Add(-a.riskVelocityWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	return a.riskEvaluator.Evaluate(ctx, risk.TransferContext{
+		SourceAccountId:       source.AccountId,
+		DestinationAccountId:  dest.AccountId,
+		Amount:                txn.Amount,
+		Fee:                   txn.Fee,
+		SourceAccountAge:      accountAge(source, *txn.CreatedAt),
+		DestinationAccountAge: accountAge(dest, *txn.CreatedAt),
+		RecentCount:           recentCount,
+		RecentAmount:          recentAmount,
+	})
+}
+
+// accountAge returns how long acc has existed at the given time; accounts without a creation time count as brand new
+func accountAge(acc *account.Model, at time.Time) time.Duration {
+	if acc.CreatedAt == nil {
+		return 0
+	}
+	return at.Sub(*acc.CreatedAt)
+}
+
+func (a *AccountServiceImpl) GetTransferHistory(ctx context.Context, accountId string) ([]*transfer.Response, error) {
+	acc, err := a.repo.GetAccount(ctx, accountId)
+	if err != nil {
+		return nil, ErrAccountNotFound
+	}
+	if err := a.authorize(ctx, access.ActionRead, acc); err != nil {
+		return nil, err
+	}
+
+	transfers, err := a.repo.GetTransferHistory(ctx, accountId, TransferHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]*transfer.Response, 0, len(transfers))
+	for i := range transfers {
+		history = append(history, transfers[i].ToResponse())
+	}
+	return history, nil
 }
 
-func NewAccountService(repo account.Repository, cache cache.Cache) account.Service {
-	return &AccountServiceImpl{
-		repo:             repo,
-		cache:            cache,
-		lockPollInterval: 10 * time.Millisecond,
+func (a *AccountServiceImpl) UpdateOverdraftLimit(ctx context.Context, accountId string, req account.UpdateOverdraftLimitRequest) (*account.GetAccountResponse, error) {
+	if req.Limit < 0 {
+		return nil, ErrInvalidOverdraftLimit
+	}
+	operator := auth.SubjectFromContext(ctx)
+	if operator == "" {
+		return nil, ErrOperatorRequired
+	}
+
+	ctx = db.WithPrimary(ctx)
+	// limit changes take the account lock so they never interleave with a transfer's balance check
+	release, err := a.lockAccounts(ctx, accountId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	acc, err := a.repo.GetAccount(ctx, accountId)
+	if err != nil {
+		return nil, ErrAccountNotFound
+	}
+	if err := a.authorize(ctx, access.ActionManage, acc); err != nil {
+		return nil, err
+	}
+	if req.ExpectedVersion != nil && *req.ExpectedVersion != acc.OverdraftLimitVersion {
+		return nil, account.ErrVersionConflict
+	}
+	if acc.OverdraftUsed() > req.Limit {
+		return nil, ErrOverdraftLimitBelowUsage
+	}
+
+	change := &account.OverdraftLimitChange{
+		AccountId:     acc.AccountId,
+		Version:       acc.OverdraftLimitVersion + 1,
+		PreviousLimit: acc.OverdraftLimit,
+		NewLimit:      req.Limit,
+		ChangedBy:     operator,
+		Reason:        req.Reason,
+	}
+	if err := a.repo.UpdateOverdraftLimit(ctx, acc, change); err != nil {
+		return nil, err
+	}
+
+	acc.OverdraftLimit = change.NewLimit
+	acc.OverdraftLimitVersion = change.Version
+	return toGetAccountResponse(acc), nil
+}
+
+func (a *AccountServiceImpl) SetAccountFrozen(ctx context.Context, accountId string, req account.FreezeAccountRequest) (*account.GetAccountResponse, error) {
+	operator := auth.SubjectFromContext(ctx)
+	if operator == "" {
+		return nil, ErrOperatorRequired
+	}
+
+	ctx = db.WithPrimary(ctx)
+	// freezing takes the account lock so a transfer that already checked the account completes first
+	release, err := a.lockAccounts(ctx, accountId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	acc, err := a.repo.GetAccount(ctx, accountId)
+	if err != nil {
+		return nil, ErrAccountNotFound
+	}
+	if err := a.authorize(ctx, access.ActionManage, acc); err != nil {
+		return nil, err
+	}
+	if acc.Frozen == req.Frozen {
+		return toGetAccountResponse(acc), nil
+	}
+
+	acc.Frozen = req.Frozen
+	if err := a.repo.SetFrozen(ctx, acc, operator, req.Reason); err != nil {
+		return nil, err
+	}
+	return toGetAccountResponse(acc), nil
+}
+
+func (a *AccountServiceImpl) GetOverdraftLimitHistory(ctx context.Context, accountId string) ([]account.OverdraftLimitChange, error) {
+	acc, err := a.repo.GetAccount(ctx, accountId)
+	if err != nil {
+		return nil, ErrAccountNotFound
+	}
+	if err := a.authorize(ctx, access.ActionRead, acc); err != nil {
+		return nil, err
+	}
+	return a.repo.GetOverdraftLimitHistory(ctx, accountId)
+}
+
+func (a *AccountServiceImpl) GetRemainingLimits(ctx context.Context, accountId string) (*limit.Allowance, error) {
+	acc, err := a.repo.GetAccount(ctx, accountId)
+	if err != nil {
+		return nil, ErrAccountNotFound
+	}
+	if err := a.authorize(ctx, access.ActionRead, acc); err != nil {
+		return nil, err
+	}
+	if a.limitEngine == nil {
+		return &limit.Allowance{Tier: acc.Tier}, nil
+	}
+	return a.limitEngine.Remaining(ctx, acc.AccountId, acc.Tier, time.Now().UTC())
+}
+
+func NewAccountService(repo account.Repository, cache cache.Cache, opts ...Option) account.Service {
+	service := &AccountServiceImpl{
+		accountLocker: newAccountLocker(cache),
+		repo:          repo,
+	}
+	for _, opt := range opts {
+		opt(service)
 	}
+	return service
 }
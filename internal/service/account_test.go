@@ -4,146 +4,18 @@ import (
 	"context"
 	"errors"
 	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/infrastructure/cache"
+	"internal-transfer-microservice/internal/infrastructure/db"
+	"internal-transfer-microservice/internal/repository"
 	"sync"
 	"testing"
-	"time"
 )
 
-// MockRepository is a mock implementation of account.Repository
-type MockRepository struct {
-	accounts map[string]*account.Model
-	mu       sync.Mutex
-}
-
-func NewMockRepository() *MockRepository {
-	return &MockRepository{
-		accounts: make(map[string]*account.Model),
-	}
-}
-
-func (m *MockRepository) GetAccount(ctx context.Context, accountId string) (*account.Model, error) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	acc, exists := m.accounts[accountId]
-	if !exists {
-		return nil, errors.New("account not found")
-	}
-	return acc, nil
-}
-
-func (m *MockRepository) UpdateAccount(ctx context.Context, account *account.Model) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	_, exists := m.accounts[account.AccountId]
-	if !exists {
-		return errors.New("account not found")
-	}
-	m.accounts[account.AccountId] = account
-	return nil
-}
-
-func (m *MockRepository) CreateAccount(ctx context.Context, account *account.Model) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	_, exists := m.accounts[account.AccountId]
-	if exists {
-		return errors.New("account already exists")
-	}
-	m.accounts[account.AccountId] = account
-	return nil
-}
-
-func (m *MockRepository) UpdateAccountsInTx(ctx context.Context, srcAccount *account.Model, destAccount *account.Model) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	_, srcExists := m.accounts[srcAccount.AccountId]
-	if !srcExists {
-		return errors.New("source account not found")
-	}
-
-	_, destExists := m.accounts[destAccount.AccountId]
-	if !destExists {
-		return errors.New("destination account not found")
-	}
-
-	m.accounts[srcAccount.AccountId] = srcAccount
-	m.accounts[destAccount.AccountId] = destAccount
-	return nil
-}
-
-// MockCache is a mock implementation of cache.Cache
-type MockCache struct {
-	locks map[string]bool
-	data  map[string]string
-	mu    sync.Mutex
-}
-
-func NewMockCache() *MockCache {
-	return &MockCache{
-		locks: make(map[string]bool),
-		data:  make(map[string]string),
-	}
-}
-
-func (m *MockCache) Get(ctx context.Context, key string) (string, error) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	value, exists := m.data[key]
-	if !exists {
-		return "", errors.New("key not found")
-	}
-	return value, nil
-}
-
-func (m *MockCache) Set(ctx context.Context, key string, value string, expiration time.Duration) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	m.data[key] = value
-	return nil
-}
-
-func (m *MockCache) Delete(ctx context.Context, key string) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	delete(m.data, key)
-	return nil
-}
-
-func (m *MockCache) Lock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	if m.locks[key] {
-		return false, errors.New("lock already acquired")
-	}
-	m.locks[key] = true
-	return true, nil
-}
-
-func (m *MockCache) Release(ctx context.Context, key string) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	delete(m.locks, key)
-	return nil
-}
-
-func (m *MockCache) Close() error {
-	return nil
-}
-
 // Test cases
 func TestCreateAccount(t *testing.T) {
 	// Setup
-	repo := NewMockRepository()
-	cache := NewMockCache()
+	repo := repository.NewMemoryAccountRepo()
+	cache := cache.NewMemoryCache()
 	service := NewAccountService(repo, cache)
 	ctx := context.Background()
 
@@ -151,7 +23,10 @@ func TestCreateAccount(t *testing.T) {
 	accountId := "acc123"
 	balance := 1000.0
 
-	response, err := service.CreateAccount(ctx, accountId, balance)
+	response, err := service.CreateAccount(ctx, account.CreateAccountRequest{
+		AccountId:      accountId,
+		InitialBalance: balance,
+	})
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -174,8 +49,8 @@ func TestCreateAccount(t *testing.T) {
 
 func TestGetExistingAccount(t *testing.T) {
 	// Setup
-	repo := NewMockRepository()
-	cache := NewMockCache()
+	repo := repository.NewMemoryAccountRepo()
+	cache := cache.NewMemoryCache()
 	service := NewAccountService(repo, cache)
 	ctx := context.Background()
 
@@ -202,8 +77,8 @@ func TestGetExistingAccount(t *testing.T) {
 
 func TestGetNonExistentAccount(t *testing.T) {
 	// Setup
-	repo := NewMockRepository()
-	cache := NewMockCache()
+	repo := repository.NewMemoryAccountRepo()
+	cache := cache.NewMemoryCache()
 	service := NewAccountService(repo, cache)
 	ctx := context.Background()
 
@@ -217,8 +92,8 @@ func TestGetNonExistentAccount(t *testing.T) {
 
 func TestSimpleTransfer(t *testing.T) {
 	// Setup
-	repo := NewMockRepository()
-	cache := NewMockCache()
+	repo := repository.NewMemoryAccountRepo()
+	cache := cache.NewMemoryCache()
 	service := NewAccountService(repo, cache)
 	ctx := context.Background()
 
@@ -262,10 +137,44 @@ func TestSimpleTransfer(t *testing.T) {
 	}
 }
 
+func TestInvalidTransfers(t *testing.T) {
+	// Setup
+	repo := repository.NewMemoryAccountRepo()
+	service := NewAccountService(repo, cache.NewMemoryCache())
+	ctx := context.Background()
+
+	repo.CreateAccount(ctx, &account.Model{AccountId: "a", Balance: 100.0})
+	repo.CreateAccount(ctx, &account.Model{AccountId: "b", Balance: 100.0})
+
+	// Test case: transfers within one account, without an account or moving no money are refused
+	invalid := []struct {
+		source, dest string
+		amount       float64
+		expected     error
+	}{
+		{"a", "a", 50.0, ErrSameAccountTransfer},
+		{"a", "", 50.0, ErrTransferAccountsRequired},
+		{"a", "b", -30.0, ErrInvalidAmount},
+		{"a", "b", 0, ErrInvalidAmount},
+	}
+	for _, tc := range invalid {
+		if _, err := service.TxnAccount(ctx, tc.source, tc.dest, tc.amount); !errors.Is(err, tc.expected) {
+			t.Errorf("Expected %v for %s -> %s of %.2f, got %v", tc.expected, tc.source, tc.dest, tc.amount, err)
+		}
+	}
+
+	// Verify no money moved
+	a, _ := repo.GetAccount(ctx, "a")
+	b, _ := repo.GetAccount(ctx, "b")
+	if a.Balance != 100.0 || b.Balance != 100.0 {
+		t.Errorf("Expected balances 100 and 100, got %.2f and %.2f", a.Balance, b.Balance)
+	}
+}
+
 func TestConcurrentTransfersOnDifferentAccounts(t *testing.T) {
 	// Setup
-	repo := NewMockRepository()
-	cache := NewMockCache()
+	repo := repository.NewMemoryAccountRepo()
+	cache := cache.NewMemoryCache()
 	service := NewAccountService(repo, cache)
 	ctx := context.Background()
 
@@ -324,8 +233,8 @@ func TestConcurrentTransfersOnDifferentAccounts(t *testing.T) {
 
 func TestDeadlockPrevention(t *testing.T) {
 	// Setup
-	repo := NewMockRepository()
-	cache := NewMockCache()
+	repo := repository.NewMemoryAccountRepo()
+	cache := cache.NewMemoryCache()
 	service := NewAccountService(repo, cache)
 	ctx := context.Background()
 
@@ -372,3 +281,158 @@ func TestDeadlockPrevention(t *testing.T) {
 		t.Errorf("Expected accB balance 900.0, got %.2f", accBFinal.Balance)
 	}
 }
+
+func TestTransferIntoOverdraft(t *testing.T) {
+	// Setup
+	repo := repository.NewMemoryAccountRepo()
+	cache := cache.NewMemoryCache()
+	service := NewAccountService(repo, cache)
+	ctx := context.Background()
+
+	repo.CreateAccount(ctx, &account.Model{AccountId: "src", Balance: 100.0})
+	repo.CreateAccount(ctx, &account.Model{AccountId: "dst", Balance: 0})
+
+	// Test case: no transfer beyond the balance without an overdraft limit
+	response, _ := service.TxnAccount(ctx, "src", "dst", 150.0)
+	if response.Message != "Insufficient balance" {
+		t.Errorf("Expected insufficient balance, got %s", response.Message)
+	}
+
+	// Test case: the balance may go negative down to the limit
+	if _, err := service.UpdateOverdraftLimit(operatorContext("ops"), "src", account.UpdateOverdraftLimitRequest{Limit: 100.0}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	response, err := service.TxnAccount(ctx, "src", "dst", 150.0)
+	if err != nil || response.Message != "Transaction completed successfully" {
+		t.Fatalf("Expected transfer into overdraft to succeed, got %s (%v)", response.Message, err)
+	}
+
+	acc, err := service.GetAccount(ctx, "src")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if acc.Balance != -50.0 || acc.OverdraftUsed != 50.0 || acc.AvailableBalance != 50.0 {
+		t.Errorf("Expected balance -50, overdraft used 50 and available 50, got %+v", acc)
+	}
+
+	// Test case: the limit cannot be lowered below the overdraft in use
+	_, err = service.UpdateOverdraftLimit(operatorContext("ops"), "src", account.UpdateOverdraftLimitRequest{Limit: 10.0})
+	if !errors.Is(err, ErrOverdraftLimitBelowUsage) {
+		t.Errorf("Expected ErrOverdraftLimitBelowUsage, got %v", err)
+	}
+}
+
+func TestOverdraftLimitVersioning(t *testing.T) {
+	// Setup
+	repo := repository.NewMemoryAccountRepo()
+	cache := cache.NewMemoryCache()
+	service := NewAccountService(repo, cache)
+	ctx := context.Background()
+
+	repo.CreateAccount(ctx, &account.Model{AccountId: "acc", Balance: 0})
+
+	// Test case: changes are made on behalf of an operator, who is recorded as their author
+	if _, err := service.UpdateOverdraftLimit(ctx, "acc", account.UpdateOverdraftLimitRequest{Limit: 100.0}); !errors.Is(err, ErrOperatorRequired) {
+		t.Errorf("Expected ErrOperatorRequired, got %v", err)
+	}
+	service.UpdateOverdraftLimit(operatorContext("alice"), "acc", account.UpdateOverdraftLimitRequest{Limit: 100.0})
+	service.UpdateOverdraftLimit(operatorContext("bob"), "acc", account.UpdateOverdraftLimitRequest{Limit: 200.0})
+
+	// Test case: a change based on a stale version is rejected
+	stale := 1
+	_, err := service.UpdateOverdraftLimit(operatorContext("carol"), "acc", account.UpdateOverdraftLimitRequest{Limit: 300.0, ExpectedVersion: &stale})
+	if !errors.Is(err, account.ErrVersionConflict) {
+		t.Errorf("Expected ErrVersionConflict, got %v", err)
+	}
+
+	// Test case: every accepted change is audited, newest first
+	history, err := service.GetOverdraftLimitHistory(ctx, "acc")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("Expected 2 audit records, got %d", len(history))
+	}
+	if history[0].Version != 2 || history[0].PreviousLimit != 100.0 || history[0].NewLimit != 200.0 || history[0].ChangedBy != "bob" {
+		t.Errorf("Unexpected latest audit record %+v", history[0])
+	}
+}
+
+func TestFreezeAccount(t *testing.T) {
+	// Setup
+	repo := repository.NewMemoryAccountRepo()
+	service := NewAccountService(repo, cache.NewMemoryCache())
+	ctx := operatorContext("oncall")
+
+	repo.CreateAccount(ctx, &account.Model{AccountId: "src", Balance: 100.0})
+	repo.CreateAccount(ctx, &account.Model{AccountId: "dst", Balance: 0})
+
+	// Test case: freezing needs an operator
+	if _, err := service.SetAccountFrozen(context.Background(), "src", account.FreezeAccountRequest{Frozen: true}); !errors.Is(err, ErrOperatorRequired) {
+		t.Errorf("Expected ErrOperatorRequired, got %v", err)
+	}
+	if _, err := service.SetAccountFrozen(ctx, "missing", account.FreezeAccountRequest{Frozen: true}); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("Expected ErrAccountNotFound, got %v", err)
+	}
+
+	// Test case: a frozen account refuses transfers until it is unfrozen
+	response, err := service.SetAccountFrozen(ctx, "src", account.FreezeAccountRequest{Frozen: true, Reason: "fraud report"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !response.Frozen {
+		t.Errorf("Expected the account to be frozen")
+	}
+	if _, err := service.TxnAccount(ctx, "src", "dst", 10.0); !errors.Is(err, account.ErrAccountFrozen) {
+		t.Errorf("Expected ErrAccountFrozen, got %v", err)
+	}
+
+	if _, err := service.SetAccountFrozen(ctx, "src", account.FreezeAccountRequest{Frozen: false}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := service.TxnAccount(ctx, "src", "dst", 10.0); err != nil {
+		t.Errorf("Expected the transfer to succeed, got %v", err)
+	}
+}
+
+// replicaRecordingRepository records whether each account lookup would be served by the primary
+type replicaRecordingRepository struct {
+	*repository.MemoryAccountRepoImpl
+	primaryReads int
+	replicaReads int
+}
+
+func (r *replicaRecordingRepository) GetAccount(ctx context.Context, accountId string) (*account.Model, error) {
+	if db.UsesPrimary(ctx) {
+		r.primaryReads++
+	} else {
+		r.replicaReads++
+	}
+	return r.MemoryAccountRepoImpl.GetAccount(ctx, accountId)
+}
+
+func TestTransferReadsFromPrimary(t *testing.T) {
+	// Setup
+	repo := &replicaRecordingRepository{MemoryAccountRepoImpl: repository.NewMemoryAccountRepo()}
+	service := NewAccountService(repo, cache.NewMemoryCache())
+	ctx := context.Background()
+
+	repo.CreateAccount(ctx, &account.Model{AccountId: "src", Balance: 100})
+	repo.CreateAccount(ctx, &account.Model{AccountId: "dst", Balance: 0})
+
+	// Test case: account lookups may be served by a replica
+	if _, err := service.GetAccount(ctx, "src"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if repo.replicaReads != 1 || repo.primaryReads != 0 {
+		t.Errorf("Expected 1 replica read, got %d replica and %d primary reads", repo.replicaReads, repo.primaryReads)
+	}
+
+	// Test case: the balances a transfer checks and writes back are read from the primary
+	if _, err := service.TxnAccount(ctx, "src", "dst", 40); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if repo.replicaReads != 1 || repo.primaryReads == 0 {
+		t.Errorf("Expected the transfer to read from the primary only, got %d replica and %d primary reads", repo.replicaReads-1, repo.primaryReads)
+	}
+}
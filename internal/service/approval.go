@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"internal-transfer-microservice/internal/auth"
+	"internal-transfer-microservice/internal/domain/access"
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/transfer"
+	"internal-transfer-microservice/internal/infrastructure/db"
+)
+
+// PendingTransfersLimit caps the number of transfers returned by ListPendingTransfers
+const PendingTransfersLimit = 100
+
+var (
+	ErrTransferNotFound   = errors.New("transfer not found")
+	ErrTransferNotPending = errors.New("transfer is not waiting for a decision")
+	ErrTransferExpired    = errors.New("transfer expired before a decision was made")
+	ErrOperatorRequired   = errors.New("operator identity is required")
+	ErrSelfApproval       = errors.New("transfer cannot be approved by its initiator")
+)
+
+func (a *AccountServiceImpl) ListPendingTransfers(ctx context.Context) ([]*transfer.Response, error) {
+	if err := a.authorize(ctx, access.ActionReview, nil); err != nil {
+		return nil, err
+	}
+	if _, err := a.ExpirePendingTransfers(ctx); err != nil {
+		return nil, err
+	}
+
+	transfers, err := a.repo.ListTransfersByStatus(ctx, transfer.PendingStatuses, PendingTransfersLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make([]*transfer.Response, 0, len(transfers))
+	for i := range transfers {
+		pending = append(pending, transfers[i].ToResponse())
+	}
+	return pending, nil
+}
+
+func (a *AccountServiceImpl) ApproveTransfer(ctx context.Context, transferId string) (account.TransferResponse, error) {
+	approver := auth.SubjectFromContext(ctx)
+	if approver == "" {
+		return account.TransferResponse{Message: "Operator identity is required"}, ErrOperatorRequired
+	}
+	if err := a.authorize(ctx, access.ActionReview, nil); err != nil {
+		return account.TransferResponse{Message: "Not allowed to review transfers"}, err
+	}
+
+	ctx = db.WithPrimary(ctx)
+	txn, release, err := a.lockPendingTransfer(ctx, transferId)
+	if err != nil {
+		return account.TransferResponse{Message: "Transfer cannot be approved"}, err
+	}
+	defer release()
+
+	if txn.InitiatedBy == approver {
+		return account.TransferResponse{Message: "Transfer cannot be approved by its initiator"}, ErrSelfApproval
+	}
+
+	// approval hands the transfer back to the normal execution path
+	now := time.Now().UTC()
+	txn.ReviewedBy = approver
+	txn.ReviewedAt = &now
+	return a.executeTransfer(ctx, txn)
+}
+
+func (a *AccountServiceImpl) RejectTransfer(ctx context.Context, transferId string, reason string) (*transfer.Response, error) {
+	reviewer := auth.SubjectFromContext(ctx)
+	if reviewer == "" {
+		return nil, ErrOperatorRequired
+	}
+	if err := a.authorize(ctx, access.ActionReview, nil); err != nil {
+		return nil, err
+	}
+
+	ctx = db.WithPrimary(ctx)
+	txn, release, err := a.lockPendingTransfer(ctx, transferId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	now := time.Now().UTC()
+	txn.Status = transfer.StatusRejected
+	txn.ReviewedBy = reviewer
+	txn.ReviewedAt = &now
+	txn.FailureReason = reason
+	if err := a.saveTransfer(ctx, txn); err != nil {
+		return nil, err
+	}
+	return txn.ToResponse(), nil
+}
+
+func (a *AccountServiceImpl) ExpirePendingTransfers(ctx context.Context) (int64, error) {
+	return a.repo.ExpireTransfers(ctx, transfer.PendingStatuses, time.Now().UTC())
+}
+
+// lockPendingTransfer locks the accounts of a pending transfer and reloads it under the locks,
+// so concurrent decisions on the same transfer are serialised and only the first one applies
+func (a *AccountServiceImpl) lockPendingTransfer(ctx context.Context, transferId string) (*transfer.Model, func(), error) {
+	id, err := uuid.Parse(transferId)
+	if err != nil {
+		return nil, nil, ErrTransferNotFound
+	}
+	txn, err := a.repo.GetTransfer(ctx, id)
+	if err != nil {
+		return nil, nil, ErrTransferNotFound
+	}
+
+	release, err := a.lockTransferAccounts(ctx, txn.SourceAccountId, txn.DestinationAccountId)
+	if err != nil {
+		return nil, nil, err
+	}
+	txn, err = a.repo.GetTransfer(ctx, id)
+	if err != nil {
+		release()
+		return nil, nil, ErrTransferNotFound
+	}
+	if !txn.IsPending() {
+		release()
+		return nil, nil, ErrTransferNotPending
+	}
+	if txn.ExpiresAt != nil && txn.ExpiresAt.Before(time.Now().UTC()) {
+		txn.Status = transfer.StatusExpired
+		err = a.saveTransfer(ctx, txn)
+		release()
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, ErrTransferExpired
+	}
+	return txn, release, nil
+}
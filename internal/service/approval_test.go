@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"internal-transfer-microservice/internal/auth"
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/transfer"
+	"internal-transfer-microservice/internal/infrastructure/cache"
+	"internal-transfer-microservice/internal/repository"
+	"testing"
+	"time"
+)
+
+func operatorContext(subject string) context.Context {
+	return auth.WithPrincipal(context.Background(), auth.Principal{Subject: subject})
+}
+
+func TestTransferApproval(t *testing.T) {
+	// Setup
+	repo := repository.NewMemoryAccountRepo()
+	service := NewAccountService(repo, cache.NewMemoryCache(), WithApprovalPolicy(1000, time.Hour))
+
+	repo.CreateAccount(context.Background(), &account.Model{AccountId: "source", Balance: 5000.0})
+	repo.CreateAccount(context.Background(), &account.Model{AccountId: "dest", Balance: 0})
+
+	// Transfers at or below the threshold execute straight away
+	response, err := service.TxnAccount(operatorContext("maker"), "source", "dest", 1000.0)
+	if err != nil || response.Transfer.Status != transfer.StatusCompleted {
+		t.Fatalf("Expected completed transfer, got %+v (%v)", response.Transfer, err)
+	}
+
+	// Transfers above the threshold wait for a second operator
+	response, err = service.TxnAccount(operatorContext("maker"), "source", "dest", 2500.0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Transfer.Status != transfer.StatusPendingApproval {
+		t.Fatalf("Expected status %s, got %s", transfer.StatusPendingApproval, response.Transfer.Status)
+	}
+	if response.Transfer.ExpiresAt == nil {
+		t.Errorf("Expected pending transfer to have an expiry")
+	}
+	source, _ := repo.GetAccount(context.Background(), "source")
+	if source.Balance != 4000.0 {
+		t.Errorf("Expected pending transfer to leave balance at 4000, got %f", source.Balance)
+	}
+
+	pending, err := service.ListPendingTransfers(context.Background())
+	if err != nil || len(pending) != 1 {
+		t.Fatalf("Expected 1 pending transfer, got %d (%v)", len(pending), err)
+	}
+	transferId := pending[0].TransferId
+
+	// Approval needs an operator other than the initiator
+	if _, err := service.ApproveTransfer(context.Background(), transferId); !errors.Is(err, ErrOperatorRequired) {
+		t.Errorf("Expected ErrOperatorRequired, got %v", err)
+	}
+	if _, err := service.ApproveTransfer(operatorContext("maker"), transferId); !errors.Is(err, ErrSelfApproval) {
+		t.Errorf("Expected ErrSelfApproval, got %v", err)
+	}
+
+	response, err = service.ApproveTransfer(operatorContext("checker"), transferId)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Transfer.Status != transfer.StatusCompleted || response.Transfer.ReviewedBy != "checker" {
+		t.Errorf("Expected transfer completed by checker, got %s by %s", response.Transfer.Status, response.Transfer.ReviewedBy)
+	}
+	source, _ = repo.GetAccount(context.Background(), "source")
+	dest, _ := repo.GetAccount(context.Background(), "dest")
+	if source.Balance != 1500.0 || dest.Balance != 3500.0 {
+		t.Errorf("Expected balances 1500 and 3500, got %f and %f", source.Balance, dest.Balance)
+	}
+
+	// A decided transfer cannot be decided again
+	if _, err := service.ApproveTransfer(operatorContext("checker"), transferId); !errors.Is(err, ErrTransferNotPending) {
+		t.Errorf("Expected ErrTransferNotPending, got %v", err)
+	}
+}
+
+func TestTransferRejectionAndExpiry(t *testing.T) {
+	// Setup
+	repo := repository.NewMemoryAccountRepo()
+	service := NewAccountService(repo, cache.NewMemoryCache(), WithApprovalPolicy(100, time.Hour))
+
+	repo.CreateAccount(context.Background(), &account.Model{AccountId: "source", Balance: 1000.0})
+	repo.CreateAccount(context.Background(), &account.Model{AccountId: "dest", Balance: 0})
+
+	rejected, _ := service.TxnAccount(operatorContext("maker"), "source", "dest", 500.0)
+	expiring, _ := service.TxnAccount(operatorContext("maker"), "source", "dest", 400.0)
+
+	response, err := service.RejectTransfer(operatorContext("checker"), rejected.Transfer.TransferId, "unexpected beneficiary")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Status != transfer.StatusRejected || response.FailureReason != "unexpected beneficiary" {
+		t.Errorf("Expected rejected transfer with reason, got %s (%s)", response.Status, response.FailureReason)
+	}
+
+	// Move the remaining transfer past its expiry
+	txn, _ := repo.GetTransfer(context.Background(), uuid.MustParse(expiring.Transfer.TransferId))
+	past := time.Now().Add(-time.Minute)
+	txn.ExpiresAt = &past
+	repo.UpdateAccountsInTx(context.Background(), txn)
+
+	if _, err := service.ApproveTransfer(operatorContext("checker"), txn.ID.String()); !errors.Is(err, ErrTransferExpired) {
+		t.Errorf("Expected ErrTransferExpired, got %v", err)
+	}
+	txn, _ = repo.GetTransfer(context.Background(), uuid.MustParse(expiring.Transfer.TransferId))
+	if txn.Status != transfer.StatusExpired {
+		t.Errorf("Expected status %s, got %s", transfer.StatusExpired, txn.Status)
+	}
+
+	source, _ := repo.GetAccount(context.Background(), "source")
+	if source.Balance != 1000.0 {
+		t.Errorf("Expected balance to remain 1000, got %f", source.Balance)
+	}
+}
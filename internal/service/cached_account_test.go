@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/infrastructure/cache"
+	"internal-transfer-microservice/internal/repository"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// MockAccountStore counts the account reads of an in-memory account repository
+type MockAccountStore struct {
+	*repository.MemoryAccountRepoImpl
+	reads atomic.Int64
+	// afterRead runs after every read with its number, before the account is returned
+	afterRead func(read int64)
+}
+
+func (m *MockAccountStore) GetAccount(ctx context.Context, accountId string) (*account.Model, error) {
+	read := m.reads.Add(1)
+	acc, err := m.MemoryAccountRepoImpl.GetAccount(ctx, accountId)
+	if m.afterRead != nil {
+		m.afterRead(read)
+	}
+	return acc, err
+}
+
+func newTestCachedAccountRepo(balances map[string]float64) (*repository.CachedAccountRepoImpl, *MockAccountStore) {
+	store := &MockAccountStore{MemoryAccountRepoImpl: repository.NewMemoryAccountRepo()}
+	for accountId, balance := range balances {
+		store.CreateAccount(context.Background(), &account.Model{AccountId: accountId, Balance: balance})
+	}
+	return repository.NewCachedAccountRepo(store, cache.NewMemoryCache(), time.Minute, time.Minute), store
+}
+
+func TestCachedAccountLookups(t *testing.T) {
+	// Setup
+	repo, store := newTestCachedAccountRepo(map[string]float64{"acc-1": 100})
+	stats := cache.NewStats(repository.AccountCacheStatsName).Snapshot()
+	ctx := context.Background()
+
+	// The second lookup is served from the cache, with a copy the caller may change
+	first, err := repo.GetAccount(ctx, "acc-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	first.Balance = 0
+	second, err := repo.GetAccount(ctx, "acc-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if second.Balance != 100 {
+		t.Errorf("Expected cached balance 100, got %.2f", second.Balance)
+	}
+	if reads := store.reads.Load(); reads != 1 {
+		t.Errorf("Expected 1 database read, got %d", reads)
+	}
+
+	// A balance write invalidates the cached account
+	second.Debit(40)
+	if err := repo.UpdateAccountsInTx(ctx, nil, second); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	updated, _ := repo.GetAccount(ctx, "acc-1")
+	if updated.Balance != 60 {
+		t.Errorf("Expected balance 60 after the write, got %.2f", updated.Balance)
+	}
+
+	// Missing accounts are cached too, until the account is created
+	for i := 0; i < 2; i++ {
+		if _, err := repo.GetAccount(ctx, "acc-2"); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("Expected record not found, got %v", err)
+		}
+	}
+	if reads := store.reads.Load(); reads != 3 {
+		t.Errorf("Expected 3 database reads, got %d", reads)
+	}
+	repo.CreateAccount(ctx, &account.Model{AccountId: "acc-2", Balance: 5})
+	if created, err := repo.GetAccount(ctx, "acc-2"); err != nil || created.Balance != 5 {
+		t.Errorf("Expected the created account, got %v", err)
+	}
+
+	after := cache.NewStats(repository.AccountCacheStatsName).Snapshot()
+	if hits := after["hits"].(int64) - stats["hits"].(int64); hits != 1 {
+		t.Errorf("Expected 1 hit, got %d", hits)
+	}
+	if negativeHits := after["negative_hits"].(int64) - stats["negative_hits"].(int64); negativeHits != 1 {
+		t.Errorf("Expected 1 negative hit, got %d", negativeHits)
+	}
+	if misses := after["misses"].(int64) - stats["misses"].(int64); misses != 4 {
+		t.Errorf("Expected 4 misses, got %d", misses)
+	}
+}
+
+func TestCachedAccountStampede(t *testing.T) {
+	// Setup
+	repo, store := newTestCachedAccountRepo(map[string]float64{"acc-1": 100})
+	store.afterRead = func(int64) { time.Sleep(50 * time.Millisecond) }
+	ctx := context.Background()
+
+	// Concurrent misses share one database read
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if acc, err := repo.GetAccount(ctx, "acc-1"); err != nil || acc.Balance != 100 {
+				t.Errorf("Expected balance 100, got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if reads := store.reads.Load(); reads != 1 {
+		t.Errorf("Expected 1 database read, got %d", reads)
+	}
+}
+
+func TestCachedAccountReadRacingWrite(t *testing.T) {
+	// Setup
+	repo, store := newTestCachedAccountRepo(map[string]float64{"acc-1": 100})
+	readDone, resume := make(chan struct{}), make(chan struct{})
+	store.afterRead = func(read int64) {
+		if read == 1 {
+			close(readDone)
+			<-resume
+		}
+	}
+	ctx := context.Background()
+
+	// A lookup reads the old balance, then a transfer commits before it caches it
+	stale := make(chan *account.Model)
+	go func() {
+		acc, _ := repo.GetAccount(ctx, "acc-1")
+		stale <- acc
+	}()
+	<-readDone
+	written := &account.Model{AccountId: "acc-1"}
+	written.Debit(60)
+	if err := repo.UpdateAccountsInTx(ctx, nil, written); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Lookups after the write neither wait for nor share the read that started before it
+	fresh, err := repo.GetAccount(ctx, "acc-1")
+	if err != nil || fresh.Balance != 40 {
+		t.Errorf("Expected balance 40 after the write, got %v", err)
+	}
+	close(resume)
+	if acc := <-stale; acc.Balance != 100 {
+		t.Errorf("Expected the racing lookup to return balance 100, got %.2f", acc.Balance)
+	}
+
+	// The old balance it cached is never served
+	cached, err := repo.GetAccount(ctx, "acc-1")
+	if err != nil || cached.Balance != 40 {
+		t.Errorf("Expected balance 40, got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/eventstore"
+	"internal-transfer-microservice/internal/infrastructure/cache"
+	"internal-transfer-microservice/internal/repository"
+	"testing"
+	"time"
+)
+
+// appendChanges numbers changes after the last event of stream, as the event-sourced repository does
+func appendChanges(t *testing.T, stream []eventstore.Event, changes ...eventstore.Change) []eventstore.Event {
+	t.Helper()
+	for _, change := range changes {
+		event, err := eventstore.NewEvent("acc", change.Type, change.Data, nil, time.Now().UTC())
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		event.Version = len(stream) + 1
+		stream = append(stream, *event)
+	}
+	return stream
+}
+
+func TestAccountAggregateRehydration(t *testing.T) {
+	// Setup
+	id := uuid.New()
+	stream := appendChanges(t, nil,
+		eventstore.Change{Type: eventstore.TypeOpened, Data: eventstore.Opened{Id: id, AccountType: "standard", Tier: "standard", InitialBalance: 100}},
+		eventstore.Change{Type: eventstore.TypeCredited, Data: eventstore.Credited{Amount: 50}},
+		eventstore.Change{Type: eventstore.TypeOverdraftLimitChanged, Data: eventstore.OverdraftLimitChanged{Limit: 200, LimitVersion: 1}},
+		eventstore.Change{Type: eventstore.TypeDebited, Data: eventstore.Debited{Amount: 300}},
+		eventstore.Change{Type: eventstore.TypeFrozen, Data: eventstore.Frozen{Reason: "fraud review"}},
+	)
+
+	acc, err := eventstore.Rehydrate(nil, stream)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if acc.ID != id || acc.Balance != -150 || acc.OverdraftLimit != 200 || !acc.Frozen || acc.Version != 5 {
+		t.Errorf("Expected frozen account at -150 with limit 200 at version 5, got %+v", acc)
+	}
+
+	// A snapshot taken at version 3 plus the events after it gives the same state
+	atThree, _ := eventstore.Rehydrate(nil, stream[:3])
+	state, _ := json.Marshal(atThree)
+	fromSnapshot, err := eventstore.Rehydrate(&eventstore.Snapshot{AccountId: "acc", Version: 3, State: string(state)}, stream[3:])
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if fromSnapshot.Balance != acc.Balance || fromSnapshot.Frozen != acc.Frozen || fromSnapshot.Version != acc.Version {
+		t.Errorf("Expected the snapshot to rehydrate to %+v, got %+v", acc, fromSnapshot)
+	}
+
+	// Events must follow the version they are applied to
+	if _, err := eventstore.Rehydrate(nil, stream[1:]); err == nil {
+		t.Errorf("Expected an error for a stream with a gap")
+	}
+	if _, err := eventstore.Rehydrate(nil, nil); !errors.Is(err, eventstore.ErrStreamNotFound) {
+		t.Errorf("Expected ErrStreamNotFound, got %v", err)
+	}
+}
+
+func TestAccountChangesBecomeEvents(t *testing.T) {
+	current := &account.Model{AccountId: "acc", Balance: 100, AccountType: "standard", Tier: "standard"}
+	updated := *current
+	updated.Balance = 40
+	updated.Tier = "premium"
+	updated.Frozen = true
+
+	var types []string
+	for _, change := range eventstore.Changes(current, &updated) {
+		types = append(types, change.Type)
+	}
+	expected := []string{eventstore.TypeDetailsChanged, eventstore.TypeDebited, eventstore.TypeFrozen}
+	if len(types) != len(expected) {
+		t.Fatalf("Expected changes %v, got %v", expected, types)
+	}
+	for i := range expected {
+		if types[i] != expected[i] {
+			t.Errorf("Expected changes %v, got %v", expected, types)
+		}
+	}
+	if len(eventstore.Changes(current, current)) != 0 {
+		t.Errorf("Expected no changes for an unchanged account")
+	}
+}
+
+func TestTransferFromFrozenAccount(t *testing.T) {
+	// Setup
+	repo := repository.NewMemoryAccountRepo()
+	service := NewAccountService(repo, cache.NewMemoryCache())
+	ctx := context.Background()
+
+	repo.CreateAccount(ctx, &account.Model{AccountId: "frozen", Balance: 100.0, Frozen: true})
+	repo.CreateAccount(ctx, &account.Model{AccountId: "open", Balance: 100.0})
+
+	for _, accounts := range [][2]string{{"frozen", "open"}, {"open", "frozen"}} {
+		if _, err := service.TxnAccount(ctx, accounts[0], accounts[1], 10.0); !errors.Is(err, account.ErrAccountFrozen) {
+			t.Errorf("Expected ErrAccountFrozen for %s to %s, got %v", accounts[0], accounts[1], err)
+		}
+	}
+	open, _ := repo.GetAccount(ctx, "open")
+	if open.Balance != 100.0 {
+		t.Errorf("Expected no money to move, got balance %f", open.Balance)
+	}
+}
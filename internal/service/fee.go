@@ -0,0 +1,104 @@
+package service
+
+import (
+	"math"
+	"sort"
+
+	"internal-transfer-microservice/internal/config"
+	"internal-transfer-microservice/internal/domain/fee"
+	"internal-transfer-microservice/pkg/logger"
+)
+
+type FeeEngineImpl struct {
+	revenueAccountId string
+	schedules        map[string]config.FeeScheduleConfig
+}
+
+func (f *FeeEngineImpl) RevenueAccountId() string {
+	return f.revenueAccountId
+}
+
+func (f *FeeEngineImpl) Calculate(accountType string, amount float64) *fee.Breakdown {
+	schedule, ok := f.schedules[accountType]
+	if !ok {
+		// fall back to the schedule configured without an account type
+		schedule, ok = f.schedules[""]
+		if !ok {
+			return nil
+		}
+	}
+
+	breakdown := &fee.Breakdown{
+		AccountType: accountType,
+		Kind:        fee.KindFlat,
+		Flat:        schedule.Flat,
+		Rate:        schedule.Percentage,
+	}
+	if len(schedule.Tiers) > 0 {
+		tier, index := selectTier(schedule.Tiers, amount)
+		breakdown.Kind = fee.KindTiered
+		breakdown.Tier = index + 1
+		breakdown.Flat = tier.Flat
+		breakdown.Rate = tier.Percentage
+	} else if schedule.Percentage > 0 {
+		breakdown.Kind = fee.KindPercentage
+	}
+
+	breakdown.Variable = roundCents(amount * breakdown.Rate / 100)
+	total := breakdown.Flat + breakdown.Variable
+	if schedule.Min > 0 && total < schedule.Min {
+		total = schedule.Min
+		breakdown.CapApplied = fee.CapMin
+	}
+	if schedule.Max > 0 && total > schedule.Max {
+		total = schedule.Max
+		breakdown.CapApplied = fee.CapMax
+	}
+	breakdown.Total = roundCents(total)
+
+	if breakdown.Total <= 0 {
+		return nil
+	}
+	return breakdown
+}
+
+// selectTier returns the first tier whose upper bound covers amount, tiers being ordered by bound
+func selectTier(tiers []config.FeeTierConfig, amount float64) (config.FeeTierConfig, int) {
+	for i, tier := range tiers {
+		if tier.UpTo == 0 || amount <= tier.UpTo {
+			return tier, i
+		}
+	}
+	return tiers[len(tiers)-1], len(tiers) - 1
+}
+
+func roundCents(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+func NewFeeEngine(cfg *config.Config) fee.Engine {
+	engine := &FeeEngineImpl{
+		revenueAccountId: cfg.GetFeeRevenueAccountId(),
+		schedules:        make(map[string]config.FeeScheduleConfig),
+	}
+	if engine.revenueAccountId == "" {
+		if len(cfg.GetFeeSchedules()) > 0 {
+			logger.Warn("Fee schedules configured without a revenue account, transfer fees are disabled")
+		}
+		return engine
+	}
+
+	for _, schedule := range cfg.GetFeeSchedules() {
+		tiers := append([]config.FeeTierConfig(nil), schedule.Tiers...)
+		sort.SliceStable(tiers, func(i, j int) bool {
+			// the open-ended tier (UpTo == 0) always sorts last
+			if tiers[i].UpTo == 0 || tiers[j].UpTo == 0 {
+				return tiers[j].UpTo == 0 && tiers[i].UpTo != 0
+			}
+			return tiers[i].UpTo < tiers[j].UpTo
+		})
+		schedule.Tiers = tiers
+		engine.schedules[schedule.AccountType] = schedule
+	}
+	return engine
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"internal-transfer-microservice/internal/config"
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/fee"
+	"internal-transfer-microservice/internal/domain/stream"
+	"internal-transfer-microservice/internal/infrastructure/broker"
+	"internal-transfer-microservice/internal/infrastructure/cache"
+	"internal-transfer-microservice/internal/repository"
+	"sync"
+	"testing"
+)
+
+func newTestFeeEngine() fee.Engine {
+	cfg := &config.Config{
+		Fees: config.FeeConfig{
+			RevenueAccountId: "revenue",
+			Schedules: []config.FeeScheduleConfig{
+				{AccountType: "", Flat: 1.0},
+				{AccountType: "premium", Percentage: 1.0, Min: 2.0, Max: 10.0},
+				{AccountType: "business", Tiers: []config.FeeTierConfig{
+					{UpTo: 0, Percentage: 0.5},
+					{UpTo: 100, Flat: 0.5},
+					{UpTo: 1000, Flat: 1.0, Percentage: 0.1},
+				}},
+			},
+		},
+	}
+	return NewFeeEngine(cfg)
+}
+
+func TestFeeCalculation(t *testing.T) {
+	engine := newTestFeeEngine()
+
+	tests := []struct {
+		name        string
+		accountType string
+		amount      float64
+		kind        string
+		tier        int
+		capApplied  string
+		total       float64
+	}{
+		{"fallback flat", "standard", 500, fee.KindFlat, 0, "", 1.0},
+		{"percentage within caps", "premium", 500, fee.KindPercentage, 0, "", 5.0},
+		{"percentage min cap", "premium", 50, fee.KindPercentage, 0, fee.CapMin, 2.0},
+		{"percentage max cap", "premium", 5000, fee.KindPercentage, 0, fee.CapMax, 10.0},
+		{"lowest tier", "business", 80, fee.KindTiered, 1, "", 0.5},
+		{"middle tier", "business", 500, fee.KindTiered, 2, "", 1.5},
+		{"open-ended tier", "business", 10000, fee.KindTiered, 3, "", 50.0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			breakdown := engine.Calculate(tc.accountType, tc.amount)
+			if breakdown == nil {
+				t.Fatalf("Expected a fee breakdown, got nil")
+			}
+			if breakdown.Kind != tc.kind {
+				t.Errorf("Expected kind %s, got %s", tc.kind, breakdown.Kind)
+			}
+			if breakdown.Tier != tc.tier {
+				t.Errorf("Expected tier %d, got %d", tc.tier, breakdown.Tier)
+			}
+			if breakdown.CapApplied != tc.capApplied {
+				t.Errorf("Expected cap %q, got %q", tc.capApplied, breakdown.CapApplied)
+			}
+			if breakdown.Total != tc.total {
+				t.Errorf("Expected total %.2f, got %.2f", tc.total, breakdown.Total)
+			}
+		})
+	}
+}
+
+func TestFeeEngineWithoutRevenueAccount(t *testing.T) {
+	engine := NewFeeEngine(&config.Config{
+		Fees: config.FeeConfig{
+			Schedules: []config.FeeScheduleConfig{{Flat: 1.0}},
+		},
+	})
+
+	if breakdown := engine.Calculate("standard", 100); breakdown != nil {
+		t.Errorf("Expected no fee without a revenue account, got %.2f", breakdown.Total)
+	}
+}
+
+func TestTransferWithFee(t *testing.T) {
+	// Setup
+	repo := repository.NewMemoryAccountRepo()
+	cache := cache.NewMemoryCache()
+	service := NewAccountService(repo, cache, WithFeeEngine(newTestFeeEngine()))
+	ctx := context.Background()
+
+	repo.CreateAccount(ctx, &account.Model{AccountId: "src", Balance: 1000.0, AccountType: "premium"})
+	repo.CreateAccount(ctx, &account.Model{AccountId: "dst", Balance: 0})
+	repo.CreateAccount(ctx, &account.Model{AccountId: "revenue", Balance: 0})
+
+	// Test case: 1% fee is charged on top of the amount and posted to the revenue account
+	response, err := service.TxnAccount(ctx, "src", "dst", 500.0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Transfer == nil || response.Transfer.Fee != 5.0 {
+		t.Fatalf("Expected fee 5.00 in the transfer response, got %+v", response.Transfer)
+	}
+
+	src, _ := repo.GetAccount(ctx, "src")
+	dst, _ := repo.GetAccount(ctx, "dst")
+	revenue, _ := repo.GetAccount(ctx, "revenue")
+	if src.Balance != 495.0 {
+		t.Errorf("Expected source balance 495.00, got %.2f", src.Balance)
+	}
+	if dst.Balance != 500.0 {
+		t.Errorf("Expected destination balance 500.00, got %.2f", dst.Balance)
+	}
+	if revenue.Balance != 5.0 {
+		t.Errorf("Expected revenue balance 5.00, got %.2f", revenue.Balance)
+	}
+
+	// The fee breakdown is kept in the transfer history
+	history, err := service.GetTransferHistory(ctx, "src")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(history) != 1 || history[0].FeeBreakdown == nil || history[0].FeeBreakdown.Total != 5.0 {
+		t.Errorf("Expected one transfer with a 5.00 fee breakdown, got %+v", history)
+	}
+}
+
+func TestConcurrentTransfersWithFee(t *testing.T) {
+	testConcurrentTransfersWithFee(t, repository.NewMemoryAccountRepo())
+}
+
+func TestConcurrentTransfersWithFeeEventSourced(t *testing.T) {
+	database, migrator := newTestSQLiteDB(t)
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	testConcurrentTransfersWithFee(t, repository.NewEventSourcedAccountRepo(database, 10))
+}
+
+// testConcurrentTransfersWithFee charges fees into the revenue account of repo from concurrent transfers
+func testConcurrentTransfersWithFee(t *testing.T, repo account.Repository) {
+	// Setup
+	cache := cache.NewMemoryCache()
+	streams := NewStreamService(broker.NewMemoryBroker(100), repo, nil)
+	service := NewAccountService(repo, cache, WithFeeEngine(newTestFeeEngine()), WithNotifier(streams))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	repo.CreateAccount(ctx, &account.Model{AccountId: "revenue", Balance: 0})
+	for i := 0; i < 10; i++ {
+		repo.CreateAccount(ctx, &account.Model{AccountId: fmt.Sprintf("src-%d", i), Balance: 100.0})
+		repo.CreateAccount(ctx, &account.Model{AccountId: fmt.Sprintf("dst-%d", i), Balance: 0})
+	}
+
+	// Test case: transfers do not wait for the revenue account to be unlocked
+	revenueLock := fmt.Sprintf(UpdateAccountResourceLockKey, "revenue")
+	if acquired, err := cache.Lock(ctx, revenueLock, 0); !acquired || err != nil {
+		t.Fatalf("Expected the lock of revenue, got %v", err)
+	}
+	if _, err := NewAccountService(repo, cache, WithFeeEngine(newTestFeeEngine())).TxnAccount(ctx, "src-0", "dst-0", 50.0); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	cache.Release(ctx, revenueLock)
+
+	// Test case: concurrent transfers between distinct accounts all credit their fee to the revenue account,
+	// whose stream ends with its latest balance
+	events, err := streams.Subscribe(ctx, "revenue", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var wg sync.WaitGroup
+	for i := 1; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := service.TxnAccount(ctx, fmt.Sprintf("src-%d", i), fmt.Sprintf("dst-%d", i), 50.0); err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	revenue, _ := repo.GetAccount(ctx, "revenue")
+	if revenue.Balance != 10.0 {
+		t.Errorf("Expected revenue balance 10.00, got %.2f", revenue.Balance)
+	}
+	var changed stream.BalanceChanged
+	for i := 1; i < 10; i++ {
+		json.Unmarshal(nextEvent(t, events).Data, &changed)
+	}
+	if changed.Balance != 10.0 {
+		t.Errorf("Expected the last published revenue balance to be 10.00, got %.2f", changed.Balance)
+	}
+}
+
+func TestTransferInsufficientBalanceForFee(t *testing.T) {
+	// Setup
+	repo := repository.NewMemoryAccountRepo()
+	cache := cache.NewMemoryCache()
+	service := NewAccountService(repo, cache, WithFeeEngine(newTestFeeEngine()))
+	ctx := context.Background()
+
+	repo.CreateAccount(ctx, &account.Model{AccountId: "src", Balance: 100.0})
+	repo.CreateAccount(ctx, &account.Model{AccountId: "dst", Balance: 0})
+	repo.CreateAccount(ctx, &account.Model{AccountId: "revenue", Balance: 0})
+
+	// Test case: the amount is covered but the fee is not
+	response, _ := service.TxnAccount(ctx, "src", "dst", 100.0)
+	if response.Message != "Insufficient balance" {
+		t.Errorf("Expected insufficient balance, got %s", response.Message)
+	}
+	src, _ := repo.GetAccount(ctx, "src")
+	if src.Balance != 100.0 {
+		t.Errorf("Expected source balance untouched, got %.2f", src.Balance)
+	}
+}
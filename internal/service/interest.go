@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"internal-transfer-microservice/internal/config"
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/interest"
+	"internal-transfer-microservice/internal/domain/transfer"
+	"internal-transfer-microservice/internal/infrastructure/cache"
+	"internal-transfer-microservice/internal/infrastructure/db"
+	"internal-transfer-microservice/pkg/logger"
+)
+
+var ErrFundingAccountNotConfigured = errors.New("interest funding account is not configured")
+
+type InterestServiceImpl struct {
+	accountLocker
+	repo             interest.Repository
+	accountRepo      account.Repository
+	fundingAccountId string
+	revenueAccountId string
+	ratePlans        map[string]interest.RatePlan
+}
+
+func (i *InterestServiceImpl) AccrueDaily(ctx context.Context, date time.Time) (*interest.AccrualSummary, error) {
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1)
+	summary := &interest.AccrualSummary{Date: start.Format(interest.DateLayout)}
+
+	accounts, err := i.repo.ListInterestBearingAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, acc := range accounts {
+		plan, ok := i.ratePlans[acc.RatePlanId]
+		if !ok {
+			logger.Warnf("Account %s references unknown rate plan %s, skipping accrual", acc.AccountId, acc.RatePlanId)
+			summary.Skipped++
+			continue
+		}
+		if acc.CreatedAt != nil && !acc.CreatedAt.Before(end) {
+			summary.Skipped++
+			continue
+		}
+		balance, err := i.balanceAt(ctx, acc.AccountId, end)
+		if err != nil {
+			return summary, err
+		}
+		if balance <= 0 {
+			summary.Skipped++
+			continue
+		}
+
+		amount := balance * plan.AnnualRate / 100 * dayCountFraction(plan.DayCount, start, end)
+		created, err := i.repo.CreateAccrual(ctx, &interest.Accrual{
+			AccountId:   acc.AccountId,
+			AccrualDate: summary.Date,
+			RatePlanId:  plan.Id,
+			AnnualRate:  plan.AnnualRate,
+			DayCount:    plan.DayCount,
+			Balance:     balance,
+			Amount:      amount,
+		})
+		if err != nil {
+			return summary, err
+		}
+		if !created {
+			// already accrued by an earlier run for this day
+			summary.Existing++
+			continue
+		}
+		summary.Accrued++
+		summary.Total += amount
+	}
+
+	return summary, nil
+}
+
+// balanceAt returns the balance of the account at the given time: its balance now, less the transfers executed
+// since. The account is locked so that no transfer is saved between the two reads; fee credits to the revenue
+// account, which transfers do not lock, are counted when they have been recorded.
+func (i *InterestServiceImpl) balanceAt(ctx context.Context, accountId string, at time.Time) (float64, error) {
+	ctx = db.WithPrimary(ctx)
+	release, err := i.lockAccounts(ctx, accountId)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	acc, err := i.accountRepo.GetAccount(ctx, accountId)
+	if err != nil {
+		return 0, err
+	}
+	collectsFees := i.revenueAccountId != "" && accountId == i.revenueAccountId
+	transfers, err := i.repo.ListTransfersSince(ctx, accountId, at, collectsFees)
+	if err != nil {
+		return 0, err
+	}
+
+	balance := acc.Balance
+	for _, txn := range transfers {
+		if txn.SourceAccountId == accountId {
+			balance += txn.Amount + txn.Fee
+		}
+		if txn.DestinationAccountId == accountId {
+			balance -= txn.Amount
+		}
+		if collectsFees {
+			balance -= txn.Fee
+		}
+	}
+	return balance, nil
+}
+
+func (i *InterestServiceImpl) PostMonthly(ctx context.Context, month time.Time) (*interest.PostingSummary, error) {
+	if i.fundingAccountId == "" {
+		return nil, ErrFundingAccountNotConfigured
+	}
+
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1)
+	summary := &interest.PostingSummary{Month: first.Format(interest.MonthLayout)}
+
+	// accruals from earlier months that rounded to nothing are carried into this posting
+	byAccount, err := i.repo.GetUnpostedAccruals(ctx, last.Format(interest.DateLayout))
+	if err != nil {
+		return nil, err
+	}
+
+	accountIds := make([]string, 0, len(byAccount))
+	for accountId := range byAccount {
+		accountIds = append(accountIds, accountId)
+	}
+	sort.Strings(accountIds)
+
+	for _, accountId := range accountIds {
+		accruals := byAccount[accountId]
+		total := 0.0
+		accrualIds := make([]uuid.UUID, 0, len(accruals))
+		for _, accrual := range accruals {
+			total += accrual.Amount
+			accrualIds = append(accrualIds, accrual.ID)
+		}
+		amount := roundCents(total)
+		if amount <= 0 {
+			continue
+		}
+
+		if err := i.postInterest(ctx, accountId, amount, accrualIds); err != nil {
+			logger.Errorf("Failed to post interest to account %s: %v", accountId, err)
+			summary.Failed++
+			continue
+		}
+		summary.Posted++
+		summary.Total += amount
+	}
+
+	if summary.Failed > 0 {
+		return summary, fmt.Errorf("failed to post interest to %d accounts", summary.Failed)
+	}
+	return summary, nil
+}
+
+func (i *InterestServiceImpl) postInterest(ctx context.Context, accountId string, amount float64, accrualIds []uuid.UUID) error {
+	ctx = db.WithPrimary(ctx)
+	release, err := i.lockAccounts(ctx, i.fundingAccountId, accountId)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	fundingAccount, err := i.accountRepo.GetAccount(ctx, i.fundingAccountId)
+	if err != nil {
+		return fmt.Errorf("funding account: %w", ErrAccountNotFound)
+	}
+	acc, err := i.accountRepo.GetAccount(ctx, accountId)
+	if err != nil {
+		return ErrAccountNotFound
+	}
+
+	fundingAccount.Debit(amount)
+	acc.Credit(amount)
+	txn := &transfer.Model{
+		SourceAccountId:      fundingAccount.AccountId,
+		DestinationAccountId: acc.AccountId,
+		Amount:               amount,
+		Type:                 transfer.TypeInterest,
+		Status:               transfer.StatusCompleted,
+	}
+	return i.repo.PostAccruals(ctx, txn, accrualIds, fundingAccount, acc)
+}
+
+// dayCountFraction returns the year fraction between start and end under the given convention
+func dayCountFraction(convention string, start, end time.Time) float64 {
+	days := end.Sub(start).Hours() / 24
+	switch convention {
+	case interest.DayCountActual360:
+		return days / 360
+	case interest.DayCountActualAct:
+		fraction := 0.0
+		for cursor := start; cursor.Before(end); {
+			yearEnd := time.Date(cursor.Year()+1, 1, 1, 0, 0, 0, 0, time.UTC)
+			if yearEnd.After(end) {
+				yearEnd = end
+			}
+			fraction += yearEnd.Sub(cursor).Hours() / 24 / float64(daysInYear(cursor.Year()))
+			cursor = yearEnd
+		}
+		return fraction
+	case interest.DayCount30360:
+		d1, d2 := start.Day(), end.Day()
+		if d1 == 31 {
+			d1 = 30
+		}
+		if d2 == 31 && d1 == 30 {
+			d2 = 30
+		}
+		return float64(360*(end.Year()-start.Year())+30*(int(end.Month())-int(start.Month()))+(d2-d1)) / 360
+	default:
+		return days / 365
+	}
+}
+
+func daysInYear(year int) int {
+	if year%4 == 0 && (year%100 != 0 || year%400 == 0) {
+		return 366
+	}
+	return 365
+}
+
+func NewInterestService(repo interest.Repository, accountRepo account.Repository, cache cache.Cache, cfg *config.Config) interest.Service {
+	ratePlans := make(map[string]interest.RatePlan)
+	for _, plan := range cfg.GetRatePlans() {
+		dayCount := plan.DayCount
+		if dayCount == "" {
+			dayCount = interest.DayCountActual365
+		}
+		ratePlans[plan.Id] = interest.RatePlan{
+			Id:         plan.Id,
+			AnnualRate: plan.AnnualRate,
+			DayCount:   dayCount,
+		}
+	}
+
+	return &InterestServiceImpl{
+		accountLocker:    newAccountLocker(cache),
+		repo:             repo,
+		accountRepo:      accountRepo,
+		fundingAccountId: cfg.GetInterestFundingAccountId(),
+		revenueAccountId: cfg.GetFeeRevenueAccountId(),
+		ratePlans:        ratePlans,
+	}
+}
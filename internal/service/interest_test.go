@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"internal-transfer-microservice/internal/config"
+	"internal-transfer-microservice/internal/domain"
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/interest"
+	"internal-transfer-microservice/internal/domain/transfer"
+	"internal-transfer-microservice/internal/infrastructure/cache"
+	"internal-transfer-microservice/internal/repository"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MockInterestRepository is a mock implementation of interest.Repository sharing accounts with an in-memory account repository
+type MockInterestRepository struct {
+	accounts *repository.MemoryAccountRepoImpl
+	accruals []*interest.Accrual
+	mu       sync.Mutex
+}
+
+func NewMockInterestRepository(accounts *repository.MemoryAccountRepoImpl) *MockInterestRepository {
+	return &MockInterestRepository{accounts: accounts}
+}
+
+func (m *MockInterestRepository) ListInterestBearingAccounts(ctx context.Context) ([]account.Model, error) {
+	all, err := m.accounts.ListAccounts(ctx, "", "", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts []account.Model
+	for _, acc := range all {
+		if acc.RatePlanId != "" {
+			accounts = append(accounts, acc)
+		}
+	}
+	return accounts, nil
+}
+
+func (m *MockInterestRepository) ListTransfersSince(ctx context.Context, accountId string, since time.Time, withFees bool) ([]transfer.Model, error) {
+	all, err := m.accounts.ListAccounts(ctx, "", "", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	var transfers []transfer.Model
+	for _, acc := range all {
+		history, err := m.accounts.GetTransferHistory(ctx, acc.AccountId, math.MaxInt32)
+		if err != nil {
+			return nil, err
+		}
+		for _, txn := range history {
+			executedAt := txn.CreatedAt
+			if txn.ExecutedAt != nil {
+				executedAt = txn.ExecutedAt
+			}
+			involved := txn.SourceAccountId == accountId || txn.DestinationAccountId == accountId || withFees && txn.Fee > 0
+			if seen[txn.ID] || !involved || txn.Status != transfer.StatusCompleted || executedAt.Before(since) {
+				continue
+			}
+			seen[txn.ID] = true
+			transfers = append(transfers, txn)
+		}
+	}
+	return transfers, nil
+}
+
+func (m *MockInterestRepository) CreateAccrual(ctx context.Context, accrual *interest.Accrual) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, existing := range m.accruals {
+		if existing.AccountId == accrual.AccountId && existing.AccrualDate == accrual.AccrualDate {
+			return false, nil
+		}
+	}
+	accrual.ID = uuid.New()
+	m.accruals = append(m.accruals, accrual)
+	return true, nil
+}
+
+func (m *MockInterestRepository) GetUnpostedAccruals(ctx context.Context, through string) (map[string][]interest.Accrual, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	byAccount := make(map[string][]interest.Accrual)
+	for _, accrual := range m.accruals {
+		if accrual.TransferId == nil && accrual.AccrualDate <= through {
+			byAccount[accrual.AccountId] = append(byAccount[accrual.AccountId], *accrual)
+		}
+	}
+	return byAccount, nil
+}
+
+func (m *MockInterestRepository) PostAccruals(ctx context.Context, txn *transfer.Model, accrualIds []uuid.UUID, accounts ...*account.Model) error {
+	if err := m.accounts.UpdateAccountsInTx(ctx, txn, accounts...); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, id := range accrualIds {
+		for _, accrual := range m.accruals {
+			if accrual.ID == id {
+				accrual.TransferId = &txn.ID
+			}
+		}
+	}
+	return nil
+}
+
+func newTestInterestService(repo *repository.MemoryAccountRepoImpl) (interest.Service, *MockInterestRepository) {
+	interestRepo := NewMockInterestRepository(repo)
+	cfg := &config.Config{
+		Interest: config.InterestConfig{
+			FundingAccountId: "funding",
+			RatePlans: []config.RatePlanConfig{
+				{Id: "savings", AnnualRate: 3.65, DayCount: interest.DayCountActual365},
+			},
+		},
+	}
+	return NewInterestService(interestRepo, repo, cache.NewMemoryCache(), cfg), interestRepo
+}
+
+// openedBefore returns the base of an account created the day before day
+func openedBefore(day time.Time) domain.Base {
+	createdAt := day.AddDate(0, 0, -1)
+	return domain.Base{CreatedAt: &createdAt}
+}
+
+func TestDayCountFraction(t *testing.T) {
+	start := time.Date(2024, 2, 28, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 2)
+
+	tests := []struct {
+		convention string
+		expected   float64
+	}{
+		{interest.DayCountActual365, 2.0 / 365},
+		{interest.DayCountActual360, 2.0 / 360},
+		{interest.DayCountActualAct, 2.0 / 366},
+		{interest.DayCount30360, 3.0 / 360},
+	}
+
+	for _, tc := range tests {
+		fraction := dayCountFraction(tc.convention, start, end)
+		if math.Abs(fraction-tc.expected) > 1e-12 {
+			t.Errorf("%s: expected %.10f, got %.10f", tc.convention, tc.expected, fraction)
+		}
+	}
+}
+
+func TestInterestAccrualIsIdempotent(t *testing.T) {
+	// Setup
+	repo := repository.NewMemoryAccountRepo()
+	service, interestRepo := newTestInterestService(repo)
+	ctx := context.Background()
+	opened := openedBefore(time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC))
+
+	repo.CreateAccount(ctx, &account.Model{Base: opened, AccountId: "saver", Balance: 1000.0, RatePlanId: "savings"})
+	repo.CreateAccount(ctx, &account.Model{AccountId: "checking", Balance: 1000.0})
+	day := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	// Test case: the first run accrues, the second one finds the existing accrual
+	summary, err := service.AccrueDaily(ctx, day)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if summary.Accrued != 1 || math.Abs(summary.Total-0.1) > 1e-9 {
+		t.Errorf("Expected one accrual of 0.10, got %+v", summary)
+	}
+
+	summary, err = service.AccrueDaily(ctx, day)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if summary.Accrued != 0 || summary.Existing != 1 {
+		t.Errorf("Expected the rerun to accrue nothing, got %+v", summary)
+	}
+	if len(interestRepo.accruals) != 1 {
+		t.Errorf("Expected 1 stored accrual, got %d", len(interestRepo.accruals))
+	}
+}
+
+func TestInterestPosting(t *testing.T) {
+	// Setup
+	repo := repository.NewMemoryAccountRepo()
+	service, _ := newTestInterestService(repo)
+	ctx := context.Background()
+	opened := openedBefore(time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC))
+
+	repo.CreateAccount(ctx, &account.Model{Base: opened, AccountId: "saver", Balance: 1000.0, RatePlanId: "savings"})
+	repo.CreateAccount(ctx, &account.Model{AccountId: "funding", Balance: 100.0})
+	for day := 1; day <= 30; day++ {
+		if _, err := service.AccrueDaily(ctx, time.Date(2026, 9, day, 0, 0, 0, 0, time.UTC)); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	// Test case: 30 days at 0.10 a day are posted from the funding account
+	summary, err := service.PostMonthly(ctx, time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if summary.Posted != 1 || summary.Total != 3.0 {
+		t.Errorf("Expected 3.00 posted to one account, got %+v", summary)
+	}
+
+	saver, _ := repo.GetAccount(ctx, "saver")
+	funding, _ := repo.GetAccount(ctx, "funding")
+	if saver.Balance != 1003.0 {
+		t.Errorf("Expected saver balance 1003.00, got %.2f", saver.Balance)
+	}
+	if funding.Balance != 97.0 {
+		t.Errorf("Expected funding balance 97.00, got %.2f", funding.Balance)
+	}
+
+	// Test case: posting the same month again does nothing
+	summary, err = service.PostMonthly(ctx, time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if summary.Posted != 0 {
+		t.Errorf("Expected nothing posted on rerun, got %+v", summary)
+	}
+}
+
+func TestInterestAccrualBackfill(t *testing.T) {
+	// Setup
+	repo := repository.NewMemoryAccountRepo()
+	service, interestRepo := newTestInterestService(repo)
+	accounts := NewAccountService(repo, cache.NewMemoryCache())
+	ctx := context.Background()
+	day := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	repo.CreateAccount(ctx, &account.Model{Base: openedBefore(day), AccountId: "saver", Balance: 1000.0, RatePlanId: "savings"})
+	repo.CreateAccount(ctx, &account.Model{Base: openedBefore(day), AccountId: "payee"})
+	repo.CreateAccount(ctx, &account.Model{AccountId: "newcomer", Balance: 1000.0, RatePlanId: "savings"})
+	if _, err := accounts.TxnAccount(ctx, "saver", "payee", 500.0); err != nil {
+		t.Fatalf("Expected the transfer to succeed, got %v", err)
+	}
+
+	// Test case: a past day accrues on the balance at its end, before the later transfer, and skips later accounts
+	summary, err := service.AccrueDaily(ctx, day)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if summary.Accrued != 1 || summary.Skipped != 1 || math.Abs(summary.Total-0.1) > 1e-9 {
+		t.Errorf("Expected one accrual of 0.10 and one skipped account, got %+v", summary)
+	}
+	if len(interestRepo.accruals) != 1 || interestRepo.accruals[0].Balance != 1000.0 {
+		t.Errorf("Expected an accrual on a balance of 1000.00, got %+v", interestRepo.accruals)
+	}
+}
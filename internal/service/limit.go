@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"internal-transfer-microservice/internal/config"
+	"internal-transfer-microservice/internal/domain/limit"
+)
+
+type LimitEngineImpl struct {
+	repo  limit.Repository
+	tiers map[string]config.LimitTierConfig
+}
+
+func (l *LimitEngineImpl) tierLimits(tier string) (config.LimitTierConfig, bool) {
+	limits, ok := l.tiers[tier]
+	if !ok {
+		// fall back to the limits configured without a tier
+		limits, ok = l.tiers[""]
+	}
+	return limits, ok
+}
+
+func (l *LimitEngineImpl) Remaining(ctx context.Context, accountId, tier string, at time.Time) (*limit.Allowance, error) {
+	allowance := &limit.Allowance{Tier: tier}
+	limits, ok := l.tierLimits(tier)
+	if !ok {
+		return allowance, nil
+	}
+
+	daily, monthly := limit.DailyPeriod(at), limit.MonthlyPeriod(at)
+	counters, err := l.repo.GetCounters(ctx, accountId, daily, monthly)
+	if err != nil {
+		return nil, err
+	}
+
+	if limits.PerTransferMax > 0 {
+		allowance.PerTransferMax = &limits.PerTransferMax
+	}
+	if limits.DailyAmount > 0 {
+		remaining := roundCents(limits.DailyAmount - counters[daily].Amount)
+		allowance.DailyAmount = &remaining
+	}
+	if limits.MonthlyAmount > 0 {
+		remaining := roundCents(limits.MonthlyAmount - counters[monthly].Amount)
+		allowance.MonthlyAmount = &remaining
+	}
+	if limits.DailyCount > 0 {
+		remaining := limits.DailyCount - counters[daily].Count
+		allowance.DailyCount = &remaining
+	}
+	if limits.MonthlyCount > 0 {
+		remaining := limits.MonthlyCount - counters[monthly].Count
+		allowance.MonthlyCount = &remaining
+	}
+	return allowance, nil
+}
+
+func (l *LimitEngineImpl) Caps(tier string) limit.Caps {
+	limits, _ := l.tierLimits(tier)
+	return limit.Caps{
+		DailyAmount:   limits.DailyAmount,
+		MonthlyAmount: limits.MonthlyAmount,
+		DailyCount:    limits.DailyCount,
+		MonthlyCount:  limits.MonthlyCount,
+	}
+}
+
+func (l *LimitEngineImpl) Check(ctx context.Context, accountId, tier string, amount float64, at time.Time) error {
+	if _, ok := l.tierLimits(tier); !ok {
+		return nil
+	}
+
+	remaining, err := l.Remaining(ctx, accountId, tier, at)
+	if err != nil {
+		return err
+	}
+
+	code := ""
+	switch {
+	case remaining.PerTransferMax != nil && amount > *remaining.PerTransferMax:
+		code = limit.CodePerTransferMax
+	case remaining.DailyCount != nil && *remaining.DailyCount < 1:
+		code = limit.CodeDailyCount
+	case remaining.MonthlyCount != nil && *remaining.MonthlyCount < 1:
+		code = limit.CodeMonthlyCount
+	case remaining.DailyAmount != nil && amount > *remaining.DailyAmount:
+		code = limit.CodeDailyAmount
+	case remaining.MonthlyAmount != nil && amount > *remaining.MonthlyAmount:
+		code = limit.CodeMonthlyAmount
+	}
+	if code != "" {
+		return &limit.ExceededError{Code: code, Remaining: remaining}
+	}
+	return nil
+}
+
+func NewLimitEngine(repo limit.Repository, cfg *config.Config) limit.Engine {
+	tiers := make(map[string]config.LimitTierConfig)
+	for _, tier := range cfg.GetLimitTiers() {
+		tiers[tier.Tier] = tier
+	}
+	return &LimitEngineImpl{
+		repo:  repo,
+		tiers: tiers,
+	}
+}
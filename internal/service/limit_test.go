@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"internal-transfer-microservice/internal/config"
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/limit"
+	"internal-transfer-microservice/internal/infrastructure/cache"
+	"internal-transfer-microservice/internal/repository"
+	"testing"
+)
+
+func newTestLimitService(repo *repository.MemoryAccountRepoImpl) account.Service {
+	cfg := &config.Config{
+		Limits: config.LimitsConfig{
+			Tiers: []config.LimitTierConfig{
+				{Tier: "", PerTransferMax: 500, DailyAmount: 800, DailyCount: 3},
+				{Tier: "vip"},
+			},
+		},
+	}
+	engine := NewLimitEngine(repo, cfg)
+	return NewAccountService(repo, cache.NewMemoryCache(), WithLimitEngine(engine))
+}
+
+func TestTransferLimits(t *testing.T) {
+	// Setup
+	repo := repository.NewMemoryAccountRepo()
+	service := newTestLimitService(repo)
+	ctx := context.Background()
+
+	repo.CreateAccount(ctx, &account.Model{AccountId: "src", Balance: 10000.0, Tier: account.DefaultTier})
+	repo.CreateAccount(ctx, &account.Model{AccountId: "dst", Balance: 0})
+
+	// Test case: a single transfer above the per-transfer maximum
+	response, err := service.TxnAccount(ctx, "src", "dst", 600.0)
+	var exceeded *limit.ExceededError
+	if !errors.As(err, &exceeded) || response.ErrorCode != limit.CodePerTransferMax {
+		t.Fatalf("Expected %s, got %s (%v)", limit.CodePerTransferMax, response.ErrorCode, err)
+	}
+
+	// Test case: the daily amount runs out before the daily count
+	for _, amount := range []float64{400.0, 300.0} {
+		if _, err := service.TxnAccount(ctx, "src", "dst", amount); err != nil {
+			t.Fatalf("Expected transfer of %.2f to succeed, got %v", amount, err)
+		}
+	}
+	response, err = service.TxnAccount(ctx, "src", "dst", 200.0)
+	if !errors.As(err, &exceeded) || response.ErrorCode != limit.CodeDailyAmount {
+		t.Fatalf("Expected %s, got %s (%v)", limit.CodeDailyAmount, response.ErrorCode, err)
+	}
+	if response.Remaining == nil || response.Remaining.DailyAmount == nil || *response.Remaining.DailyAmount != 100.0 {
+		t.Errorf("Expected 100.00 left for the day, got %+v", response.Remaining)
+	}
+
+	// Test case: the last transfer of the day fits, the next one hits the count limit
+	if _, err := service.TxnAccount(ctx, "src", "dst", 100.0); err != nil {
+		t.Fatalf("Expected transfer to succeed, got %v", err)
+	}
+	response, err = service.TxnAccount(ctx, "src", "dst", 0.01)
+	if !errors.As(err, &exceeded) || response.ErrorCode != limit.CodeDailyCount {
+		t.Fatalf("Expected %s, got %s (%v)", limit.CodeDailyCount, response.ErrorCode, err)
+	}
+
+	src, _ := repo.GetAccount(ctx, "src")
+	if src.Balance != 9200.0 {
+		t.Errorf("Expected source balance 9200.00, got %.2f", src.Balance)
+	}
+}
+
+// staleCounters serves counters read before any transfer was recorded, as a check racing concurrent transfers sees them
+type staleCounters struct{}
+
+func (staleCounters) GetCounters(ctx context.Context, accountId string, periods ...string) (map[string]limit.Counter, error) {
+	return map[string]limit.Counter{}, nil
+}
+
+func TestTransferLimitsWithStaleCounters(t *testing.T) {
+	// Setup
+	repo := repository.NewMemoryAccountRepo()
+	cfg := &config.Config{Limits: config.LimitsConfig{Tiers: []config.LimitTierConfig{{Tier: "", DailyAmount: 800}}}}
+	service := NewAccountService(repo, cache.NewMemoryCache(), WithLimitEngine(NewLimitEngine(staleCounters{}, cfg)))
+	ctx := context.Background()
+
+	repo.CreateAccount(ctx, &account.Model{AccountId: "src", Balance: 10000.0})
+	repo.CreateAccount(ctx, &account.Model{AccountId: "dst", Balance: 0})
+
+	// Test case: a transfer the check lets through is refused when recording it would exceed the limit
+	for _, amount := range []float64{300.0, 300.0} {
+		if _, err := service.TxnAccount(ctx, "src", "dst", amount); err != nil {
+			t.Fatalf("Expected transfer of %.2f to succeed, got %v", amount, err)
+		}
+	}
+	response, err := service.TxnAccount(ctx, "src", "dst", 300.0)
+	var exceeded *limit.ExceededError
+	if !errors.As(err, &exceeded) || response.ErrorCode != limit.CodeDailyAmount {
+		t.Fatalf("Expected %s, got %s (%v)", limit.CodeDailyAmount, response.ErrorCode, err)
+	}
+
+	src, _ := repo.GetAccount(ctx, "src")
+	if src.Balance != 9400.0 {
+		t.Errorf("Expected source balance 9400.00, got %.2f", src.Balance)
+	}
+	if history, _ := repo.GetTransferHistory(ctx, "src", 10); len(history) != 2 {
+		t.Errorf("Expected 2 transfers recorded, got %d", len(history))
+	}
+}
+
+func TestTransferLimitsPerTier(t *testing.T) {
+	// Setup
+	repo := repository.NewMemoryAccountRepo()
+	service := newTestLimitService(repo)
+	ctx := context.Background()
+
+	repo.CreateAccount(ctx, &account.Model{AccountId: "src", Balance: 10000.0, Tier: "vip"})
+	repo.CreateAccount(ctx, &account.Model{AccountId: "dst", Balance: 0})
+
+	// Test case: the vip tier has no limits configured
+	if _, err := service.TxnAccount(ctx, "src", "dst", 5000.0); err != nil {
+		t.Errorf("Expected unlimited transfer for vip tier, got %v", err)
+	}
+}
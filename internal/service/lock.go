@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"internal-transfer-microservice/internal/infrastructure/cache"
+)
+
+const UpdateAccountResourceLockKey = "update_account:%s"
+
+// accountLockTTL bounds how long an account stays locked by a holder that never releases it
+const accountLockTTL = 30 * time.Second
+
+// accountLocker serialises balance updates through per-account locks held in the cache
+type accountLocker struct {
+	cache            cache.Cache
+	lockPollInterval time.Duration
+}
+
+func newAccountLocker(cache cache.Cache) accountLocker {
+	return accountLocker{
+		cache:            cache,
+		lockPollInterval: 10 * time.Millisecond,
+	}
+}
+
+func (l *accountLocker) acquireLockWithPolling(ctx context.Context, key string, lockTTL, waitTimeout time.Duration) (token string, err error) {
+	deadline := time.Now().Add(waitTimeout)
+	for {
+		acquired, err := l.cache.Lock(ctx, key, lockTTL)
+		if err != nil {
+			return "", err
+		}
+		if acquired {
+			return key, nil
+		}
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("timed out waiting for %s", key)
+		}
+		time.Sleep(l.lockPollInterval)
+	}
+}
+
+// lockAccounts acquires the update lock of every distinct account and returns a func releasing them.
+// Locks are always taken in key order to avoid deadlock in case of concurrent txn (A->B) & (B->A).
+func (l *accountLocker) lockAccounts(ctx context.Context, accountIds ...string) (release func(), err error) {
+	seen := make(map[string]bool, len(accountIds))
+	keys := make([]string, 0, len(accountIds))
+	for _, accountId := range accountIds {
+		if seen[accountId] {
+			continue
+		}
+		seen[accountId] = true
+		keys = append(keys, fmt.Sprintf(UpdateAccountResourceLockKey, accountId))
+	}
+	sort.Strings(keys)
+
+	acquired := make([]string, 0, len(keys))
+	release = func() {
+		for i := len(acquired) - 1; i >= 0; i-- {
+			l.cache.Release(ctx, acquired[i])
+		}
+	}
+	for _, key := range keys {
+		resource, err := l.acquireLockWithPolling(ctx, key, accountLockTTL, 100*time.Millisecond)
+		if err != nil {
+			release()
+			return nil, err
+		}
+		acquired = append(acquired, resource)
+	}
+	return release, nil
+}
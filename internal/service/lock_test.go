@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"internal-transfer-microservice/internal/infrastructure/cache"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLockAccountsExcludeConcurrentHolders(t *testing.T) {
+	// Setup
+	locker := newAccountLocker(cache.NewMemoryCache())
+	ctx := context.Background()
+
+	// Test case: a held account cannot be locked until it is released
+	release, err := locker.lockAccounts(ctx, "a")
+	if err != nil {
+		t.Fatalf("Expected the lock of a, got %v", err)
+	}
+	if _, err := locker.lockAccounts(ctx, "a", "b"); err == nil {
+		t.Fatalf("Expected locking a held account to time out")
+	}
+	// the lock of b taken before timing out on a is given back
+	releaseB, err := locker.lockAccounts(ctx, "b")
+	if err != nil {
+		t.Fatalf("Expected the lock of b to be released, got %v", err)
+	}
+	releaseB()
+
+	acquired := make(chan error, 1)
+	go func() {
+		release, err := locker.lockAccounts(ctx, "a")
+		if err == nil {
+			release()
+		}
+		acquired <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	release()
+	if err := <-acquired; err != nil {
+		t.Errorf("Expected a to be locked once released, got %v", err)
+	}
+
+	// Test case: concurrent holders of the same account never overlap
+	var holders, maxHolders atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := locker.lockAccounts(ctx, "a")
+			if err != nil {
+				t.Errorf("Expected the lock of a, got %v", err)
+				return
+			}
+			held := holders.Add(1)
+			if held > maxHolders.Load() {
+				maxHolders.Store(held)
+			}
+			time.Sleep(time.Millisecond)
+			holders.Add(-1)
+			release()
+		}()
+	}
+	wg.Wait()
+	if maxHolders.Load() != 1 {
+		t.Errorf("Expected a single holder at a time, got %d", maxHolders.Load())
+	}
+}
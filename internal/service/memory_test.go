@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/transfer"
+	"internal-transfer-microservice/internal/infrastructure/cache"
+	"internal-transfer-microservice/internal/repository"
+	"testing"
+	"time"
+)
+
+func TestMemoryCacheLocks(t *testing.T) {
+	// Setup
+	memory := cache.NewMemoryCache()
+	ctx := context.Background()
+
+	// Test case: a held lock is refused until it is released
+	if acquired, err := memory.Lock(ctx, "job", 0); !acquired || err != nil {
+		t.Fatalf("Expected the lock to be acquired, got %v (%v)", acquired, err)
+	}
+	if acquired, err := memory.Lock(ctx, "job", 0); acquired || err != nil {
+		t.Errorf("Expected the held lock to be refused, got %v (%v)", acquired, err)
+	}
+	memory.Release(ctx, "job")
+	if acquired, _ := memory.Lock(ctx, "job", 0); !acquired {
+		t.Error("Expected the released lock to be acquired")
+	}
+
+	// Test case: locks and values expire with their TTL
+	memory.Lock(ctx, "expiring", 20*time.Millisecond)
+	memory.Set(ctx, "key", "value", 20*time.Millisecond)
+	if _, err := memory.Get(ctx, "key"); err != nil {
+		t.Errorf("Expected the value before it expires, got %v", err)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if acquired, _ := memory.Lock(ctx, "expiring", 0); !acquired {
+		t.Error("Expected the expired lock to be acquired")
+	}
+	if _, err := memory.Get(ctx, "key"); !errors.Is(err, cache.ErrNotFound) {
+		t.Errorf("Expected the expired value to be gone, got %v", err)
+	}
+}
+
+func TestMemoryAccountRepoTransactions(t *testing.T) {
+	// Setup
+	repo := repository.NewMemoryAccountRepo()
+	ctx := context.Background()
+	repo.CreateAccount(ctx, &account.Model{AccountId: "src", Balance: 100})
+	repo.CreateAccount(ctx, &account.Model{AccountId: "dst", Balance: 0})
+
+	// Test case: changes to a read account are not stored until written
+	src, _ := repo.GetAccount(ctx, "src")
+	src.Balance = 0
+	if stored, _ := repo.GetAccount(ctx, "src"); stored.Balance != 100 {
+		t.Errorf("Expected stored balance 100, got %.2f", stored.Balance)
+	}
+
+	// Test case: a write breaching one account's overdraft limit stores nothing
+	dst, _ := repo.GetAccount(ctx, "dst")
+	src.Debit(150)
+	dst.Credit(150)
+	txn := &transfer.Model{SourceAccountId: "src", DestinationAccountId: "dst", Amount: 150, Status: transfer.StatusCompleted}
+	if err := repo.UpdateAccountsInTx(ctx, txn, dst, src); !errors.Is(err, account.ErrOverdraftLimitExceeded) {
+		t.Fatalf("Expected ErrOverdraftLimitExceeded, got %v", err)
+	}
+	if stored, _ := repo.GetAccount(ctx, "dst"); stored.Balance != 0 {
+		t.Errorf("Expected dst balance 0 after the refused write, got %.2f", stored.Balance)
+	}
+	if history, _ := repo.GetTransferHistory(ctx, "src", 10); len(history) != 0 {
+		t.Errorf("Expected no transfer recorded, got %d", len(history))
+	}
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"internal-transfer-microservice/internal/domain/migration"
+	"internal-transfer-microservice/pkg/logger"
+)
+
+type MigratorImpl struct {
+	repo       migration.Repository
+	migrations []migration.Migration
+	byVersion  map[int]migration.Migration
+}
+
+func (m *MigratorImpl) Up(ctx context.Context) ([]migration.Step, error) {
+	return m.migrate(ctx, func([]migration.Applied) int {
+		if len(m.migrations) == 0 {
+			return 0
+		}
+		return m.migrations[len(m.migrations)-1].Version
+	})
+}
+
+func (m *MigratorImpl) Down(ctx context.Context, steps int) ([]migration.Step, error) {
+	if steps < 1 {
+		steps = 1
+	}
+	return m.migrate(ctx, func(applied []migration.Applied) int {
+		if steps >= len(applied) {
+			return 0
+		}
+		return applied[len(applied)-steps-1].Version
+	})
+}
+
+func (m *MigratorImpl) To(ctx context.Context, version int) ([]migration.Step, error) {
+	if _, known := m.byVersion[version]; !known && version != 0 {
+		return nil, fmt.Errorf("%w: %d", migration.ErrUnknownVersion, version)
+	}
+	return m.migrate(ctx, func([]migration.Applied) int { return version })
+}
+
+// migrate reverts the applied migrations above the target version, newest first, then applies the pending
+// ones up to it in order, under the migration lock. It stops at the first migration that fails, returning
+// the steps taken before it.
+func (m *MigratorImpl) migrate(ctx context.Context, target func(applied []migration.Applied) int) ([]migration.Step, error) {
+	release, err := m.repo.Lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if err := m.repo.EnsureTable(ctx); err != nil {
+		return nil, err
+	}
+	applied, err := m.repo.ListApplied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.verify(applied); err != nil {
+		return nil, err
+	}
+	version := target(applied)
+
+	var steps []migration.Step
+	isApplied := make(map[int]bool, len(applied))
+	for i := len(applied) - 1; i >= 0; i-- {
+		isApplied[applied[i].Version] = true
+		if applied[i].Version <= version {
+			continue
+		}
+		script := m.byVersion[applied[i].Version]
+		if script.Down == "" {
+			return steps, fmt.Errorf("%w: %d_%s", migration.ErrIrreversible, script.Version, script.Name)
+		}
+		if err := m.repo.Revert(ctx, script); err != nil {
+			return steps, fmt.Errorf("reverting migration %d_%s: %w", script.Version, script.Name, err)
+		}
+		logger.Infof("Reverted migration %d_%s", script.Version, script.Name)
+		steps = append(steps, migration.Step{Version: script.Version, Name: script.Name, Direction: migration.DirectionDown})
+	}
+
+	for _, script := range m.migrations {
+		if script.Version > version || isApplied[script.Version] {
+			continue
+		}
+		if err := m.repo.Apply(ctx, script, time.Now().UTC()); err != nil {
+			return steps, fmt.Errorf("applying migration %d_%s: %w", script.Version, script.Name, err)
+		}
+		logger.Infof("Applied migration %d_%s", script.Version, script.Name)
+		steps = append(steps, migration.Step{Version: script.Version, Name: script.Name, Direction: migration.DirectionUp})
+	}
+	return steps, nil
+}
+
+// verify refuses to migrate a database whose applied migrations were edited since, or are unknown to this build
+func (m *MigratorImpl) verify(applied []migration.Applied) error {
+	for _, record := range applied {
+		script, known := m.byVersion[record.Version]
+		if !known {
+			return fmt.Errorf("%w: %d_%s", migration.ErrUnknownMigration, record.Version, record.Name)
+		}
+		if script.Checksum() != record.Checksum {
+			return fmt.Errorf("%w: %d_%s", migration.ErrChecksumMismatch, record.Version, record.Name)
+		}
+	}
+	return nil
+}
+
+func (m *MigratorImpl) Status(ctx context.Context) ([]migration.Status, error) {
+	if err := m.repo.EnsureTable(ctx); err != nil {
+		return nil, err
+	}
+	applied, err := m.repo.ListApplied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make(map[int]migration.Applied, len(applied))
+	for _, record := range applied {
+		records[record.Version] = record
+	}
+	statuses := make([]migration.Status, 0, len(m.migrations))
+	for _, script := range m.migrations {
+		status := migration.Status{Version: script.Version, Name: script.Name}
+		if record, ok := records[script.Version]; ok {
+			appliedAt := record.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+			status.Modified = record.Checksum != script.Checksum()
+			delete(records, script.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, record := range applied {
+		if _, missing := records[record.Version]; missing {
+			appliedAt := record.AppliedAt
+			statuses = append(statuses, migration.Status{
+				Version:   record.Version,
+				Name:      record.Name,
+				Applied:   true,
+				AppliedAt: &appliedAt,
+				Missing:   true,
+			})
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// NewMigrator migrates the database with the migrations, which must be ordered by version as returned by migration.Load
+func NewMigrator(repo migration.Repository, migrations []migration.Migration) migration.Service {
+	byVersion := make(map[int]migration.Migration, len(migrations))
+	for _, script := range migrations {
+		byVersion[script.Version] = script
+	}
+	return &MigratorImpl{
+		repo:       repo,
+		migrations: migrations,
+		byVersion:  byVersion,
+	}
+}
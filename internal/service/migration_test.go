@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"internal-transfer-microservice/internal/domain/migration"
+	"internal-transfer-microservice/migrations"
+	"sort"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// MockMigrationRepository is a mock implementation of migration.Repository recording the scripts it runs
+type MockMigrationRepository struct {
+	applied map[int]migration.Applied
+	scripts []string
+	locks   int
+	mu      sync.Mutex
+	// fail is run by Apply and Revert with the script about to run
+	fail func(script string) error
+}
+
+func NewMockMigrationRepository() *MockMigrationRepository {
+	return &MockMigrationRepository{applied: make(map[int]migration.Applied)}
+}
+
+func (m *MockMigrationRepository) Lock(ctx context.Context) (func(), error) {
+	m.mu.Lock()
+	m.locks++
+	m.mu.Unlock()
+	return func() {}, nil
+}
+
+func (m *MockMigrationRepository) EnsureTable(ctx context.Context) error {
+	return nil
+}
+
+func (m *MockMigrationRepository) ListApplied(ctx context.Context) ([]migration.Applied, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	applied := make([]migration.Applied, 0, len(m.applied))
+	for _, record := range m.applied {
+		applied = append(applied, record)
+	}
+	sort.Slice(applied, func(i, j int) bool { return applied[i].Version < applied[j].Version })
+	return applied, nil
+}
+
+func (m *MockMigrationRepository) Apply(ctx context.Context, script migration.Migration, appliedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.fail != nil {
+		if err := m.fail(script.Up); err != nil {
+			return err
+		}
+	}
+	m.scripts = append(m.scripts, script.Up)
+	m.applied[script.Version] = migration.Applied{Version: script.Version, Name: script.Name, Checksum: script.Checksum(), AppliedAt: appliedAt}
+	return nil
+}
+
+func (m *MockMigrationRepository) Revert(ctx context.Context, script migration.Migration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.fail != nil {
+		if err := m.fail(script.Down); err != nil {
+			return err
+		}
+	}
+	m.scripts = append(m.scripts, script.Down)
+	delete(m.applied, script.Version)
+	return nil
+}
+
+func testMigrations(t *testing.T) []migration.Migration {
+	migrations, err := migration.Load(fstest.MapFS{
+		"0001_accounts.up.sql":      {Data: []byte("create accounts")},
+		"0001_accounts.down.sql":    {Data: []byte("drop accounts")},
+		"0002_transfers.up.sql":     {Data: []byte("create transfers")},
+		"0002_transfers.down.sql":   {Data: []byte("drop transfers")},
+		"0003_constraints.up.sql":   {Data: []byte("add constraints")},
+		"0003_constraints.down.sql": {Data: []byte("drop constraints")},
+		"README.md":                 {Data: []byte("not a migration")},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return migrations
+}
+
+func stepVersions(steps []migration.Step) []int {
+	versions := make([]int, len(steps))
+	for i, step := range steps {
+		versions[i] = step.Version
+	}
+	return versions
+}
+
+func TestMigrateUpDownAndTo(t *testing.T) {
+	// Setup
+	repo := NewMockMigrationRepository()
+	migrator := NewMigrator(repo, testMigrations(t))
+	ctx := context.Background()
+
+	// Test case: up applies every migration in order, then has nothing left to do
+	steps, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := stepVersions(steps); len(got) != 3 || got[0] != 1 || got[2] != 3 {
+		t.Errorf("Expected migrations 1 to 3 applied, got %v", got)
+	}
+	if steps, _ := migrator.Up(ctx); len(steps) != 0 {
+		t.Errorf("Expected no migrations on a second run, got %d", len(steps))
+	}
+
+	// Test case: down reverts the newest migrations first
+	steps, err = migrator.Down(ctx, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := stepVersions(steps); len(got) != 2 || got[0] != 3 || got[1] != 2 || steps[0].Direction != migration.DirectionDown {
+		t.Errorf("Expected migrations 3 and 2 reverted, got %v", got)
+	}
+
+	// Test case: to applies up to the version, or reverts down to it
+	if steps, err := migrator.To(ctx, 2); err != nil || len(steps) != 1 || steps[0].Version != 2 {
+		t.Errorf("Expected migration 2 applied, got %v (%v)", stepVersions(steps), err)
+	}
+	if steps, err := migrator.To(ctx, 0); err != nil || len(steps) != 2 {
+		t.Errorf("Expected every migration reverted, got %v (%v)", stepVersions(steps), err)
+	}
+	if _, err := migrator.To(ctx, 7); !errors.Is(err, migration.ErrUnknownVersion) {
+		t.Errorf("Expected ErrUnknownVersion, got %v", err)
+	}
+
+	expected := []string{"create accounts", "create transfers", "add constraints", "drop constraints", "drop transfers", "create transfers", "drop transfers", "drop accounts"}
+	if len(repo.scripts) != len(expected) {
+		t.Fatalf("Expected %d scripts run, got %v", len(expected), repo.scripts)
+	}
+	for i, script := range expected {
+		if repo.scripts[i] != script {
+			t.Errorf("Expected script %d to be %q, got %q", i, script, repo.scripts[i])
+		}
+	}
+	if repo.locks != 5 {
+		t.Errorf("Expected every valid run to take the migration lock, got %d locks", repo.locks)
+	}
+}
+
+func TestMigrateStopsAtFailure(t *testing.T) {
+	// Setup
+	repo := NewMockMigrationRepository()
+	repo.fail = func(script string) error {
+		if script == "add constraints" {
+			return errors.New("constraint violated")
+		}
+		return nil
+	}
+	migrator := NewMigrator(repo, testMigrations(t))
+	ctx := context.Background()
+
+	// Test case: the migrations before the failing one stay applied
+	steps, err := migrator.Up(ctx)
+	if err == nil {
+		t.Fatal("Expected an error, got nil")
+	}
+	if len(steps) != 2 {
+		t.Errorf("Expected 2 migrations applied before the failure, got %d", len(steps))
+	}
+	statuses, _ := migrator.Status(ctx)
+	if len(statuses) != 3 || !statuses[1].Applied || statuses[2].Applied {
+		t.Errorf("Expected migrations 1 and 2 applied and 3 pending, got %+v", statuses)
+	}
+}
+
+func TestMigrateRefusesModifiedMigrations(t *testing.T) {
+	// Setup
+	repo := NewMockMigrationRepository()
+	migrator := NewMigrator(repo, testMigrations(t))
+	ctx := context.Background()
+	migrator.To(ctx, 2)
+
+	// Test case: an applied migration edited since is flagged and blocks migrating
+	record := repo.applied[1]
+	record.Checksum = "edited"
+	repo.applied[1] = record
+	if _, err := migrator.Up(ctx); !errors.Is(err, migration.ErrChecksumMismatch) {
+		t.Errorf("Expected ErrChecksumMismatch, got %v", err)
+	}
+
+	// Test case: a migration applied by a newer build is flagged and blocks migrating
+	repo.applied[1] = migration.Applied{Version: 1, Name: "accounts", Checksum: testMigrations(t)[0].Checksum()}
+	repo.applied[9] = migration.Applied{Version: 9, Name: "future"}
+	if _, err := migrator.Down(ctx, 1); !errors.Is(err, migration.ErrUnknownMigration) {
+		t.Errorf("Expected ErrUnknownMigration, got %v", err)
+	}
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(statuses) != 4 || !statuses[3].Missing || statuses[2].Applied {
+		t.Errorf("Unexpected statuses %+v", statuses)
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	// Test case: every driver's migrations load, are numbered without gaps and can all be reverted
+	var expected []migration.Migration
+	for _, driver := range migrations.Drivers {
+		files, err := migrations.For(driver)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		loaded, err := migration.Load(files)
+		if err != nil {
+			t.Fatalf("Expected no error loading the %s migrations, got %v", driver, err)
+		}
+		if len(loaded) == 0 {
+			t.Fatalf("Expected embedded %s migrations, got none", driver)
+		}
+		for i, script := range loaded {
+			if script.Version != i+1 {
+				t.Errorf("Expected %s migration %d to have version %d, got %d", driver, i, i+1, script.Version)
+			}
+			if script.Down == "" {
+				t.Errorf("Expected %s migration %d_%s to have a down script", driver, script.Version, script.Name)
+			}
+		}
+
+		// Test case: the drivers have the same migrations
+		if expected == nil {
+			expected = loaded
+			continue
+		}
+		if len(loaded) != len(expected) {
+			t.Errorf("Expected %d %s migrations, got %d", len(expected), driver, len(loaded))
+			continue
+		}
+		for i := range loaded {
+			if loaded[i].Name != expected[i].Name {
+				t.Errorf("Expected %s migration %d to be %s, got %s", driver, loaded[i].Version, expected[i].Name, loaded[i].Name)
+			}
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"internal-transfer-microservice/internal/config"
+	"internal-transfer-microservice/internal/domain/event"
+	"internal-transfer-microservice/internal/infrastructure/cache"
+	"internal-transfer-microservice/internal/infrastructure/publisher"
+	"internal-transfer-microservice/pkg/logger"
+)
+
+// OutboxRelayLockKey is held while a relay publishes, so concurrent relays cannot reorder events
+const OutboxRelayLockKey = "outbox:relay"
+
+const (
+	outboxRelayLockTTL = 30 * time.Second
+	// outboxRelayWindow is how long a relay starts publishing for. Publishes still running when the lock is
+	// about to expire are cut off, so a relay never publishes while another holds the lock.
+	outboxRelayWindow = 20 * time.Second
+)
+
+type OutboxRelayImpl struct {
+	repo      event.Repository
+	cache     cache.Cache
+	publisher publisher.EventPublisher
+	topic     string
+	interval  time.Duration
+	batchSize int
+	lockTTL   time.Duration
+	window    time.Duration
+}
+
+func (o *OutboxRelayImpl) RelayPending(ctx context.Context) (int, error) {
+	start := time.Now()
+	acquired, err := o.cache.Lock(ctx, OutboxRelayLockKey, o.lockTTL)
+	if err != nil {
+		return 0, err
+	}
+	if !acquired {
+		// another relay is draining the outbox
+		return 0, nil
+	}
+	defer o.cache.Release(ctx, OutboxRelayLockKey)
+	publishCtx, cancel := context.WithDeadline(ctx, start.Add((o.window+o.lockTTL)/2))
+	defer cancel()
+
+	sent := 0
+	for {
+		events, err := o.repo.ListPendingEvents(ctx, o.batchSize)
+		if err != nil {
+			return sent, err
+		}
+
+		for i := range events {
+			if time.Since(start) > o.window {
+				// the rest are left to the next pass, under a fresh lock
+				return sent, nil
+			}
+			if err := o.publish(publishCtx, &events[i]); err != nil {
+				if recordErr := o.repo.RecordEventFailure(ctx, events[i].ID, err.Error()); recordErr != nil {
+					logger.Errorf("Failed to record delivery failure of event %s: %v", events[i].ID, recordErr)
+				}
+				// later events wait for this one, so consumers never see them out of order
+				return sent, err
+			}
+			// a crash before this point publishes the event again: delivery is at least once
+			if err := o.repo.MarkEventSent(ctx, events[i].ID, time.Now().UTC()); err != nil {
+				return sent, err
+			}
+			sent++
+		}
+
+		if len(events) < o.batchSize {
+			return sent, nil
+		}
+	}
+}
+
+func (o *OutboxRelayImpl) publish(ctx context.Context, outbox *event.Outbox) error {
+	payload, err := json.Marshal(outbox.ToEnvelope())
+	if err != nil {
+		return err
+	}
+	return o.publisher.Publish(ctx, o.topic, publisher.Message{
+		Key:     outbox.PartitionKey,
+		Payload: payload,
+		Headers: map[string]string{
+			"event_id":       outbox.ID.String(),
+			"event_type":     outbox.EventType,
+			"schema_version": strconv.Itoa(outbox.SchemaVersion),
+		},
+	})
+}
+
+func (o *OutboxRelayImpl) Run(ctx context.Context) error {
+	ticker := time.NewTicker(o.interval)
+	defer ticker.Stop()
+
+	for {
+		sent, err := o.RelayPending(ctx)
+		if err != nil && ctx.Err() == nil {
+			logger.Errorf("Failed to relay outbox events: %v", err)
+		}
+		if sent > 0 {
+			logger.Infof("Relayed %d outbox events", sent)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func NewOutboxRelay(repo event.Repository, cache cache.Cache, publisher publisher.EventPublisher, cfg *config.Config) event.Relay {
+	relay := &OutboxRelayImpl{
+		repo:      repo,
+		cache:     cache,
+		publisher: publisher,
+		topic:     cfg.GetEventTopic(),
+		interval:  cfg.GetOutboxRelayInterval(),
+		batchSize: cfg.GetOutboxRelayBatchSize(),
+		lockTTL:   outboxRelayLockTTL,
+		window:    outboxRelayWindow,
+	}
+	if relay.interval <= 0 {
+		relay.interval = time.Second
+	}
+	if relay.batchSize <= 0 {
+		relay.batchSize = 100
+	}
+	return relay
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"internal-transfer-microservice/internal/config"
+	"internal-transfer-microservice/internal/domain/event"
+	"internal-transfer-microservice/internal/infrastructure/cache"
+	"internal-transfer-microservice/internal/infrastructure/publisher"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// MockOutboxRepository is a mock implementation of event.Repository
+type MockOutboxRepository struct {
+	events []event.Outbox
+	mu     sync.Mutex
+}
+
+func (m *MockOutboxRepository) add(eventType, aggregateId string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	outbox, _ := event.NewOutbox(eventType, event.AggregateAccount, aggregateId, aggregateId, event.AccountCreated{AccountId: aggregateId}, time.Now())
+	outbox.ID = uuid.New()
+	outbox.Sequence = uint64(len(m.events) + 1)
+	m.events = append(m.events, *outbox)
+}
+
+func (m *MockOutboxRepository) ListPendingEvents(ctx context.Context, limit int) ([]event.Outbox, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var pending []event.Outbox
+	for _, outbox := range m.events {
+		if outbox.SentAt == nil {
+			pending = append(pending, outbox)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Sequence < pending[j].Sequence })
+	if len(pending) > limit {
+		pending = pending[:limit]
+	}
+	return pending, nil
+}
+
+func (m *MockOutboxRepository) MarkEventSent(ctx context.Context, eventId uuid.UUID, sentAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.events {
+		if m.events[i].ID == eventId {
+			m.events[i].SentAt = &sentAt
+			m.events[i].Attempts++
+		}
+	}
+	return nil
+}
+
+func (m *MockOutboxRepository) RecordEventFailure(ctx context.Context, eventId uuid.UUID, reason string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.events {
+		if m.events[i].ID == eventId {
+			m.events[i].Attempts++
+			m.events[i].LastError = reason
+		}
+	}
+	return nil
+}
+
+// MockPublisher records published messages and fails for the aggregates listed in failFor
+type MockPublisher struct {
+	messages []publisher.Message
+	failFor  map[string]bool
+	mu       sync.Mutex
+}
+
+func (m *MockPublisher) Publish(ctx context.Context, topic string, message publisher.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.failFor[message.Key] {
+		return errors.New("broker unavailable")
+	}
+	m.messages = append(m.messages, message)
+	return nil
+}
+
+func (m *MockPublisher) Close() error {
+	return nil
+}
+
+func TestOutboxRelayPublishesInOrder(t *testing.T) {
+	// Setup
+	repo := &MockOutboxRepository{}
+	pub := &MockPublisher{failFor: map[string]bool{"acc-3": true}}
+	relay := NewOutboxRelay(repo, cache.NewMemoryCache(), pub, &config.Config{Events: config.EventsConfig{Topic: "events", RelayBatchSize: 2}})
+	ctx := context.Background()
+
+	for _, id := range []string{"acc-1", "acc-2", "acc-3", "acc-4"} {
+		repo.add(event.TypeAccountCreated, id)
+	}
+
+	// The failing event stops the relay, so the one after it waits
+	sent, err := relay.RelayPending(ctx)
+	if err == nil {
+		t.Errorf("Expected the publishing error")
+	}
+	if sent != 2 || len(pub.messages) != 2 {
+		t.Fatalf("Expected 2 events sent, got %d", sent)
+	}
+	if repo.events[2].Attempts != 1 || repo.events[2].LastError == "" {
+		t.Errorf("Expected the failed attempt to be recorded, got %+v", repo.events[2])
+	}
+
+	// Once the broker recovers the remaining events go out in sequence order
+	pub.failFor = nil
+	sent, err = relay.RelayPending(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if sent != 2 {
+		t.Errorf("Expected 2 events sent, got %d", sent)
+	}
+
+	for i, message := range pub.messages {
+		var envelope event.Envelope
+		if err := json.Unmarshal(message.Payload, &envelope); err != nil {
+			t.Fatalf("Expected an event envelope, got %v", err)
+		}
+		if envelope.Sequence != uint64(i+1) || envelope.SchemaVersion != event.SchemaVersion {
+			t.Errorf("Expected sequence %d at schema version %d, got %d at %d", i+1, event.SchemaVersion, envelope.Sequence, envelope.SchemaVersion)
+		}
+		if message.Headers["event_type"] != event.TypeAccountCreated {
+			t.Errorf("Expected event type header %s, got %s", event.TypeAccountCreated, message.Headers["event_type"])
+		}
+	}
+
+	pending, _ := repo.ListPendingEvents(ctx, 10)
+	if len(pending) != 0 {
+		t.Errorf("Expected the outbox to be drained, got %d pending", len(pending))
+	}
+}
+
+// blockingPublisher holds every publish until its context is done
+type blockingPublisher struct{}
+
+func (blockingPublisher) Publish(ctx context.Context, topic string, message publisher.Message) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (blockingPublisher) Close() error {
+	return nil
+}
+
+func TestOutboxRelayStopsBeforeTheLockExpires(t *testing.T) {
+	// Setup
+	repo := &MockOutboxRepository{}
+	cache := cache.NewMemoryCache()
+	relay := NewOutboxRelay(repo, cache, blockingPublisher{}, &config.Config{}).(*OutboxRelayImpl)
+	relay.lockTTL, relay.window = 100*time.Millisecond, 50*time.Millisecond
+	ctx := context.Background()
+	repo.add(event.TypeAccountCreated, "acc-1")
+
+	// Test case: a publish still running when the lock is about to expire is cut off, and the lock given back
+	start := time.Now()
+	if _, err := relay.RelayPending(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the publish to be cut off, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= relay.lockTTL {
+		t.Errorf("Expected the relay to stop before its lock expires, took %s", elapsed)
+	}
+	if acquired, _ := cache.Lock(ctx, OutboxRelayLockKey, time.Second); !acquired {
+		t.Errorf("Expected the relay lock to be released")
+	}
+}
+
+func TestOutboxRelayToFileSink(t *testing.T) {
+	// Setup
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	cfg := &config.Config{Events: config.EventsConfig{Topic: "events", File: config.FileSinkConfig{Path: path}}}
+	sink, err := publisher.NewFilePublisher(cfg)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	repo := &MockOutboxRepository{}
+	relay := NewOutboxRelay(repo, cache.NewMemoryCache(), sink, cfg)
+
+	repo.add(event.TypeAccountCreated, "acc-1")
+	repo.add(event.TypeAccountCreated, "acc-2")
+	if _, err := relay.RelayPending(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	sink.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected the event file, got %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(lines))
+	}
+	for i, line := range lines {
+		var record struct {
+			Topic   string         `json:"topic"`
+			Key     string         `json:"key"`
+			Payload event.Envelope `json:"payload"`
+		}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Expected a JSON line, got %v", err)
+		}
+		if record.Topic != "events" || record.Key != record.Payload.PartitionKey || record.Payload.Sequence != uint64(i+1) {
+			t.Errorf("Expected event %d on topic events keyed by its account, got %+v", i+1, record)
+		}
+	}
+}
+
+func TestPartitionFor(t *testing.T) {
+	if publisher.PartitionFor("acc-1", 1) != 0 {
+		t.Errorf("Expected a single partition to always be 0")
+	}
+	for _, key := range []string{"acc-1", "acc-2", "acc-3"} {
+		partition := publisher.PartitionFor(key, 8)
+		if partition < 0 || partition >= 8 {
+			t.Errorf("Expected partition of %s within [0, 8), got %d", key, partition)
+		}
+		if publisher.PartitionFor(key, 8) != partition {
+			t.Errorf("Expected the partition of %s to be stable", key)
+		}
+	}
+}
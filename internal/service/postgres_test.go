@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"internal-transfer-microservice/internal/config"
+	"internal-transfer-microservice/internal/domain/migration"
+	"internal-transfer-microservice/internal/infrastructure/db"
+	"internal-transfer-microservice/internal/repository"
+	"internal-transfer-microservice/migrations"
+	"os"
+	"testing"
+)
+
+// newTestPostgresDB connects to the empty Postgres database at $TEST_POSTGRES_DSN with a migrator for it,
+// skipping the test when it is not set. Every table is dropped when the test ends.
+func newTestPostgresDB(t *testing.T) (db.Database, migration.Service) {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+	cfg := &config.Config{}
+	cfg.Database.Driver = db.DriverPostgres
+	cfg.Database.DSN = dsn
+	cfg.Database.LogLevel = "silent"
+	database, err := db.NewDatabase(cfg)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	files, err := migrations.For(db.DriverPostgres)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	scripts, err := migration.Load(files)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	migrator := NewMigrator(repository.NewMigrationRepo(database), scripts)
+	t.Cleanup(func() {
+		migrator.To(context.Background(), 0)
+		database.GetConnection().Exec("DROP TABLE IF EXISTS accounts, schema_migrations")
+		database.Close()
+	})
+	return database, migrator
+}
+
+func TestPostgresMigrationsAdoptAutoMigrateSchema(t *testing.T) {
+	// Setup
+	database, migrator := newTestPostgresDB(t)
+	ctx := context.Background()
+	conn := database.GetConnection()
+
+	// the accounts table AutoMigrate created before versioned migrations, with an account in it
+	for _, statement := range []string{
+		"CREATE TABLE accounts (id uuid PRIMARY KEY, created_at timestamptz, updated_at timestamptz, account_id text, balance decimal)",
+		"CREATE UNIQUE INDEX idx_accounts_account_id ON accounts (account_id)",
+		"INSERT INTO accounts (id, account_id, balance) VALUES ('8f14e45f-ceea-467e-a5e1-8a7c3e4f1d2b', 'acc-1', 100)",
+	} {
+		if err := conn.Exec(statement).Error; err != nil {
+			t.Fatalf("Expected the baseline schema, got %v", err)
+		}
+	}
+
+	// Test case: every migration applies on top of the baseline schema
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, column := range []string{"holder_name", "account_type", "tier", "overdraft_limit", "frozen", "version", "owner_id"} {
+		if !conn.Migrator().HasColumn("accounts", column) {
+			t.Errorf("Expected the accounts table to have column %s", column)
+		}
+	}
+
+	// Test case: existing accounts keep their balance and get the defaults of the new columns
+	acc, err := repository.NewAccountRepo(database).GetAccount(ctx, "acc-1")
+	if err != nil {
+		t.Fatalf("Expected acc-1, got %v", err)
+	}
+	if acc.Balance != 100 || acc.Tier != "standard" || acc.OverdraftLimit != 0 || acc.Frozen {
+		t.Errorf("Expected acc-1 with a balance of 100 and default settings, got %+v", acc)
+	}
+}
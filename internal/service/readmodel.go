@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"internal-transfer-microservice/internal/config"
+	"internal-transfer-microservice/internal/domain/readmodel"
+	"internal-transfer-microservice/internal/infrastructure/cache"
+	"internal-transfer-microservice/pkg/logger"
+)
+
+// ReadModelLockKey is held while the read model is projected or rebuilt, so events are applied once and in order
+const ReadModelLockKey = "readmodel:project"
+
+const (
+	readModelProjectLockTTL = 30 * time.Second
+	// readModelProjectWindow is how long a projector starts projections for. Projections still running when
+	// the lock is about to expire are cut off, so a projector never applies events while another holds the lock.
+	readModelProjectWindow  = 20 * time.Second
+	readModelRebuildLockTTL = 10 * time.Minute
+	readModelRebuildWait    = time.Minute
+)
+
+var ErrReadModelBusy = errors.New("read model is being projected or rebuilt by another process")
+
+type ReadModelProjectorImpl struct {
+	repo      readmodel.Repository
+	cache     cache.Cache
+	interval  time.Duration
+	batchSize int
+	lockTTL   time.Duration
+	window    time.Duration
+}
+
+func (r *ReadModelProjectorImpl) ProjectPending(ctx context.Context) (int, error) {
+	start := time.Now()
+	acquired, err := r.cache.Lock(ctx, ReadModelLockKey, r.lockTTL)
+	if err != nil {
+		return 0, err
+	}
+	if !acquired {
+		// another projector or a rebuild is running
+		return 0, nil
+	}
+	defer r.cache.Release(ctx, ReadModelLockKey)
+	projectCtx, cancel := context.WithDeadline(ctx, start.Add((r.window+r.lockTTL)/2))
+	defer cancel()
+
+	applied := 0
+	for {
+		events, err := r.repo.ListUnprojectedEvents(projectCtx, r.batchSize)
+		if err != nil {
+			return applied, err
+		}
+
+		for i := range events {
+			if time.Since(start) > r.window {
+				// the rest are left to the next pass, under a fresh lock
+				return applied, nil
+			}
+			projection, err := readmodel.Project(&events[i])
+			if err != nil {
+				// later events wait for this one, so summaries never skip a change
+				return applied, err
+			}
+			if err := r.repo.ApplyProjection(projectCtx, projection, time.Now().UTC()); err != nil {
+				return applied, err
+			}
+			applied++
+		}
+
+		if len(events) < r.batchSize {
+			return applied, nil
+		}
+	}
+}
+
+func (r *ReadModelProjectorImpl) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		applied, err := r.ProjectPending(ctx)
+		if err != nil && ctx.Err() == nil {
+			logger.Errorf("Failed to project events into the read model: %v", err)
+		}
+		if applied > 0 {
+			logger.Infof("Projected %d events into the read model", applied)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *ReadModelProjectorImpl) Rebuild(ctx context.Context) (int, error) {
+	deadline := time.Now().Add(readModelRebuildWait)
+	for {
+		acquired, err := r.cache.Lock(ctx, ReadModelLockKey, readModelRebuildLockTTL)
+		if err != nil {
+			return 0, err
+		}
+		if acquired {
+			break
+		}
+		if time.Now().After(deadline) {
+			return 0, ErrReadModelBusy
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(r.interval):
+		}
+	}
+	defer r.cache.Release(ctx, ReadModelLockKey)
+
+	now := time.Now().UTC()
+	since := readmodel.Day(now.AddDate(0, 0, 1-readmodel.FlowWindowDays))
+	return r.repo.Rebuild(ctx, since, now)
+}
+
+func NewReadModelProjector(repo readmodel.Repository, cache cache.Cache, cfg *config.Config) readmodel.Projector {
+	projector := &ReadModelProjectorImpl{
+		repo:      repo,
+		cache:     cache,
+		interval:  cfg.GetReadModelProjectInterval(),
+		batchSize: cfg.GetReadModelBatchSize(),
+		lockTTL:   readModelProjectLockTTL,
+		window:    readModelProjectWindow,
+	}
+	if projector.interval <= 0 {
+		projector.interval = time.Second
+	}
+	if projector.batchSize <= 0 {
+		projector.batchSize = 100
+	}
+	return projector
+}
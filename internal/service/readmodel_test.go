@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"internal-transfer-microservice/internal/config"
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/event"
+	"internal-transfer-microservice/internal/domain/readmodel"
+	"internal-transfer-microservice/internal/infrastructure/cache"
+	"internal-transfer-microservice/internal/repository"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// MockReadModelRepository is a mock implementation of readmodel.Repository over an in-memory outbox
+type MockReadModelRepository struct {
+	events    []event.Outbox
+	summaries map[string]*readmodel.AccountSummary
+	flows     map[string]*readmodel.DailyFlow
+	mu        sync.Mutex
+}
+
+func NewMockReadModelRepository() *MockReadModelRepository {
+	return &MockReadModelRepository{
+		summaries: make(map[string]*readmodel.AccountSummary),
+		flows:     make(map[string]*readmodel.DailyFlow),
+	}
+}
+
+func (m *MockReadModelRepository) record(t *testing.T, eventType, aggregateId string, payload interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	outbox, err := event.NewOutbox(eventType, event.AggregateAccount, aggregateId, aggregateId, payload, time.Now().UTC())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	outbox.ID = uuid.New()
+	outbox.Sequence = uint64(len(m.events) + 1)
+	m.events = append(m.events, *outbox)
+}
+
+func (m *MockReadModelRepository) GetSummary(ctx context.Context, accountId string) (*readmodel.AccountSummary, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	summary, exists := m.summaries[accountId]
+	if !exists {
+		return nil, readmodel.ErrSummaryNotFound
+	}
+	copied := *summary
+	return &copied, nil
+}
+
+func (m *MockReadModelRepository) GetFlowTotals(ctx context.Context, accountId string, since string) (float64, float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	inflow, outflow := 0.0, 0.0
+	for _, flow := range m.flows {
+		if flow.AccountId == accountId && flow.Day >= since {
+			inflow += flow.Inflow
+			outflow += flow.Outflow
+		}
+	}
+	return inflow, outflow, nil
+}
+
+func (m *MockReadModelRepository) ListUnprojectedEvents(ctx context.Context, limit int) ([]event.Outbox, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var pending []event.Outbox
+	for _, outbox := range m.events {
+		if outbox.ProjectedAt == nil {
+			pending = append(pending, outbox)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Sequence < pending[j].Sequence })
+	if len(pending) > limit {
+		pending = pending[:limit]
+	}
+	return pending, nil
+}
+
+func (m *MockReadModelRepository) ApplyProjection(ctx context.Context, projection *readmodel.Projection, projectedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, outbox := range m.events {
+		if outbox.ID == projection.EventId && outbox.ProjectedAt != nil {
+			return nil
+		}
+	}
+
+	if projection.Opened != nil {
+		if _, exists := m.summaries[projection.Opened.AccountId]; !exists {
+			opened := *projection.Opened
+			opened.UpdatedAt = projectedAt
+			m.summaries[opened.AccountId] = &opened
+		}
+	}
+	touched := make(map[string]*readmodel.AccountSummary)
+	for _, accountId := range projection.AccountIds() {
+		if summary, exists := m.summaries[accountId]; exists {
+			touched[accountId] = summary
+		}
+	}
+	projection.Apply(touched, projectedAt)
+	if projection.Transfer != nil {
+		for _, flow := range projection.Transfer.Flows() {
+			key := flow.AccountId + "/" + flow.Day
+			if existing, exists := m.flows[key]; exists {
+				existing.Inflow += flow.Inflow
+				existing.Outflow += flow.Outflow
+				continue
+			}
+			added := flow
+			m.flows[key] = &added
+		}
+	}
+	for i := range m.events {
+		if m.events[i].ID == projection.EventId {
+			m.events[i].ProjectedAt = &projectedAt
+		}
+	}
+	return nil
+}
+
+func (m *MockReadModelRepository) Rebuild(ctx context.Context, since string, rebuiltAt time.Time) (int, error) {
+	return 0, nil
+}
+
+func TestReadModelProjection(t *testing.T) {
+	// Setup
+	repo := NewMockReadModelRepository()
+	projector := NewReadModelProjector(repo, cache.NewMemoryCache(), &config.Config{ReadModel: config.ReadModelConfig{BatchSize: 2}})
+	ctx := context.Background()
+	executedAt := time.Now().UTC()
+
+	repo.record(t, event.TypeAccountCreated, "acc-1", event.AccountCreated{AccountId: "acc-1", AccountType: "standard", Tier: "standard", InitialBalance: 100})
+	repo.record(t, event.TypeAccountCreated, "acc-2", event.AccountCreated{AccountId: "acc-2", AccountType: "standard", Tier: "standard", InitialBalance: 50})
+	repo.record(t, event.TypeTransferCompleted, "txn-1", event.TransferCompleted{
+		TransferId:           "txn-1",
+		SourceAccountId:      "acc-1",
+		DestinationAccountId: "acc-2",
+		Amount:               30,
+		Fee:                  1,
+		ExecutedAt:           &executedAt,
+		Balances:             map[string]float64{"acc-1": 69, "acc-2": 80},
+	})
+	repo.record(t, event.TypeTransferFailed, "txn-2", event.TransferFailed{TransferId: "txn-2", SourceAccountId: "acc-2", DestinationAccountId: "acc-1", Amount: 500})
+	repo.record(t, event.TypeOverdraftLimitChanged, "acc-1", event.OverdraftLimitChanged{AccountId: "acc-1", Limit: 200, Version: 1})
+	repo.record(t, event.TypeAccountFrozen, "acc-2", event.AccountFreezeChanged{AccountId: "acc-2", ChangedBy: "oncall"})
+
+	// Every event is applied in order, including those the read model ignores
+	applied, err := projector.ProjectPending(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if applied != 6 {
+		t.Errorf("Expected 6 events projected, got %d", applied)
+	}
+	if again, _ := projector.ProjectPending(ctx); again != 0 {
+		t.Errorf("Expected nothing left to project, got %d", again)
+	}
+
+	source := repo.summaries["acc-1"]
+	if source.Balance != 69 || source.BalanceSequence != 3 {
+		t.Errorf("Expected balance 69 from event 3, got %.2f from event %d", source.Balance, source.BalanceSequence)
+	}
+	if source.OverdraftLimit != 200 || source.OverdraftLimitVersion != 1 {
+		t.Errorf("Expected overdraft limit 200 at version 1, got %.2f at version %d", source.OverdraftLimit, source.OverdraftLimitVersion)
+	}
+	if source.LastTransferId != "txn-1" || source.LastTransferDirection != readmodel.DirectionOut || source.LastTransferCounterparty != "acc-2" {
+		t.Errorf("Expected last transfer txn-1 out to acc-2, got %s %s %s", source.LastTransferId, source.LastTransferDirection, source.LastTransferCounterparty)
+	}
+	destination := repo.summaries["acc-2"]
+	if destination.Balance != 80 || destination.LastTransferDirection != readmodel.DirectionIn {
+		t.Errorf("Expected balance 80 after an incoming transfer, got %.2f %s", destination.Balance, destination.LastTransferDirection)
+	}
+	if !destination.Frozen || source.Frozen {
+		t.Errorf("Expected only acc-2 to be frozen, got %t and %t", source.Frozen, destination.Frozen)
+	}
+
+	// A late event does not overwrite a newer balance
+	stale := &readmodel.Projection{Sequence: 2, Balances: map[string]float64{"acc-1": 100}}
+	stale.Apply(map[string]*readmodel.AccountSummary{"acc-1": source}, time.Now())
+	if source.Balance != 69 {
+		t.Errorf("Expected balance 69 to survive a stale event, got %.2f", source.Balance)
+	}
+
+	// Account reads are served from the read model with the account's recent activity
+	service := NewAccountService(repository.NewMemoryAccountRepo(), cache.NewMemoryCache(), WithReadModel(repo))
+	response, err := service.GetAccount(ctx, "acc-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Balance != 69 || response.AvailableBalance != 269 {
+		t.Errorf("Expected balance 69 and 269 available, got %.2f and %.2f", response.Balance, response.AvailableBalance)
+	}
+	if response.Activity == nil || response.Activity.LastTransfer == nil {
+		t.Fatalf("Expected the account activity")
+	}
+	if response.Activity.Outflow30d != 31 || response.Activity.Inflow30d != 0 {
+		t.Errorf("Expected 31 out and nothing in, got %.2f out and %.2f in", response.Activity.Outflow30d, response.Activity.Inflow30d)
+	}
+	if response.Activity.LastTransfer.TransferId != "txn-1" {
+		t.Errorf("Expected last transfer txn-1, got %s", response.Activity.LastTransfer.TransferId)
+	}
+}
+
+func TestGetAccountBeforeProjection(t *testing.T) {
+	// Setup
+	repo := repository.NewMemoryAccountRepo()
+	service := NewAccountService(repo, cache.NewMemoryCache(), WithReadModel(NewMockReadModelRepository()))
+	ctx := context.Background()
+
+	repo.CreateAccount(ctx, &account.Model{AccountId: "acc-1", Balance: 100})
+
+	// Accounts the projector has not reached yet are read from the primary
+	response, err := service.GetAccount(ctx, "acc-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Balance != 100 {
+		t.Errorf("Expected balance 100, got %.2f", response.Balance)
+	}
+	if response.Activity != nil {
+		t.Errorf("Expected no activity from the primary, got %+v", response.Activity)
+	}
+
+	if _, err := service.GetAccount(ctx, "missing"); err == nil {
+		t.Errorf("Expected an error for a missing account")
+	}
+}
+
+func TestReadModelProjectsEventsOnce(t *testing.T) {
+	// Setup
+	database, migrator := newTestSQLiteDB(t)
+	ctx := context.Background()
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	repo := repository.NewReadModelRepo(database)
+	executedAt := time.Now().UTC()
+	outbox, _ := event.NewOutbox(event.TypeTransferCompleted, event.AggregateTransfer, "txn-1", "acc-1", event.TransferCompleted{TransferId: "txn-1"}, executedAt)
+	if err := database.GetConnection().Create(outbox).Error; err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	projection := &readmodel.Projection{EventId: outbox.ID, Transfer: &readmodel.TransferActivity{
+		TransferId: "txn-1", SourceAccountId: "acc-1", DestinationAccountId: "acc-2", Amount: 30, ExecutedAt: executedAt,
+	}}
+
+	// Test case: a projection applied by two projectors adds the transfer to the daily flows once
+	for i := 0; i < 2; i++ {
+		if err := repo.ApplyProjection(ctx, projection, time.Now().UTC()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if inflow, _, _ := repo.GetFlowTotals(ctx, "acc-2", readmodel.Day(executedAt)); inflow != 30 {
+		t.Errorf("Expected an inflow of 30, got %.2f", inflow)
+	}
+	if pending, _ := repo.ListUnprojectedEvents(ctx, 10); len(pending) != 0 {
+		t.Errorf("Expected the event to be projected, got %d pending", len(pending))
+	}
+}
+
+func TestReadModelProjectionStopsBeforeTheLockExpires(t *testing.T) {
+	// Setup
+	repo := NewMockReadModelRepository()
+	projector := NewReadModelProjector(repo, cache.NewMemoryCache(), &config.Config{}).(*ReadModelProjectorImpl)
+	ctx := context.Background()
+	for _, id := range []string{"acc-1", "acc-2"} {
+		repo.record(t, event.TypeAccountCreated, id, event.AccountCreated{AccountId: id})
+	}
+
+	// Test case: no projection starts once the window has passed, and the next pass picks the events up
+	window := projector.window
+	projector.window = 0
+	if applied, err := projector.ProjectPending(ctx); err != nil || applied != 0 {
+		t.Errorf("Expected no projection after the window, got %d (%v)", applied, err)
+	}
+	projector.window = window
+	if applied, err := projector.ProjectPending(ctx); err != nil || applied != 2 {
+		t.Errorf("Expected 2 events projected, got %d (%v)", applied, err)
+	}
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"internal-transfer-microservice/internal/config"
+	"internal-transfer-microservice/internal/domain/risk"
+	"internal-transfer-microservice/pkg/logger"
+)
+
+// riskRule is a configured rule with its blocklist indexed for lookups
+type riskRule struct {
+	config.RiskRuleConfig
+	blocked map[string]bool
+}
+
+type RulesEngineImpl struct {
+	rules []riskRule
+}
+
+func (r *RulesEngineImpl) Evaluate(ctx context.Context, transfer risk.TransferContext) (*risk.Decision, error) {
+	decision := &risk.Decision{
+		Outcome:     risk.OutcomeAllow,
+		EvaluatedAt: time.Now().UTC(),
+	}
+
+	for _, rule := range r.rules {
+		if !rule.matches(transfer) {
+			continue
+		}
+		decision.TriggeredRules = append(decision.TriggeredRules, rule.Name)
+		if risk.Severity(rule.Action) > risk.Severity(decision.Outcome) {
+			decision.Outcome = rule.Action
+		}
+	}
+	return decision, nil
+}
+
+func (r *riskRule) matches(transfer risk.TransferContext) bool {
+	switch r.Type {
+	case risk.RuleAmountAbove:
+		return transfer.Amount > r.Threshold
+	case risk.RuleNewAccount:
+		isNew := transfer.SourceAccountAge < r.MaxAge || transfer.DestinationAccountAge < r.MaxAge
+		return isNew && transfer.Amount > r.Threshold
+	case risk.RuleVelocityCount:
+		return float64(transfer.RecentCount+1) > r.Threshold
+	case risk.RuleVelocityAmount:
+		return transfer.RecentAmount+transfer.Amount > r.Threshold
+	case risk.RuleBlocklist:
+		return r.blocked[transfer.SourceAccountId] || r.blocked[transfer.DestinationAccountId]
+	default:
+		return false
+	}
+}
+
+func NewRulesEngine(cfg *config.Config) risk.RiskEvaluator {
+	engine := &RulesEngineImpl{}
+	for _, ruleConfig := range cfg.GetRiskRules() {
+		rule := riskRule{RiskRuleConfig: ruleConfig}
+		switch rule.Type {
+		case risk.RuleAmountAbove, risk.RuleNewAccount, risk.RuleVelocityCount, risk.RuleVelocityAmount, risk.RuleBlocklist:
+		default:
+			logger.Warnf("Ignoring risk rule %q with unknown type %q", rule.Name, rule.Type)
+			continue
+		}
+		if rule.Name == "" {
+			rule.Name = rule.Type
+		}
+		if rule.Action != risk.OutcomeDeny && rule.Action != risk.OutcomeReview {
+			logger.Warnf("Risk rule %q has unknown action %q, sending matches to review", rule.Name, rule.Action)
+			rule.Action = risk.OutcomeReview
+		}
+		rule.blocked = make(map[string]bool, len(rule.Accounts))
+		for _, accountId := range rule.Accounts {
+			rule.blocked[accountId] = true
+		}
+		engine.rules = append(engine.rules, rule)
+	}
+	return engine
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"internal-transfer-microservice/internal/config"
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/risk"
+	"internal-transfer-microservice/internal/domain/transfer"
+	"internal-transfer-microservice/internal/infrastructure/cache"
+	"internal-transfer-microservice/internal/repository"
+	"testing"
+	"time"
+)
+
+func newTestRiskService(repo *repository.MemoryAccountRepoImpl) account.Service {
+	cfg := &config.Config{
+		Risk: config.RiskConfig{
+			VelocityWindow: time.Hour,
+			Rules: []config.RiskRuleConfig{
+				{Name: "large-amount", Type: risk.RuleAmountAbove, Threshold: 1000, Action: risk.OutcomeReview},
+				{Name: "new-account", Type: risk.RuleNewAccount, MaxAge: 72 * time.Hour, Threshold: 100, Action: risk.OutcomeReview},
+				{Name: "burst", Type: risk.RuleVelocityCount, Threshold: 2, Action: risk.OutcomeReview},
+				{Name: "blocked", Type: risk.RuleBlocklist, Accounts: []string{"mule"}, Action: risk.OutcomeDeny},
+			},
+		},
+	}
+	return NewAccountService(repo, cache.NewMemoryCache(), WithRiskEvaluator(NewRulesEngine(cfg), cfg.GetRiskVelocityWindow()))
+}
+
+func createAgedAccount(repo *repository.MemoryAccountRepoImpl, accountId string, balance float64, age time.Duration) {
+	createdAt := time.Now().Add(-age)
+	acc := &account.Model{AccountId: accountId, Balance: balance}
+	acc.CreatedAt = &createdAt
+	repo.CreateAccount(context.Background(), acc)
+}
+
+func TestRiskScreeningOutcomes(t *testing.T) {
+	// Setup
+	repo := repository.NewMemoryAccountRepo()
+	service := newTestRiskService(repo)
+	ctx := context.Background()
+
+	createAgedAccount(repo, "old", 10000.0, 365*24*time.Hour)
+	createAgedAccount(repo, "other", 0, 365*24*time.Hour)
+	createAgedAccount(repo, "fresh", 0, time.Hour)
+	createAgedAccount(repo, "mule", 0, 365*24*time.Hour)
+
+	tests := []struct {
+		name     string
+		dest     string
+		amount   float64
+		status   string
+		outcome  string
+		triggers []string
+	}{
+		{"allowed", "other", 50, transfer.StatusCompleted, risk.OutcomeAllow, nil},
+		{"large amount", "other", 5000, transfer.StatusPendingReview, risk.OutcomeReview, []string{"large-amount"}},
+		{"new destination", "fresh", 500, transfer.StatusPendingReview, risk.OutcomeReview, []string{"new-account"}},
+		{"blocklisted destination", "mule", 5000, transfer.StatusDenied, risk.OutcomeDeny, []string{"large-amount", "blocked"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			response, err := service.TxnAccount(ctx, "old", tc.dest, tc.amount)
+			if tc.status == transfer.StatusDenied && !errors.Is(err, ErrTransferDenied) {
+				t.Errorf("Expected ErrTransferDenied, got %v", err)
+			}
+			if response.Transfer == nil {
+				t.Fatalf("Expected the transfer record in the response, got %s (%v)", response.Message, err)
+			}
+			if response.Transfer.Status != tc.status {
+				t.Errorf("Expected status %s, got %s", tc.status, response.Transfer.Status)
+			}
+			decision := response.Transfer.RiskDecision
+			if decision == nil || decision.Outcome != tc.outcome {
+				t.Fatalf("Expected outcome %s, got %+v", tc.outcome, decision)
+			}
+			if len(decision.TriggeredRules) != len(tc.triggers) {
+				t.Errorf("Expected rules %v, got %v", tc.triggers, decision.TriggeredRules)
+			}
+		})
+	}
+
+	// Only the allowed transfer moved money; the others are recorded with their decision
+	old, _ := repo.GetAccount(ctx, "old")
+	if old.Balance != 9950.0 {
+		t.Errorf("Expected source balance 9950.00, got %.2f", old.Balance)
+	}
+	history, _ := service.GetTransferHistory(ctx, "old")
+	if len(history) != len(tests) {
+		t.Errorf("Expected %d recorded transfers, got %d", len(tests), len(history))
+	}
+}
+
+func TestRiskScreeningVelocity(t *testing.T) {
+	// Setup
+	repo := repository.NewMemoryAccountRepo()
+	service := newTestRiskService(repo)
+	ctx := context.Background()
+
+	createAgedAccount(repo, "src", 1000.0, 365*24*time.Hour)
+	createAgedAccount(repo, "dst", 0, 365*24*time.Hour)
+
+	// Test case: the third transfer within the window goes to review
+	for i := 0; i < 2; i++ {
+		response, err := service.TxnAccount(ctx, "src", "dst", 10.0)
+		if err != nil || response.Transfer.Status != transfer.StatusCompleted {
+			t.Fatalf("Expected transfer %d to complete, got %s (%v)", i+1, response.Message, err)
+		}
+	}
+	response, _ := service.TxnAccount(ctx, "src", "dst", 10.0)
+	if response.Transfer == nil || response.Transfer.Status != transfer.StatusPendingReview {
+		t.Errorf("Expected the third transfer to be held for review, got %s", response.Message)
+	}
+}
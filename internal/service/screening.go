@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"internal-transfer-microservice/internal/auth"
+	"internal-transfer-microservice/internal/config"
+	"internal-transfer-microservice/internal/domain/access"
+	"internal-transfer-microservice/internal/domain/screening"
+	"internal-transfer-microservice/internal/infrastructure/cache"
+	"internal-transfer-microservice/internal/infrastructure/db"
+)
+
+// WatchlistVersionKey is the cache key holding the version of the stored watchlist, changed by every reload
+const WatchlistVersionKey = "screening:watchlist:version"
+
+// ScreeningHitsLimit caps the number of hits returned by ListHits
+const ScreeningHitsLimit = 100
+
+var (
+	ErrWatchlistPathRequired = errors.New("watchlist path is not configured")
+	ErrHitNotFound           = errors.New("screening hit not found")
+	ErrHitAlreadyReviewed    = errors.New("screening hit already reviewed")
+	ErrInvalidHitStatus      = errors.New("hit status must be confirmed or cleared")
+)
+
+type ScreeningServiceImpl struct {
+	accessGuard
+	repo           screening.Repository
+	cache          cache.Cache
+	flagThreshold  float64
+	blockThreshold float64
+
+	mu      sync.RWMutex
+	names   []watchlistName
+	version string
+	loaded  bool
+}
+
+func (s *ScreeningServiceImpl) Screen(ctx context.Context, transferId uuid.UUID, parties ...screening.Party) ([]screening.Match, error) {
+	names, err := s.watchlist(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []screening.Match
+	for _, party := range parties {
+		if party.Name == "" {
+			continue
+		}
+		screened := normalizeName(party.Name)
+		// one match per entry, scored by its closest name or alias
+		byEntry := make(map[*screening.WatchlistEntry]int)
+		for _, candidate := range names {
+			score := math.Round(jaroWinkler(screened, candidate.normalized)*1000) / 1000
+			if score < s.flagThreshold {
+				continue
+			}
+			i, seen := byEntry[candidate.entry]
+			if seen && matches[i].Score >= score {
+				continue
+			}
+			match := screening.Match{
+				AccountId:    party.AccountId,
+				ScreenedName: party.Name,
+				Entry:        candidate.entry,
+				MatchedName:  candidate.name,
+				Score:        score,
+				Action:       screening.ActionFlag,
+			}
+			if score >= s.blockThreshold {
+				match.Action = screening.ActionBlock
+			}
+			if seen {
+				matches[i] = match
+				continue
+			}
+			byEntry[candidate.entry] = len(matches)
+			matches = append(matches, match)
+		}
+	}
+
+	hits := make([]screening.Hit, 0, len(matches))
+	for _, match := range matches {
+		hits = append(hits, screening.Hit{
+			TransferId:   transferId,
+			AccountId:    match.AccountId,
+			ScreenedName: match.ScreenedName,
+			EntryId:      match.Entry.EntryId,
+			MatchedName:  match.MatchedName,
+			Programs:     match.Entry.Programs,
+			Score:        match.Score,
+			Action:       match.Action,
+			Status:       screening.HitStatusOpen,
+		})
+	}
+	if err := s.repo.CreateHits(ctx, hits); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// watchlist returns the prepared watchlist, reloading it from the repository once a reload stored a new version
+func (s *ScreeningServiceImpl) watchlist(ctx context.Context) ([]watchlistName, error) {
+	// a version missing from the cache keeps whatever list is already loaded
+	version, err := s.cache.Get(ctx, WatchlistVersionKey)
+	if err != nil {
+		version = ""
+	}
+
+	s.mu.RLock()
+	names, current, loaded := s.names, s.version, s.loaded
+	s.mu.RUnlock()
+	if loaded && (version == "" || version == current) {
+		return names, nil
+	}
+
+	entries, err := s.repo.ListWatchlist(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names = prepareWatchlist(entries)
+
+	s.mu.Lock()
+	s.names, s.version, s.loaded = names, version, true
+	s.mu.Unlock()
+	return names, nil
+}
+
+func (s *ScreeningServiceImpl) ReloadWatchlist(ctx context.Context, path string) (*screening.ReloadSummary, error) {
+	if path == "" {
+		return nil, ErrWatchlistPathRequired
+	}
+	entries, err := loadWatchlist(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplaceWatchlist(ctx, entries); err != nil {
+		return nil, err
+	}
+
+	// running servers notice the new version on their next screening and reload the stored list
+	version := strconv.FormatInt(time.Now().UTC().UnixNano(), 10)
+	if err := s.cache.Set(ctx, WatchlistVersionKey, version, 0); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.names, s.version, s.loaded = prepareWatchlist(entries), version, true
+	s.mu.Unlock()
+
+	return &screening.ReloadSummary{Source: path, Entries: len(entries), Version: version}, nil
+}
+
+func (s *ScreeningServiceImpl) ListHits(ctx context.Context, status string) ([]*screening.HitResponse, error) {
+	if err := s.authorize(ctx, access.ActionReview, nil); err != nil {
+		return nil, err
+	}
+	hits, err := s.repo.ListHits(ctx, status, ScreeningHitsLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*screening.HitResponse, 0, len(hits))
+	for i := range hits {
+		responses = append(responses, hits[i].ToResponse())
+	}
+	return responses, nil
+}
+
+func (s *ScreeningServiceImpl) ReviewHit(ctx context.Context, hitId string, req screening.ReviewHitRequest) (*screening.HitResponse, error) {
+	reviewer := auth.SubjectFromContext(ctx)
+	if reviewer == "" {
+		return nil, ErrOperatorRequired
+	}
+	if err := s.authorize(ctx, access.ActionReview, nil); err != nil {
+		return nil, err
+	}
+	if req.Status != screening.HitStatusConfirmed && req.Status != screening.HitStatusCleared {
+		return nil, ErrInvalidHitStatus
+	}
+
+	id, err := uuid.Parse(hitId)
+	if err != nil {
+		return nil, ErrHitNotFound
+	}
+	// a hit read from a lagging replica could be reviewed twice
+	ctx = db.WithPrimary(ctx)
+	hit, err := s.repo.GetHit(ctx, id)
+	if err != nil {
+		return nil, ErrHitNotFound
+	}
+	if hit.Status != screening.HitStatusOpen {
+		return nil, ErrHitAlreadyReviewed
+	}
+
+	now := time.Now().UTC()
+	hit.Status = req.Status
+	hit.ReviewedBy = reviewer
+	hit.ReviewedAt = &now
+	hit.ReviewNote = req.Note
+	if err := s.repo.UpdateHit(ctx, hit); err != nil {
+		return nil, err
+	}
+	return hit.ToResponse(), nil
+}
+
+// NewScreeningService creates the watchlist screening service; callers listing and reviewing hits must be
+// allowed to review by policy, unless it is nil
+func NewScreeningService(repo screening.Repository, cache cache.Cache, cfg *config.Config, policy access.Policy) screening.Service {
+	return &ScreeningServiceImpl{
+		accessGuard:    accessGuard{policy: policy},
+		repo:           repo,
+		cache:          cache,
+		flagThreshold:  cfg.GetScreeningFlagThreshold(),
+		blockThreshold: cfg.GetScreeningBlockThreshold(),
+	}
+}
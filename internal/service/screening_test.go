@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"internal-transfer-microservice/internal/config"
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/screening"
+	"internal-transfer-microservice/internal/domain/transfer"
+	"internal-transfer-microservice/internal/infrastructure/cache"
+	"internal-transfer-microservice/internal/repository"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// MockScreeningRepository is a mock implementation of screening.Repository
+type MockScreeningRepository struct {
+	entries []screening.WatchlistEntry
+	hits    []screening.Hit
+	mu      sync.Mutex
+}
+
+func (m *MockScreeningRepository) ReplaceWatchlist(ctx context.Context, entries []screening.WatchlistEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries = append([]screening.WatchlistEntry(nil), entries...)
+	return nil
+}
+
+func (m *MockScreeningRepository) ListWatchlist(ctx context.Context) ([]screening.WatchlistEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]screening.WatchlistEntry(nil), m.entries...), nil
+}
+
+func (m *MockScreeningRepository) CreateHits(ctx context.Context, hits []screening.Hit) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, hit := range hits {
+		hit.ID = uuid.New()
+		m.hits = append(m.hits, hit)
+	}
+	return nil
+}
+
+func (m *MockScreeningRepository) ListHits(ctx context.Context, status string, limit int) ([]screening.Hit, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var hits []screening.Hit
+	for _, hit := range m.hits {
+		if (status == "" || hit.Status == status) && len(hits) < limit {
+			hits = append(hits, hit)
+		}
+	}
+	return hits, nil
+}
+
+func (m *MockScreeningRepository) GetHit(ctx context.Context, hitId uuid.UUID) (*screening.Hit, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, hit := range m.hits {
+		if hit.ID == hitId {
+			return &hit, nil
+		}
+	}
+	return nil, errors.New("hit not found")
+}
+
+func (m *MockScreeningRepository) UpdateHit(ctx context.Context, hit *screening.Hit) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.hits {
+		if m.hits[i].ID == hit.ID {
+			m.hits[i] = *hit
+			return nil
+		}
+	}
+	return errors.New("hit not found")
+}
+
+const testWatchlistCSV = `36,"AEROCARIBBEAN AIRLINES","-0-","CUBA",-0-,-0-,-0-,-0-,-0-,-0-,-0-,"Havana, Cuba."
+173,"ANGLO-CARIBBEAN CO., LTD.","-0-","CUBA",-0-,-0-,-0-,-0-,-0-,-0-,-0-,"a.k.a. 'AC TRADING'."
+2674,"PETROV, Ivan Sergeyevich","individual","SDGT] [IRGC",-0-,-0-,-0-,-0-,-0-,-0-,-0-,"DOB 1970; a.k.a. 'PETROFF, Ivan'; a.k.a. 'Johnny P'."
+`
+
+const testWatchlistXML = `<?xml version="1.0" standalone="yes"?>
+<sdnList xmlns="http://tempuri.org/sdnList.xsd">
+  <sdnEntry>
+    <uid>2674</uid>
+    <firstName>Ivan Sergeyevich</firstName>
+    <lastName>PETROV</lastName>
+    <sdnType>Individual</sdnType>
+    <programList><program>SDGT</program><program>IRGC</program></programList>
+    <akaList><aka><uid>11</uid><type>a.k.a.</type><firstName>Ivan</firstName><lastName>PETROFF</lastName></aka></akaList>
+  </sdnEntry>
+</sdnList>
+`
+
+func newTestScreeningService(t *testing.T) (screening.Service, *MockScreeningRepository, *cache.MemoryCache) {
+	repo := &MockScreeningRepository{}
+	watchlistCache := cache.NewMemoryCache()
+	cfg := &config.Config{
+		Screening: config.ScreeningConfig{Enabled: true, FlagThreshold: 0.85, BlockThreshold: 0.95},
+	}
+	service := NewScreeningService(repo, watchlistCache, cfg, nil)
+
+	path := filepath.Join(t.TempDir(), "sdn.csv")
+	if err := os.WriteFile(path, []byte(testWatchlistCSV), 0o600); err != nil {
+		t.Fatalf("Failed to write watchlist: %v", err)
+	}
+	if _, err := service.ReloadWatchlist(context.Background(), path); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return service, repo, watchlistCache
+}
+
+func TestWatchlistParsing(t *testing.T) {
+	csvEntries, err := parseWatchlistCSV(strings.NewReader(testWatchlistCSV), "sdn.csv")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(csvEntries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(csvEntries))
+	}
+	petrov := csvEntries[2]
+	if petrov.EntryId != "2674" || len(petrov.Programs) != 2 || len(petrov.Aliases) != 2 {
+		t.Errorf("Expected entry 2674 with 2 programs and 2 aliases, got %+v", petrov)
+	}
+	if csvEntries[0].Type != "" {
+		t.Errorf("Expected OFAC null field to be empty, got %s", csvEntries[0].Type)
+	}
+
+	xmlEntries, err := parseWatchlistXML(strings.NewReader(testWatchlistXML), "sdn.xml")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(xmlEntries) != 1 || xmlEntries[0].Name != "Ivan Sergeyevich PETROV" || len(xmlEntries[0].Aliases) != 1 {
+		t.Errorf("Expected PETROV with one alias, got %+v", xmlEntries)
+	}
+}
+
+func TestFuzzyNameMatching(t *testing.T) {
+	tests := []struct {
+		a, b string
+		min  float64
+		max  float64
+	}{
+		{"PETROV, Ivan Sergeyevich", "Ivan Sergeyevich Petrov", 1, 1},
+		{"Ivan Petrov", "Ivan Petrof", 0.9, 0.99},
+		{"Ivan Petrov", "Maria Gonzalez", 0, 0.7},
+	}
+
+	for _, tc := range tests {
+		score := jaroWinkler(normalizeName(tc.a), normalizeName(tc.b))
+		if score < tc.min || score > tc.max {
+			t.Errorf("Expected score of %q and %q within [%.2f, %.2f], got %.3f", tc.a, tc.b, tc.min, tc.max, score)
+		}
+	}
+}
+
+func TestTransferWatchlistScreening(t *testing.T) {
+	// Setup
+	screener, screeningRepo, _ := newTestScreeningService(t)
+	repo := repository.NewMemoryAccountRepo()
+	service := NewAccountService(repo, cache.NewMemoryCache(), WithScreener(screener))
+	ctx := context.Background()
+
+	repo.CreateAccount(ctx, &account.Model{AccountId: "clean", HolderName: "Maria Gonzalez", Balance: 1000.0})
+	repo.CreateAccount(ctx, &account.Model{AccountId: "other", HolderName: "Li Wei", Balance: 0})
+	repo.CreateAccount(ctx, &account.Model{AccountId: "sanctioned", HolderName: "Ivan Petroff", Balance: 0})
+	repo.CreateAccount(ctx, &account.Model{AccountId: "similar", HolderName: "Anglo Caribbean Company", Balance: 0})
+
+	response, err := service.TxnAccount(ctx, "clean", "other", 100.0)
+	if err != nil || response.Transfer.Status != transfer.StatusCompleted {
+		t.Fatalf("Expected completed transfer, got %+v (%v)", response.Transfer, err)
+	}
+
+	response, err = service.TxnAccount(ctx, "clean", "sanctioned", 100.0)
+	if !errors.Is(err, ErrTransferBlocked) {
+		t.Errorf("Expected ErrTransferBlocked, got %v", err)
+	}
+	if response.Transfer == nil || response.Transfer.Status != transfer.StatusBlocked {
+		t.Fatalf("Expected blocked transfer, got %+v", response.Transfer)
+	}
+
+	response, err = service.TxnAccount(ctx, "clean", "similar", 100.0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Transfer.Status != transfer.StatusPendingReview {
+		t.Errorf("Expected status %s, got %s", transfer.StatusPendingReview, response.Transfer.Status)
+	}
+
+	source, _ := repo.GetAccount(ctx, "clean")
+	if source.Balance != 900.0 {
+		t.Errorf("Expected only the clean transfer to move money, got balance %f", source.Balance)
+	}
+	if len(screeningRepo.hits) != 2 {
+		t.Fatalf("Expected 2 hits, got %d", len(screeningRepo.hits))
+	}
+	for _, hit := range screeningRepo.hits {
+		if hit.TransferId == uuid.Nil || hit.Status != screening.HitStatusOpen {
+			t.Errorf("Expected an open hit linked to its transfer, got %+v", hit)
+		}
+	}
+}
+
+func TestWatchlistReloadAndHitReview(t *testing.T) {
+	// Setup
+	screener, screeningRepo, cache := newTestScreeningService(t)
+	ctx := context.Background()
+	party := screening.Party{AccountId: "acc", Name: "Jane Roe"}
+
+	matches, err := screener.Screen(ctx, uuid.New(), party)
+	if err != nil || len(matches) != 0 {
+		t.Fatalf("Expected no matches, got %d (%v)", len(matches), err)
+	}
+
+	// Another process stores a new list and bumps the version
+	screeningRepo.ReplaceWatchlist(ctx, []screening.WatchlistEntry{{EntryId: "9", Name: "ROE, Jane"}})
+	cache.Set(ctx, WatchlistVersionKey, "next", 0)
+
+	matches, err = screener.Screen(ctx, uuid.New(), party)
+	if err != nil || len(matches) != 1 || matches[0].Action != screening.ActionBlock {
+		t.Fatalf("Expected a blocking match after reload, got %+v (%v)", matches, err)
+	}
+
+	hits, err := screener.ListHits(ctx, screening.HitStatusOpen)
+	if err != nil || len(hits) != 1 {
+		t.Fatalf("Expected 1 open hit, got %d (%v)", len(hits), err)
+	}
+
+	review := screening.ReviewHitRequest{Status: screening.HitStatusCleared, Note: "different date of birth"}
+	if _, err := screener.ReviewHit(ctx, hits[0].HitId, review); !errors.Is(err, ErrOperatorRequired) {
+		t.Errorf("Expected ErrOperatorRequired, got %v", err)
+	}
+	reviewed, err := screener.ReviewHit(operatorContext("analyst"), hits[0].HitId, review)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if reviewed.Status != screening.HitStatusCleared || reviewed.ReviewedBy != "analyst" {
+		t.Errorf("Expected hit cleared by analyst, got %s by %s", reviewed.Status, reviewed.ReviewedBy)
+	}
+	if _, err := screener.ReviewHit(operatorContext("analyst"), hits[0].HitId, review); !errors.Is(err, ErrHitAlreadyReviewed) {
+		t.Errorf("Expected ErrHitAlreadyReviewed, got %v", err)
+	}
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"internal-transfer-microservice/internal/config"
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/limit"
+	"internal-transfer-microservice/internal/domain/migration"
+	"internal-transfer-microservice/internal/domain/transfer"
+	"internal-transfer-microservice/internal/infrastructure/cache"
+	"internal-transfer-microservice/internal/infrastructure/db"
+	"internal-transfer-microservice/internal/repository"
+	"internal-transfer-microservice/migrations"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestSQLiteDB opens a SQLite database in a temporary directory with a migrator for it
+func newTestSQLiteDB(t *testing.T) (db.Database, migration.Service) {
+	cfg := &config.Config{}
+	cfg.Database.Driver = db.DriverSQLite
+	cfg.Database.Path = filepath.Join(t.TempDir(), "transfer_service.db")
+	cfg.Database.LogLevel = "silent"
+	database, err := db.NewDatabase(cfg)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	files, err := migrations.For(db.DriverSQLite)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	scripts, err := migration.Load(files)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return database, NewMigrator(repository.NewMigrationRepo(database), scripts)
+}
+
+func TestSQLiteMigrations(t *testing.T) {
+	// Setup
+	database, migrator := newTestSQLiteDB(t)
+	ctx := context.Background()
+
+	// Test case: every migration applies, and reverting them all drops the schema
+	steps, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(statuses) != len(steps) || !statuses[len(statuses)-1].Applied {
+		t.Errorf("Expected every migration applied, got %+v", statuses)
+	}
+
+	if _, err := migrator.To(ctx, 0); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if database.GetConnection().Migrator().HasTable("accounts") {
+		t.Error("Expected the accounts table to be dropped")
+	}
+	if steps, err := migrator.Up(ctx); err != nil || len(steps) != len(statuses) {
+		t.Errorf("Expected every migration applied again, got %d (%v)", len(steps), err)
+	}
+}
+
+func TestSQLiteTransfers(t *testing.T) {
+	// Setup
+	database, migrator := newTestSQLiteDB(t)
+	ctx := context.Background()
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	service := NewAccountService(repository.NewAccountRepo(database), cache.NewMemoryCache())
+
+	for _, req := range []account.CreateAccountRequest{
+		{AccountId: "src", InitialBalance: 100},
+		{AccountId: "dst", InitialBalance: 0},
+	} {
+		if _, err := service.CreateAccount(ctx, req); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	// Test case: transfers move money and are recorded with their outbox events
+	for i := 0; i < 3; i++ {
+		if _, err := service.TxnAccount(ctx, "src", "dst", 20); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	source, err := service.GetAccount(ctx, "src")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if source.Balance != 40 {
+		t.Errorf("Expected source balance 40, got %.2f", source.Balance)
+	}
+	history, err := service.GetTransferHistory(ctx, "dst")
+	if err != nil || len(history) != 3 {
+		t.Errorf("Expected 3 transfers in the history, got %d (%v)", len(history), err)
+	}
+
+	events, err := repository.NewOutboxRepo(database).ListPendingEvents(ctx, 100)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for i, outbox := range events {
+		if outbox.Sequence != uint64(i+1) {
+			t.Errorf("Expected event %d to have sequence %d, got %d", i, i+1, outbox.Sequence)
+		}
+	}
+
+	// Test case: transfers of no money are refused
+	if _, err := service.TxnAccount(ctx, "src", "dst", -10); err == nil {
+		t.Error("Expected a negative transfer to be refused")
+	}
+	if source, _ := service.GetAccount(ctx, "src"); source.Balance != 40 {
+		t.Errorf("Expected source balance 40 after the refused transfer, got %.2f", source.Balance)
+	}
+
+	// Test case: frozen accounts are stored and refuse transfers
+	if _, err := service.SetAccountFrozen(operatorContext("oncall"), "dst", account.FreezeAccountRequest{Frozen: true}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if destination, _ := service.GetAccount(ctx, "dst"); !destination.Frozen {
+		t.Errorf("Expected dst to be frozen")
+	}
+	if _, err := service.TxnAccount(ctx, "src", "dst", 10); !errors.Is(err, account.ErrAccountFrozen) {
+		t.Errorf("Expected ErrAccountFrozen, got %v", err)
+	}
+}
+
+func TestSQLiteBalanceChanges(t *testing.T) {
+	// Setup
+	database, migrator := newTestSQLiteDB(t)
+	ctx := context.Background()
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	repo := repository.NewAccountRepo(database)
+	repo.CreateAccount(ctx, &account.Model{AccountId: "acc-1", Balance: 100})
+
+	// Test case: writers that read the same balance each apply their change, rather than overwrite the other's
+	first, _ := repo.GetAccount(ctx, "acc-1")
+	second, _ := repo.GetAccount(ctx, "acc-1")
+	first.Debit(10)
+	second.Debit(30)
+	if err := repo.UpdateAccountsInTx(ctx, nil, first); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := repo.UpdateAccountsInTx(ctx, nil, second); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if second.Balance != 60 || second.BalanceChange() != 0 {
+		t.Errorf("Expected the saved account to have the stored balance 60, got %.2f", second.Balance)
+	}
+	if stored, _ := repo.GetAccount(ctx, "acc-1"); stored.Balance != 60 {
+		t.Errorf("Expected stored balance 60, got %.2f", stored.Balance)
+	}
+
+	// Test case: a change is refused when the stored balance cannot take it, whatever the read balance was
+	first.Debit(70)
+	if err := repo.UpdateAccountsInTx(ctx, nil, first); !errors.Is(err, account.ErrOverdraftLimitExceeded) {
+		t.Errorf("Expected ErrOverdraftLimitExceeded, got %v", err)
+	}
+	if stored, _ := repo.GetAccount(ctx, "acc-1"); stored.Balance != 60 {
+		t.Errorf("Expected stored balance 60 after the refused change, got %.2f", stored.Balance)
+	}
+}
+
+func TestSQLiteLimitCounters(t *testing.T) {
+	// Setup
+	database, migrator := newTestSQLiteDB(t)
+	ctx := context.Background()
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	repo := repository.NewAccountRepo(database)
+	repo.CreateAccount(ctx, &account.Model{AccountId: "src", Balance: 100})
+	repo.CreateAccount(ctx, &account.Model{AccountId: "dst", Balance: 0})
+	caps := &limit.Caps{DailyAmount: 50, DailyCount: 2}
+	record := func(amount float64) error {
+		src, _ := repo.GetAccount(ctx, "src")
+		dst, _ := repo.GetAccount(ctx, "dst")
+		src.Debit(amount)
+		dst.Credit(amount)
+		now := time.Now().UTC()
+		txn := &transfer.Model{SourceAccountId: "src", DestinationAccountId: "dst", Amount: amount, Type: transfer.TypeTransfer,
+			Status: transfer.StatusCompleted, ExecutedAt: &now, LimitCaps: caps}
+		return repo.UpdateAccountsInTx(ctx, txn, src, dst)
+	}
+
+	// Test case: counters are incremented within the caps, and a transfer beyond them records nothing
+	if err := record(30); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var exceeded *limit.ExceededError
+	if err := record(30); !errors.As(err, &exceeded) || exceeded.Code != limit.CodeDailyAmount {
+		t.Errorf("Expected %s, got %v", limit.CodeDailyAmount, err)
+	}
+	if err := record(20); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := record(0.01); !errors.As(err, &exceeded) || exceeded.Code != limit.CodeDailyCount {
+		t.Errorf("Expected %s, got %v", limit.CodeDailyCount, err)
+	}
+
+	counters, _ := repository.NewLimitRepo(database).GetCounters(ctx, "src", limit.DailyPeriod(time.Now()))
+	if counter := counters[limit.DailyPeriod(time.Now())]; counter.Amount != 50 || counter.Count != 2 {
+		t.Errorf("Expected 2 transfers of 50.00 counted, got %d of %.2f", counter.Count, counter.Amount)
+	}
+	if src, _ := repo.GetAccount(ctx, "src"); src.Balance != 50 {
+		t.Errorf("Expected source balance 50, got %.2f", src.Balance)
+	}
+}
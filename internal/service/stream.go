@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"internal-transfer-microservice/internal/domain/access"
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/stream"
+	"internal-transfer-microservice/internal/infrastructure/broker"
+)
+
+var ErrInvalidLastEventId = errors.New("Last-Event-ID must be a positive event id")
+
+// streamChannel returns the broker channel carrying the events of an account
+func streamChannel(accountId string) string {
+	return "accounts:" + accountId + ":events"
+}
+
+// streamMessage is how an event travels through the broker, which numbers it
+type streamMessage struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+type StreamServiceImpl struct {
+	accessGuard
+	broker      broker.Broker
+	accountRepo account.Repository
+}
+
+func (s *StreamServiceImpl) Notify(ctx context.Context, accountId, eventType string, data interface{}) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(streamMessage{Type: eventType, Data: encoded})
+	if err != nil {
+		return err
+	}
+	_, err = s.broker.Publish(ctx, streamChannel(accountId), payload)
+	return err
+}
+
+func (s *StreamServiceImpl) Subscribe(ctx context.Context, accountId, lastEventId string) (<-chan stream.Event, error) {
+	var lastId uint64
+	if lastEventId != "" {
+		var err error
+		if lastId, err = strconv.ParseUint(lastEventId, 10, 64); err != nil {
+			return nil, ErrInvalidLastEventId
+		}
+	}
+	acc, err := s.accountRepo.GetAccount(ctx, accountId)
+	if err != nil {
+		return nil, ErrAccountNotFound
+	}
+	if err := s.authorize(ctx, access.ActionRead, acc); err != nil {
+		return nil, err
+	}
+
+	messages, err := s.broker.Subscribe(ctx, streamChannel(accountId), lastId)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan stream.Event)
+	go func() {
+		defer close(events)
+
+		send := func(event stream.Event) bool {
+			select {
+			case events <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for message := range messages {
+			// ids are contiguous per account, so a jump means the backlog no longer holds what the client missed
+			if lastId > 0 && message.ID > lastId+1 {
+				if !send(stream.Event{ID: message.ID - 1, Type: stream.EventReset, Data: json.RawMessage("{}")}) {
+					return
+				}
+			}
+			lastId = message.ID
+
+			var decoded streamMessage
+			if err := json.Unmarshal(message.Payload, &decoded); err != nil {
+				continue
+			}
+			if !send(stream.Event{ID: message.ID, Type: decoded.Type, Data: decoded.Data}) {
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+// NewStreamService creates the account event stream service; subscribers must be allowed to read the
+// account by policy, unless it is nil
+func NewStreamService(broker broker.Broker, accountRepo account.Repository, policy access.Policy) stream.Service {
+	return &StreamServiceImpl{
+		accessGuard: accessGuard{policy: policy},
+		broker:      broker,
+		accountRepo: accountRepo,
+	}
+}
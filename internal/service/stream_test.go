@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/stream"
+	"internal-transfer-microservice/internal/infrastructure/broker"
+	"internal-transfer-microservice/internal/infrastructure/cache"
+	"internal-transfer-microservice/internal/repository"
+	"testing"
+	"time"
+)
+
+// nextEvent waits for the next event of a stream
+func nextEvent(t *testing.T, events <-chan stream.Event) stream.Event {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatalf("Expected an event")
+		return stream.Event{}
+	}
+}
+
+func TestTransferStreamsBalanceChanges(t *testing.T) {
+	// Setup
+	repo := repository.NewMemoryAccountRepo()
+	streams := NewStreamService(broker.NewMemoryBroker(100), repo, nil)
+	service := NewAccountService(repo, cache.NewMemoryCache(), WithNotifier(streams))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	repo.CreateAccount(ctx, &account.Model{AccountId: "source", Balance: 1000.0})
+	repo.CreateAccount(ctx, &account.Model{AccountId: "dest", Balance: 0})
+
+	if _, err := streams.Subscribe(ctx, "missing", ""); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("Expected ErrAccountNotFound, got %v", err)
+	}
+	if _, err := streams.Subscribe(ctx, "dest", "abc"); !errors.Is(err, ErrInvalidLastEventId) {
+		t.Errorf("Expected ErrInvalidLastEventId, got %v", err)
+	}
+
+	events, err := streams.Subscribe(ctx, "dest", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := service.TxnAccount(ctx, "source", "dest", 250.0); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	balance := nextEvent(t, events)
+	if balance.ID != 1 || balance.Type != stream.EventBalance {
+		t.Fatalf("Expected balance event 1, got %d %s", balance.ID, balance.Type)
+	}
+	var changed stream.BalanceChanged
+	json.Unmarshal(balance.Data, &changed)
+	if changed.AccountId != "dest" || changed.Balance != 250.0 || changed.TransferId == "" {
+		t.Errorf("Expected dest balance 250 linked to the transfer, got %+v", changed)
+	}
+
+	completed := nextEvent(t, events)
+	if completed.ID != 2 || completed.Type != stream.EventTransfer {
+		t.Errorf("Expected transfer event 2, got %d %s", completed.ID, completed.Type)
+	}
+}
+
+func TestStreamResumesFromLastEventId(t *testing.T) {
+	// Setup
+	repo := repository.NewMemoryAccountRepo()
+	streams := NewStreamService(broker.NewMemoryBroker(3), repo, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	repo.CreateAccount(ctx, &account.Model{AccountId: "acc", Balance: 0})
+	for i := 1; i <= 5; i++ {
+		streams.Notify(ctx, "acc", stream.EventBalance, stream.BalanceChanged{AccountId: "acc", Balance: float64(i)})
+	}
+
+	// events 3 to 5 are still in the backlog
+	events, err := streams.Subscribe(ctx, "acc", "3")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, id := range []uint64{4, 5} {
+		if event := nextEvent(t, events); event.ID != id || event.Type != stream.EventBalance {
+			t.Errorf("Expected replayed event %d, got %d %s", id, event.ID, event.Type)
+		}
+	}
+
+	// event 2 has been dropped, so the client is told to refetch before the backlog resumes
+	events, err = streams.Subscribe(ctx, "acc", "1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if event := nextEvent(t, events); event.Type != stream.EventReset || event.ID != 2 {
+		t.Errorf("Expected a reset at id 2, got %d %s", event.ID, event.Type)
+	}
+	if event := nextEvent(t, events); event.ID != 3 {
+		t.Errorf("Expected event 3 after the reset, got %d", event.ID)
+	}
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"internal-transfer-microservice/internal/domain/screening"
+)
+
+var ErrUnsupportedWatchlistFormat = errors.New("watchlist must be a .csv or .xml file")
+
+// ofacNull is how OFAC files spell an empty field
+const ofacNull = "-0-"
+
+// akaPattern finds the aliases OFAC lists in the remarks of sdn.csv rows
+var akaPattern = regexp.MustCompile(`a\.k\.a\. '([^']+)'`)
+
+// loadWatchlist parses the watchlist at path as an OFAC-style sdn.csv or sdn.xml, depending on its extension
+func loadWatchlist(path string) ([]screening.WatchlistEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	source := filepath.Base(path)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return parseWatchlistCSV(file, source)
+	case ".xml":
+		return parseWatchlistXML(file, source)
+	default:
+		return nil, ErrUnsupportedWatchlistFormat
+	}
+}
+
+// parseWatchlistCSV reads rows laid out like sdn.csv: ent_num, name, type, programs, and the remarks as twelfth column
+func parseWatchlistCSV(r io.Reader, source string) ([]screening.WatchlistEntry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	var entries []screening.WatchlistEntry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		// skips blank rows, an optional header and the end-of-file marker OFAC appends
+		if len(record) < 2 || ofacField(record[1]) == "" || strings.EqualFold(record[0], "ent_num") {
+			continue
+		}
+
+		entry := screening.WatchlistEntry{
+			EntryId: ofacField(record[0]),
+			Name:    ofacField(record[1]),
+			Source:  source,
+		}
+		if len(record) > 2 {
+			entry.Type = ofacField(record[2])
+		}
+		if len(record) > 3 {
+			entry.Programs = splitPrograms(record[3])
+		}
+		if len(record) > 11 {
+			for _, aka := range akaPattern.FindAllStringSubmatch(record[11], -1) {
+				entry.Aliases = append(entry.Aliases, aka[1])
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+type ofacName struct {
+	FirstName string `xml:"firstName"`
+	LastName  string `xml:"lastName"`
+}
+
+func (n ofacName) fullName() string {
+	return strings.TrimSpace(n.FirstName + " " + n.LastName)
+}
+
+type ofacEntry struct {
+	Uid string `xml:"uid"`
+	ofacName
+	SdnType  string     `xml:"sdnType"`
+	Programs []string   `xml:"programList>program"`
+	Akas     []ofacName `xml:"akaList>aka"`
+}
+
+// parseWatchlistXML reads the sdnEntry elements of a document laid out like sdn.xml
+func parseWatchlistXML(r io.Reader, source string) ([]screening.WatchlistEntry, error) {
+	var list struct {
+		Entries []ofacEntry `xml:"sdnEntry"`
+	}
+	if err := xml.NewDecoder(r).Decode(&list); err != nil {
+		return nil, err
+	}
+
+	entries := make([]screening.WatchlistEntry, 0, len(list.Entries))
+	for _, sdn := range list.Entries {
+		entry := screening.WatchlistEntry{
+			EntryId:  strings.TrimSpace(sdn.Uid),
+			Name:     sdn.fullName(),
+			Type:     strings.TrimSpace(sdn.SdnType),
+			Programs: sdn.Programs,
+			Source:   source,
+		}
+		if entry.Name == "" {
+			continue
+		}
+		for _, aka := range sdn.Akas {
+			if alias := aka.fullName(); alias != "" {
+				entry.Aliases = append(entry.Aliases, alias)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func ofacField(value string) string {
+	value = strings.TrimSpace(value)
+	if value == ofacNull {
+		return ""
+	}
+	return value
+}
+
+// splitPrograms splits sdn.csv's program field, which joins several programs as "SDGT] [IRGC"
+func splitPrograms(value string) []string {
+	var programs []string
+	for _, program := range strings.FieldsFunc(ofacField(value), func(r rune) bool { return r == '[' || r == ']' }) {
+		if program = strings.TrimSpace(program); program != "" {
+			programs = append(programs, program)
+		}
+	}
+	return programs
+}
+
+// watchlistName is one name or alias of a watchlist entry, prepared for matching
+type watchlistName struct {
+	entry      *screening.WatchlistEntry
+	name       string
+	normalized string
+}
+
+func prepareWatchlist(entries []screening.WatchlistEntry) []watchlistName {
+	var names []watchlistName
+	for i := range entries {
+		entry := &entries[i]
+		for _, name := range append([]string{entry.Name}, entry.Aliases...) {
+			names = append(names, watchlistName{entry: entry, name: name, normalized: normalizeName(name)})
+		}
+	}
+	return names
+}
+
+// normalizeName lowercases name, drops punctuation and sorts its tokens, so "SMITH, John" and "John Smith" compare equal
+func normalizeName(name string) string {
+	tokens := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	sort.Strings(tokens)
+	return strings.Join(tokens, " ")
+}
+
+// jaroWinkler returns the Jaro-Winkler similarity of a and b, from 0 for nothing in common to 1 for identical strings
+func jaroWinkler(a, b string) float64 {
+	s1, s2 := []rune(a), []rune(b)
+	if len(s1) == 0 || len(s2) == 0 {
+		if len(s1) == len(s2) {
+			return 1
+		}
+		return 0
+	}
+
+	window := max(max(len(s1), len(s2))/2-1, 0)
+	matched1, matched2 := make([]bool, len(s1)), make([]bool, len(s2))
+	matches := 0
+	for i := range s1 {
+		for j := max(0, i-window); j < min(len(s2), i+window+1); j++ {
+			if matched2[j] || s1[i] != s2[j] {
+				continue
+			}
+			matched1[i], matched2[j] = true, true
+			matches++
+			break
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions, k := 0, 0
+	for i := range s1 {
+		if !matched1[i] {
+			continue
+		}
+		for !matched2[k] {
+			k++
+		}
+		if s1[i] != s2[k] {
+			transpositions++
+		}
+		k++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(s1)) + m/float64(len(s2)) + (m-float64(transpositions)/2)/m) / 3
+
+	// boost strings sharing a prefix of up to four characters
+	prefix := 0
+	for prefix < 4 && prefix < len(s1) && prefix < len(s2) && s1[prefix] == s2[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
@@ -0,0 +1,474 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"internal-transfer-microservice/internal/config"
+	"internal-transfer-microservice/internal/domain/access"
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/event"
+	"internal-transfer-microservice/internal/domain/webhook"
+	"internal-transfer-microservice/internal/infrastructure/cache"
+	"internal-transfer-microservice/internal/infrastructure/db"
+	"internal-transfer-microservice/pkg/logger"
+)
+
+// WebhookDispatchLockKey is held while a dispatcher sends deliveries, so concurrent dispatchers cannot send one twice
+const WebhookDispatchLockKey = "webhooks:dispatch"
+
+// WebhookDeliveriesLimit caps the number of deliveries returned by ListDeliveries
+const WebhookDeliveriesLimit = 100
+
+// WebhookSignatureTolerance is how old a signed timestamp may be before receivers should reject the call
+const WebhookSignatureTolerance = 5 * time.Minute
+
+// webhookDispatchWindow is how long a dispatcher starts attempts for before it gives the lock back. The lock
+// outlives it by the timeout of a call, so it is released before it expires, and claimed deliveries are leased
+// as long, so no other dispatcher takes them while they are being sent.
+const webhookDispatchWindow = 2 * time.Minute
+
+// webhookSignaturePrefix names the scheme of the signature header, leaving room for another one
+const webhookSignaturePrefix = "sha256="
+
+// webhookErrorLength bounds the response body kept as the error of a failed attempt
+const webhookErrorLength = 512
+
+var (
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+	ErrInvalidWebhookURL    = errors.New("webhook url must be an absolute http or https url")
+	ErrInvalidWebhookEvent  = errors.New("webhooks can only subscribe to TransferCompleted and TransferFailed")
+	ErrInvalidSignature     = errors.New("webhook signature does not match")
+	ErrSignatureExpired     = errors.New("webhook signature timestamp is outside the tolerance")
+	errSubscriptionInactive = errors.New("subscription is no longer active")
+)
+
+// webhookSubscribableTypes are the events a subscription may ask for
+var webhookSubscribableTypes = map[string]bool{event.TypeTransferCompleted: true, event.TypeTransferFailed: true}
+
+// SignWebhookPayload returns the signature header value of body sent at timestamp (unix seconds):
+// the hex HMAC-SHA256 of "<timestamp>.<body>" keyed by the subscription secret
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return webhookSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature checks a received call the way subscribers are expected to,
+// rejecting timestamps further than tolerance from now to defeat replays
+func VerifyWebhookSignature(secret, signature, timestamp string, body []byte, tolerance time.Duration, now time.Time) error {
+	sentAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(sentAt, 0)); age > tolerance || age < -tolerance {
+		return ErrSignatureExpired
+	}
+	if !hmac.Equal([]byte(signature), []byte(SignWebhookPayload(secret, sentAt, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+type WebhookServiceImpl struct {
+	accessGuard
+	repo        webhook.Repository
+	accountRepo account.Repository
+}
+
+func (w *WebhookServiceImpl) CreateSubscription(ctx context.Context, req webhook.CreateSubscriptionRequest) (*webhook.SubscriptionResponse, error) {
+	target, err := url.Parse(req.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, ErrInvalidWebhookURL
+	}
+	for _, eventType := range req.EventTypes {
+		if !webhookSubscribableTypes[eventType] {
+			return nil, ErrInvalidWebhookEvent
+		}
+	}
+	acc, err := w.accountRepo.GetAccount(ctx, req.AccountId)
+	if err != nil {
+		return nil, ErrAccountNotFound
+	}
+	if err := w.authorize(ctx, access.ActionManage, acc); err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		if secret, err = generateWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	subscription := &webhook.Subscription{
+		AccountId:  req.AccountId,
+		URL:        target.String(),
+		EventTypes: req.EventTypes,
+		Secret:     secret,
+		Active:     true,
+	}
+	if err := w.repo.CreateSubscription(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	// the secret is shown once, when the subscriber needs it to verify signatures
+	response := subscription.ToResponse()
+	response.Secret = secret
+	return response, nil
+}
+
+func generateWebhookSecret() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(key), nil
+}
+
+func (w *WebhookServiceImpl) ListSubscriptions(ctx context.Context) ([]*webhook.SubscriptionResponse, error) {
+	if err := w.authorize(ctx, access.ActionManage, nil); err != nil {
+		return nil, err
+	}
+	subscriptions, err := w.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*webhook.SubscriptionResponse, 0, len(subscriptions))
+	for i := range subscriptions {
+		responses = append(responses, subscriptions[i].ToResponse())
+	}
+	return responses, nil
+}
+
+func (w *WebhookServiceImpl) DeleteSubscription(ctx context.Context, subscriptionId string) error {
+	ctx = db.WithPrimary(ctx)
+	subscription, err := w.subscription(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+	if !subscription.Active {
+		return nil
+	}
+
+	// deactivated rather than deleted, so its delivery log stays available
+	subscription.Active = false
+	return w.repo.UpdateSubscription(ctx, subscription)
+}
+
+func (w *WebhookServiceImpl) ListDeliveries(ctx context.Context, subscriptionId string) ([]*webhook.DeliveryResponse, error) {
+	subscription, err := w.subscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+	deliveries, err := w.repo.ListDeliveries(ctx, subscription.ID, WebhookDeliveriesLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*webhook.DeliveryResponse, 0, len(deliveries))
+	for i := range deliveries {
+		response := deliveries[i].ToResponse()
+		response.Payload = nil
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+func (w *WebhookServiceImpl) GetDelivery(ctx context.Context, subscriptionId, deliveryId string) (*webhook.DeliveryResponse, error) {
+	delivery, err := w.delivery(ctx, subscriptionId, deliveryId)
+	if err != nil {
+		return nil, err
+	}
+	return w.withLog(ctx, delivery)
+}
+
+func (w *WebhookServiceImpl) ReplayDelivery(ctx context.Context, subscriptionId, deliveryId string) (*webhook.DeliveryResponse, error) {
+	ctx = db.WithPrimary(ctx)
+	delivery, err := w.delivery(ctx, subscriptionId, deliveryId)
+	if err != nil {
+		return nil, err
+	}
+
+	// a replay starts a fresh round of attempts; the log keeps the earlier ones
+	now := time.Now().UTC()
+	delivery.Status = webhook.DeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = &now
+	delivery.DeliveredAt = nil
+	if err := w.repo.UpdateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+	return w.withLog(ctx, delivery)
+}
+
+// subscription reads a subscription for a caller allowed to manage webhooks
+func (w *WebhookServiceImpl) subscription(ctx context.Context, subscriptionId string) (*webhook.Subscription, error) {
+	if err := w.authorize(ctx, access.ActionManage, nil); err != nil {
+		return nil, err
+	}
+	id, err := uuid.Parse(subscriptionId)
+	if err != nil {
+		return nil, ErrSubscriptionNotFound
+	}
+	subscription, err := w.repo.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, ErrSubscriptionNotFound
+	}
+	return subscription, nil
+}
+
+func (w *WebhookServiceImpl) delivery(ctx context.Context, subscriptionId, deliveryId string) (*webhook.Delivery, error) {
+	subscription, err := w.subscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+	id, err := uuid.Parse(deliveryId)
+	if err != nil {
+		return nil, ErrDeliveryNotFound
+	}
+	delivery, err := w.repo.GetDelivery(ctx, id)
+	if err != nil || delivery.SubscriptionId != subscription.ID {
+		return nil, ErrDeliveryNotFound
+	}
+	return delivery, nil
+}
+
+func (w *WebhookServiceImpl) withLog(ctx context.Context, delivery *webhook.Delivery) (*webhook.DeliveryResponse, error) {
+	attempts, err := w.repo.ListAttempts(ctx, delivery.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := delivery.ToResponse()
+	for i := range attempts {
+		response.Log = append(response.Log, attempts[i].ToResponse())
+	}
+	return response, nil
+}
+
+type WebhookDispatcherImpl struct {
+	repo         webhook.Repository
+	cache        cache.Cache
+	client       *http.Client
+	maxAttempts  int
+	retryBackoff time.Duration
+	maxBackoff   time.Duration
+	interval     time.Duration
+	batchSize    int
+}
+
+func (w *WebhookDispatcherImpl) DispatchDue(ctx context.Context) (int, error) {
+	start := time.Now()
+	lockTTL := webhookDispatchWindow + w.client.Timeout
+	acquired, err := w.cache.Lock(ctx, WebhookDispatchLockKey, lockTTL)
+	if err != nil {
+		return 0, err
+	}
+	if !acquired {
+		// another dispatcher is sending the due deliveries
+		return 0, nil
+	}
+	defer w.cache.Release(ctx, WebhookDispatchLockKey)
+
+	// deliveries are sent to the subscriptions as they are now, not as a replica last saw them
+	ctx = db.WithPrimary(ctx)
+	deliveries, err := w.repo.ListDueDeliveries(ctx, time.Now().UTC(), w.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	subscriptions := make(map[uuid.UUID]*webhook.Subscription)
+	succeeded := 0
+	for i := range deliveries {
+		if time.Since(start) > webhookDispatchWindow {
+			// the rest are left to the next pass, under a fresh lock
+			break
+		}
+		delivery := &deliveries[i]
+		claimed, err := w.repo.ClaimDelivery(ctx, delivery, time.Now().UTC().Add(lockTTL))
+		if err != nil {
+			return succeeded, err
+		}
+		if !claimed {
+			// another dispatcher or a replay changed the delivery since it was listed
+			continue
+		}
+
+		subscription, seen := subscriptions[delivery.SubscriptionId]
+		if !seen {
+			if subscription, err = w.repo.GetSubscription(ctx, delivery.SubscriptionId); err != nil {
+				return succeeded, err
+			}
+			subscriptions[delivery.SubscriptionId] = subscription
+		}
+
+		err = w.attempt(ctx, subscription, delivery)
+		if errors.Is(err, webhook.ErrDeliveryReclaimed) {
+			logger.Warnf("Webhook delivery %s was claimed again before attempt %d was recorded", delivery.ID, delivery.Attempts)
+			continue
+		}
+		if err != nil {
+			return succeeded, err
+		}
+		if delivery.Status == webhook.DeliverySucceeded {
+			succeeded++
+		}
+	}
+	return succeeded, nil
+}
+
+// attempt makes the call of a claimed delivery and records its outcome, scheduling a retry or dead-lettering it
+// on failure
+func (w *WebhookDispatcherImpl) attempt(ctx context.Context, subscription *webhook.Subscription, delivery *webhook.Delivery) error {
+	start := time.Now()
+	code, callErr := 0, errSubscriptionInactive
+	if subscription.Active {
+		code, callErr = w.post(ctx, subscription, delivery)
+	}
+	now := time.Now().UTC()
+
+	delivery.LastStatusCode = code
+	attempt := &webhook.DeliveryAttempt{
+		DeliveryId: delivery.ID,
+		Attempt:    delivery.Attempts,
+		StatusCode: code,
+		DurationMs: now.Sub(start).Milliseconds(),
+	}
+
+	switch {
+	case callErr == nil:
+		delivery.Status = webhook.DeliverySucceeded
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+		delivery.LastError = ""
+	case errors.Is(callErr, errSubscriptionInactive) || delivery.Attempts >= w.maxAttempts:
+		delivery.Status = webhook.DeliveryDeadLettered
+		delivery.NextAttemptAt = nil
+		delivery.LastError = callErr.Error()
+		attempt.Error = callErr.Error()
+		logger.Warnf("Webhook delivery %s dead-lettered after %d attempts: %v", delivery.ID, delivery.Attempts, callErr)
+	default:
+		next := now.Add(w.backoff(delivery.Attempts))
+		delivery.NextAttemptAt = &next
+		delivery.LastError = callErr.Error()
+		attempt.Error = callErr.Error()
+	}
+
+	return w.repo.RecordAttempt(ctx, delivery, attempt)
+}
+
+// backoff returns the delay after the given number of failed attempts, doubling from retryBackoff up to maxBackoff
+func (w *WebhookDispatcherImpl) backoff(attempts int) time.Duration {
+	delay := w.retryBackoff
+	for i := 1; i < attempts && delay < w.maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, w.maxBackoff)
+}
+
+// post sends the signed payload of delivery, succeeding on any 2xx response
+func (w *WebhookDispatcherImpl) post(ctx context.Context, subscription *webhook.Subscription, delivery *webhook.Delivery) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhook.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhook.HeaderSignature, SignWebhookPayload(subscription.Secret, timestamp, body))
+	req.Header.Set(webhook.HeaderEventType, delivery.EventType)
+	req.Header.Set(webhook.HeaderDelivery, delivery.ID.String())
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, webhookErrorLength))
+		return resp.StatusCode, fmt.Errorf("subscriber answered %d: %s", resp.StatusCode, bytes.TrimSpace(excerpt))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
+
+func (w *WebhookDispatcherImpl) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		succeeded, err := w.DispatchDue(ctx)
+		if err != nil && ctx.Err() == nil {
+			logger.Errorf("Failed to dispatch webhooks: %v", err)
+		}
+		if succeeded > 0 {
+			logger.Infof("Delivered %d webhooks", succeeded)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NewWebhookService creates the webhook subscription service; callers must be allowed to manage webhooks
+// by policy, unless it is nil
+func NewWebhookService(repo webhook.Repository, accountRepo account.Repository, policy access.Policy) webhook.Service {
+	return &WebhookServiceImpl{
+		accessGuard: accessGuard{policy: policy},
+		repo:        repo,
+		accountRepo: accountRepo,
+	}
+}
+
+func NewWebhookDispatcher(repo webhook.Repository, cache cache.Cache, cfg *config.Config) webhook.Dispatcher {
+	dispatcher := &WebhookDispatcherImpl{
+		repo:         repo,
+		cache:        cache,
+		client:       &http.Client{Timeout: cfg.GetWebhookTimeout()},
+		maxAttempts:  cfg.GetWebhookMaxAttempts(),
+		retryBackoff: cfg.GetWebhookRetryBackoff(),
+		maxBackoff:   cfg.GetWebhookMaxBackoff(),
+		interval:     cfg.GetWebhookDispatchInterval(),
+		batchSize:    cfg.GetWebhookBatchSize(),
+	}
+	if dispatcher.maxAttempts <= 0 {
+		dispatcher.maxAttempts = 8
+	}
+	if dispatcher.retryBackoff <= 0 {
+		dispatcher.retryBackoff = 30 * time.Second
+	}
+	if dispatcher.maxBackoff < dispatcher.retryBackoff {
+		dispatcher.maxBackoff = dispatcher.retryBackoff
+	}
+	if dispatcher.interval <= 0 {
+		dispatcher.interval = time.Second
+	}
+	if dispatcher.batchSize <= 0 {
+		dispatcher.batchSize = 50
+	}
+	return dispatcher
+}
@@ -0,0 +1,481 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"internal-transfer-microservice/internal/config"
+	"internal-transfer-microservice/internal/domain/account"
+	"internal-transfer-microservice/internal/domain/event"
+	"internal-transfer-microservice/internal/domain/webhook"
+	"internal-transfer-microservice/internal/infrastructure/cache"
+	"internal-transfer-microservice/internal/repository"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// MockWebhookRepository is a mock implementation of webhook.Repository
+type MockWebhookRepository struct {
+	subscriptions []webhook.Subscription
+	deliveries    []webhook.Delivery
+	attempts      []webhook.DeliveryAttempt
+	mu            sync.Mutex
+}
+
+func (m *MockWebhookRepository) CreateSubscription(ctx context.Context, subscription *webhook.Subscription) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	subscription.ID = uuid.New()
+	m.subscriptions = append(m.subscriptions, *subscription)
+	return nil
+}
+
+func (m *MockWebhookRepository) GetSubscription(ctx context.Context, subscriptionId uuid.UUID) (*webhook.Subscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, subscription := range m.subscriptions {
+		if subscription.ID == subscriptionId {
+			return &subscription, nil
+		}
+	}
+	return nil, errors.New("subscription not found")
+}
+
+func (m *MockWebhookRepository) ListSubscriptions(ctx context.Context) ([]webhook.Subscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]webhook.Subscription(nil), m.subscriptions...), nil
+}
+
+func (m *MockWebhookRepository) UpdateSubscription(ctx context.Context, subscription *webhook.Subscription) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.subscriptions {
+		if m.subscriptions[i].ID == subscription.ID {
+			m.subscriptions[i] = *subscription
+			return nil
+		}
+	}
+	return errors.New("subscription not found")
+}
+
+// enqueue stands in for the repository queueing a delivery along with an outbox event
+func (m *MockWebhookRepository) enqueue(subscriptionId uuid.UUID, eventType, payload string) uuid.UUID {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now().UTC()
+	delivery := webhook.Delivery{
+		SubscriptionId: subscriptionId,
+		EventId:        uuid.New(),
+		EventType:      eventType,
+		Payload:        payload,
+		Status:         webhook.DeliveryPending,
+		NextAttemptAt:  &now,
+	}
+	delivery.ID = uuid.New()
+	m.deliveries = append(m.deliveries, delivery)
+	return delivery.ID
+}
+
+// makeDue moves the next attempt of every pending delivery into the past
+func (m *MockWebhookRepository) makeDue() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	past := time.Now().UTC().Add(-time.Second)
+	for i := range m.deliveries {
+		if m.deliveries[i].NextAttemptAt != nil {
+			m.deliveries[i].NextAttemptAt = &past
+		}
+	}
+}
+
+func (m *MockWebhookRepository) ListDeliveries(ctx context.Context, subscriptionId uuid.UUID, limit int) ([]webhook.Delivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var deliveries []webhook.Delivery
+	for _, delivery := range m.deliveries {
+		if delivery.SubscriptionId == subscriptionId && len(deliveries) < limit {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+func (m *MockWebhookRepository) GetDelivery(ctx context.Context, deliveryId uuid.UUID) (*webhook.Delivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, delivery := range m.deliveries {
+		if delivery.ID == deliveryId {
+			return &delivery, nil
+		}
+	}
+	return nil, errors.New("delivery not found")
+}
+
+func (m *MockWebhookRepository) ListAttempts(ctx context.Context, deliveryId uuid.UUID) ([]webhook.DeliveryAttempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var attempts []webhook.DeliveryAttempt
+	for _, attempt := range m.attempts {
+		if attempt.DeliveryId == deliveryId {
+			attempts = append(attempts, attempt)
+		}
+	}
+	return attempts, nil
+}
+
+func (m *MockWebhookRepository) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]webhook.Delivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var due []webhook.Delivery
+	for _, delivery := range m.deliveries {
+		if delivery.Status == webhook.DeliveryPending && !delivery.NextAttemptAt.After(now) && len(due) < limit {
+			due = append(due, delivery)
+		}
+	}
+	return due, nil
+}
+
+func (m *MockWebhookRepository) ClaimDelivery(ctx context.Context, delivery *webhook.Delivery, leaseUntil time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.deliveries {
+		stored := &m.deliveries[i]
+		if stored.ID == delivery.ID && stored.Status == webhook.DeliveryPending && stored.Attempts == delivery.Attempts {
+			stored.Attempts++
+			stored.NextAttemptAt = &leaseUntil
+			*delivery = *stored
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockWebhookRepository) RecordAttempt(ctx context.Context, delivery *webhook.Delivery, attempt *webhook.DeliveryAttempt) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.deliveries {
+		stored := &m.deliveries[i]
+		if stored.ID != delivery.ID {
+			continue
+		}
+		if stored.Status != webhook.DeliveryPending || stored.Attempts != delivery.Attempts {
+			return webhook.ErrDeliveryReclaimed
+		}
+		*stored = *delivery
+		m.attempts = append(m.attempts, *attempt)
+		return nil
+	}
+	return errors.New("delivery not found")
+}
+
+func (m *MockWebhookRepository) UpdateDelivery(ctx context.Context, delivery *webhook.Delivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.deliveries {
+		if m.deliveries[i].ID == delivery.ID {
+			m.deliveries[i] = *delivery
+			return nil
+		}
+	}
+	return errors.New("delivery not found")
+}
+
+// webhookReceiver is an httptest subscriber verifying signatures and answering with the queued statuses, then 200
+type webhookReceiver struct {
+	server   *httptest.Server
+	secret   string
+	statuses []int
+	received []string
+	invalid  int
+	mu       sync.Mutex
+}
+
+func newWebhookReceiver(t *testing.T, secret string, statuses ...int) *webhookReceiver {
+	receiver := &webhookReceiver{secret: secret, statuses: statuses}
+	receiver.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receiver.mu.Lock()
+		defer receiver.mu.Unlock()
+
+		body, _ := io.ReadAll(r.Body)
+		err := VerifyWebhookSignature(receiver.secret, r.Header.Get(webhook.HeaderSignature), r.Header.Get(webhook.HeaderTimestamp), body, WebhookSignatureTolerance, time.Now())
+		if err != nil {
+			receiver.invalid++
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		receiver.received = append(receiver.received, r.Header.Get(webhook.HeaderDelivery))
+
+		status := http.StatusOK
+		if len(receiver.statuses) > 0 {
+			status, receiver.statuses = receiver.statuses[0], receiver.statuses[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(receiver.server.Close)
+	return receiver
+}
+
+func newTestWebhooks(t *testing.T, maxAttempts int) (webhook.Service, webhook.Dispatcher, *MockWebhookRepository) {
+	repo := &MockWebhookRepository{}
+	accounts := repository.NewMemoryAccountRepo()
+	accounts.CreateAccount(context.Background(), &account.Model{AccountId: "partner", Balance: 100.0})
+
+	cfg := &config.Config{Webhooks: config.WebhooksConfig{
+		Timeout:      time.Second,
+		MaxAttempts:  maxAttempts,
+		RetryBackoff: time.Minute,
+		MaxBackoff:   time.Hour,
+	}}
+	return NewWebhookService(repo, accounts, nil), NewWebhookDispatcher(repo, cache.NewMemoryCache(), cfg), repo
+}
+
+func TestWebhookSubscription(t *testing.T) {
+	// Setup
+	webhooks, _, _ := newTestWebhooks(t, 3)
+	ctx := context.Background()
+
+	tests := []struct {
+		req webhook.CreateSubscriptionRequest
+		err error
+	}{
+		{webhook.CreateSubscriptionRequest{AccountId: "partner", URL: "ftp://example.com/hook"}, ErrInvalidWebhookURL},
+		{webhook.CreateSubscriptionRequest{AccountId: "partner", URL: "https://example.com/hook", EventTypes: []string{event.TypeAccountCreated}}, ErrInvalidWebhookEvent},
+		{webhook.CreateSubscriptionRequest{AccountId: "unknown", URL: "https://example.com/hook"}, ErrAccountNotFound},
+	}
+	for _, tc := range tests {
+		if _, err := webhooks.CreateSubscription(ctx, tc.req); !errors.Is(err, tc.err) {
+			t.Errorf("Expected %v, got %v", tc.err, err)
+		}
+	}
+
+	created, err := webhooks.CreateSubscription(ctx, webhook.CreateSubscriptionRequest{AccountId: "partner", URL: "https://example.com/hook"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if created.Secret == "" || !created.Active {
+		t.Errorf("Expected an active subscription with a generated secret, got %+v", created)
+	}
+
+	listed, _ := webhooks.ListSubscriptions(ctx)
+	if len(listed) != 1 || listed[0].Secret != "" {
+		t.Errorf("Expected the listed subscription without its secret, got %+v", listed)
+	}
+
+	if err := webhooks.DeleteSubscription(ctx, created.SubscriptionId); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	listed, _ = webhooks.ListSubscriptions(ctx)
+	if listed[0].Active {
+		t.Errorf("Expected the deleted subscription to be inactive")
+	}
+}
+
+func TestWebhookSignature(t *testing.T) {
+	body := []byte(`{"event_type":"TransferCompleted"}`)
+	now := time.Now()
+	signature := SignWebhookPayload("secret", now.Unix(), body)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	if err := VerifyWebhookSignature("secret", signature, timestamp, body, WebhookSignatureTolerance, now); err != nil {
+		t.Errorf("Expected the signature to verify, got %v", err)
+	}
+	if err := VerifyWebhookSignature("other", signature, timestamp, body, WebhookSignatureTolerance, now); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for the wrong secret, got %v", err)
+	}
+	if err := VerifyWebhookSignature("secret", signature, timestamp, []byte(`{}`), WebhookSignatureTolerance, now); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for a tampered body, got %v", err)
+	}
+	if err := VerifyWebhookSignature("secret", signature, timestamp, body, WebhookSignatureTolerance, now.Add(time.Hour)); !errors.Is(err, ErrSignatureExpired) {
+		t.Errorf("Expected ErrSignatureExpired for a replayed call, got %v", err)
+	}
+}
+
+func TestWebhookDispatchRetriesWithBackoff(t *testing.T) {
+	// Setup
+	webhooks, dispatcher, repo := newTestWebhooks(t, 5)
+	ctx := context.Background()
+	receiver := newWebhookReceiver(t, "whsec_test", http.StatusInternalServerError, http.StatusBadGateway)
+
+	created, err := webhooks.CreateSubscription(ctx, webhook.CreateSubscriptionRequest{AccountId: "partner", URL: receiver.server.URL, Secret: "whsec_test"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	deliveryId := repo.enqueue(uuid.MustParse(created.SubscriptionId), event.TypeTransferCompleted, `{"event_type":"TransferCompleted"}`)
+
+	// Each failure pushes the next attempt out, doubling the delay
+	for i, backoff := range []time.Duration{time.Minute, 2 * time.Minute} {
+		before := time.Now().UTC()
+		if succeeded, err := dispatcher.DispatchDue(ctx); err != nil || succeeded != 0 {
+			t.Fatalf("Expected a failed attempt, got %d delivered (%v)", succeeded, err)
+		}
+		delivery, _ := repo.GetDelivery(ctx, deliveryId)
+		if delivery.Status != webhook.DeliveryPending || delivery.Attempts != i+1 || delivery.LastError == "" {
+			t.Fatalf("Expected a pending delivery after %d attempts, got %+v", i+1, delivery)
+		}
+		if delay := delivery.NextAttemptAt.Sub(before); delay < backoff || delay > backoff+time.Second {
+			t.Errorf("Expected the next attempt in %s, got %s", backoff, delay)
+		}
+
+		// nothing is sent before the retry is due
+		if succeeded, _ := dispatcher.DispatchDue(ctx); succeeded != 0 || len(receiver.received) != i+1 {
+			t.Errorf("Expected no call before the retry is due, got %d calls", len(receiver.received))
+		}
+		repo.makeDue()
+	}
+
+	succeeded, err := dispatcher.DispatchDue(ctx)
+	if err != nil || succeeded != 1 {
+		t.Fatalf("Expected the third attempt to succeed, got %d delivered (%v)", succeeded, err)
+	}
+	if receiver.invalid != 0 {
+		t.Errorf("Expected every call to carry a valid signature, got %d invalid", receiver.invalid)
+	}
+
+	delivery, err := webhooks.GetDelivery(ctx, created.SubscriptionId, deliveryId.String())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if delivery.Status != webhook.DeliverySucceeded || delivery.DeliveredAt == nil || len(delivery.Log) != 3 {
+		t.Errorf("Expected a succeeded delivery with 3 logged attempts, got %+v", delivery)
+	}
+	if delivery.Log[0].StatusCode != http.StatusInternalServerError || delivery.Log[2].StatusCode != http.StatusOK {
+		t.Errorf("Expected the log to record each response status, got %+v", delivery.Log)
+	}
+}
+
+func TestWebhookDeadLetterAndReplay(t *testing.T) {
+	// Setup
+	webhooks, dispatcher, repo := newTestWebhooks(t, 2)
+	ctx := context.Background()
+	receiver := newWebhookReceiver(t, "whsec_test", http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+
+	created, _ := webhooks.CreateSubscription(ctx, webhook.CreateSubscriptionRequest{AccountId: "partner", URL: receiver.server.URL, Secret: "whsec_test"})
+	deliveryId := repo.enqueue(uuid.MustParse(created.SubscriptionId), event.TypeTransferFailed, `{"event_type":"TransferFailed"}`)
+
+	dispatcher.DispatchDue(ctx)
+	repo.makeDue()
+	dispatcher.DispatchDue(ctx)
+
+	delivery, _ := webhooks.GetDelivery(ctx, created.SubscriptionId, deliveryId.String())
+	if delivery.Status != webhook.DeliveryDeadLettered || delivery.NextAttemptAt != nil {
+		t.Fatalf("Expected the delivery to be dead-lettered after 2 attempts, got %+v", delivery)
+	}
+
+	if _, err := webhooks.ReplayDelivery(ctx, uuid.NewString(), deliveryId.String()); !errors.Is(err, ErrSubscriptionNotFound) {
+		t.Errorf("Expected ErrSubscriptionNotFound, got %v", err)
+	}
+	replayed, err := webhooks.ReplayDelivery(ctx, created.SubscriptionId, deliveryId.String())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if replayed.Status != webhook.DeliveryPending || replayed.Attempts != 0 {
+		t.Errorf("Expected the replay to restart the delivery, got %+v", replayed)
+	}
+
+	if succeeded, err := dispatcher.DispatchDue(ctx); err != nil || succeeded != 1 {
+		t.Fatalf("Expected the replayed delivery to succeed, got %d delivered (%v)", succeeded, err)
+	}
+	delivery, _ = webhooks.GetDelivery(ctx, created.SubscriptionId, deliveryId.String())
+	if delivery.Status != webhook.DeliverySucceeded || len(delivery.Log) != 3 {
+		t.Errorf("Expected a succeeded delivery keeping the earlier attempts in its log, got %+v", delivery)
+	}
+
+	// deliveries of a deleted subscription are dead-lettered without a call
+	pendingId := repo.enqueue(uuid.MustParse(created.SubscriptionId), event.TypeTransferCompleted, `{}`)
+	webhooks.DeleteSubscription(ctx, created.SubscriptionId)
+	dispatcher.DispatchDue(ctx)
+	pending, _ := repo.GetDelivery(ctx, pendingId)
+	if pending.Status != webhook.DeliveryDeadLettered || len(receiver.received) != 3 {
+		t.Errorf("Expected the delivery to be dead-lettered without a call, got %s after %d calls", pending.Status, len(receiver.received))
+	}
+}
+
+func TestWebhookDispatchersClaimDeliveries(t *testing.T) {
+	// Setup
+	database, migrator := newTestSQLiteDB(t)
+	ctx := context.Background()
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	repo := repository.NewWebhookRepo(database)
+	cfg := &config.Config{Webhooks: config.WebhooksConfig{Timeout: time.Second, MaxAttempts: 3, RetryBackoff: time.Minute, MaxBackoff: time.Hour}}
+	// dispatchers whose locks do not exclude each other, as when the lock of one lapsed
+	first := NewWebhookDispatcher(repo, cache.NewMemoryCache(), cfg)
+	second := NewWebhookDispatcher(repo, cache.NewMemoryCache(), cfg)
+
+	calls := 0
+	var secondSucceeded int
+	var secondErr error
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			// the second dispatcher runs while the first is sending the delivery
+			secondSucceeded, secondErr = second.DispatchDue(ctx)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	subscription := &webhook.Subscription{AccountId: "partner", URL: server.URL, Secret: "whsec_test", Active: true}
+	if err := repo.CreateSubscription(ctx, subscription); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	now := time.Now().UTC().Add(-time.Second)
+	delivery := &webhook.Delivery{SubscriptionId: subscription.ID, EventId: uuid.New(), EventType: event.TypeTransferCompleted,
+		Payload: `{}`, Status: webhook.DeliveryPending, NextAttemptAt: &now}
+	if err := database.GetConnection().Create(delivery).Error; err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Test case: a delivery claimed by one dispatcher is not sent by another
+	if succeeded, err := first.DispatchDue(ctx); err != nil || succeeded != 1 {
+		t.Fatalf("Expected the first dispatcher to deliver, got %d delivered (%v)", succeeded, err)
+	}
+	if secondErr != nil || secondSucceeded != 0 || calls != 1 {
+		t.Errorf("Expected a single call, got %d calls and %d delivered by the second dispatcher (%v)", calls, secondSucceeded, secondErr)
+	}
+	stored, _ := repo.GetDelivery(ctx, delivery.ID)
+	if stored.Status != webhook.DeliverySucceeded || stored.Attempts != 1 {
+		t.Errorf("Expected a delivery succeeded at the first attempt, got %+v", stored)
+	}
+
+	// Test case: the outcome of a claim is not recorded over a replay made since
+	listed := *stored
+	listed.Status, listed.Attempts = webhook.DeliveryPending, 0
+	if err := repo.UpdateDelivery(ctx, &listed); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	claimed := listed
+	if ok, err := repo.ClaimDelivery(ctx, &claimed, time.Now().Add(time.Minute)); err != nil || !ok {
+		t.Fatalf("Expected the delivery to be claimed, got %v (%v)", ok, err)
+	}
+	if ok, _ := repo.ClaimDelivery(ctx, &listed, time.Now().Add(time.Minute)); ok {
+		t.Errorf("Expected a delivery to be claimed once")
+	}
+	replayed := claimed
+	replayed.Attempts = 0
+	repo.UpdateDelivery(ctx, &replayed)
+	claimed.Status = webhook.DeliverySucceeded
+	if err := repo.RecordAttempt(ctx, &claimed, &webhook.DeliveryAttempt{DeliveryId: claimed.ID, Attempt: 1}); !errors.Is(err, webhook.ErrDeliveryReclaimed) {
+		t.Errorf("Expected ErrDeliveryReclaimed, got %v", err)
+	}
+}
@@ -3,23 +3,59 @@ package main
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
+	"text/tabwriter"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/cobra"
 
+	"internal-transfer-microservice/api"
+	"internal-transfer-microservice/internal/cli"
 	"internal-transfer-microservice/internal/config"
+	"internal-transfer-microservice/internal/controller"
+	"internal-transfer-microservice/internal/domain/access"
+	"internal-transfer-microservice/internal/domain/interest"
+	"internal-transfer-microservice/internal/domain/migration"
 	"internal-transfer-microservice/internal/factory"
+	"internal-transfer-microservice/internal/middleware"
 	"internal-transfer-microservice/internal/routes"
+	"internal-transfer-microservice/pkg/client"
 	"internal-transfer-microservice/pkg/logger"
 )
 
 var (
-	configPath string
+	configPath    string
+	accrualDate   string
+	postingMonth  string
+	watchlistPath string
+	relayOnce     bool
+	dispatchOnce  bool
+	projectOnce   bool
+	migrateSteps  int
+	inMemory      bool
+
+	// flags of the operator commands
+	serverURL      string
+	operatorId     string
+	apiToken       string
+	outputFormat   string
+	direct         bool
+	holderName     string
+	ownerId        string
+	initialBalance float64
+	accountType    string
+	ratePlanId     string
+	accountTier    string
+	listAfter      string
+	listLimit      int
+	freezeReason   string
+	idempotencyKey string
 )
 
 func main() {
@@ -34,7 +70,7 @@ func main() {
 	apiCmd := &cobra.Command{
 		Use:   "api",
 		Short: "Start the API server",
-		Long:  `Start the REST API server for account transfers.`,
+		Long:  `Start the REST API server for account transfers, and the gRPC server next to it.`,
 		Run:   runAPI,
 	}
 
@@ -42,17 +78,243 @@ func main() {
 	migrateCmd := &cobra.Command{
 		Use:   "migrate",
 		Short: "Run database migrations",
-		Long:  `Run database migrations to set up or update the database schema.`,
+		Long:  `Run database migrations to set up or update the database schema. Without a subcommand, applies every pending migration.`,
+		Run:   runMigrate,
+	}
+	migrateUpCmd := &cobra.Command{
+		Use:   "up",
+		Short: "Apply pending migrations",
+		Long:  `Apply every pending migration in version order.`,
 		Run:   runMigrate,
 	}
+	migrateDownCmd := &cobra.Command{
+		Use:   "down",
+		Short: "Revert applied migrations",
+		Long:  `Revert the last applied migrations, newest first, with their down scripts.`,
+		Run:   runMigrateDown,
+	}
+	migrateToCmd := &cobra.Command{
+		Use:   "to <version>",
+		Short: "Migrate to a version",
+		Long:  `Apply or revert migrations until the given version is the last one applied; version 0 reverts every migration.`,
+		Args:  cobra.ExactArgs(1),
+		Run:   runMigrateTo,
+	}
+	migrateStatusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show migration status",
+		Long:  `List every migration with whether and when it was applied, flagging applied migrations that were modified or are missing.`,
+		Run:   runMigrateStatus,
+	}
+
+	// Interest command
+	interestCmd := &cobra.Command{
+		Use:   "interest",
+		Short: "Run interest jobs",
+		Long:  `Accrue daily interest on accounts attached to a rate plan and post accrued interest monthly.`,
+	}
+	interestAccrueCmd := &cobra.Command{
+		Use:   "accrue",
+		Short: "Accrue one day of interest",
+		Long:  `Accrue one day of interest on every interest-bearing account. Running it twice for the same day is a no-op.`,
+		Run:   runInterestAccrue,
+	}
+	interestPostCmd := &cobra.Command{
+		Use:   "post",
+		Short: "Post accrued interest",
+		Long:  `Transfer the interest accrued up to the end of a month from the funding account to each account.`,
+		Run:   runInterestPost,
+	}
+
+	// Approvals command
+	approvalsCmd := &cobra.Command{
+		Use:   "approvals",
+		Short: "Manage transfers waiting for approval",
+		Long:  `Manage transfers held for a second operator's approval or for manual review.`,
+	}
+	approvalsExpireCmd := &cobra.Command{
+		Use:   "expire",
+		Short: "Expire pending transfers",
+		Long:  `Expire pending transfers whose approval window has passed.`,
+		Run:   runApprovalsExpire,
+	}
+
+	// Screening command
+	screeningCmd := &cobra.Command{
+		Use:   "screening",
+		Short: "Manage watchlist screening",
+		Long:  `Manage the watchlist transfers are screened against.`,
+	}
+	screeningReloadCmd := &cobra.Command{
+		Use:   "reload",
+		Short: "Reload the watchlist",
+		Long:  `Replace the stored watchlist with the entries of an OFAC-style CSV or XML file; running servers pick it up on their next screening.`,
+		Run:   runScreeningReload,
+	}
+
+	// Outbox command
+	outboxCmd := &cobra.Command{
+		Use:   "outbox",
+		Short: "Manage the event outbox",
+		Long:  `Manage the outbox of domain events recorded with account and transfer changes.`,
+	}
+	outboxRelayCmd := &cobra.Command{
+		Use:   "relay",
+		Short: "Publish pending outbox events",
+		Long:  `Publish pending outbox events in order and mark them sent, until interrupted or, with --once, until the outbox is drained.`,
+		Run:   runOutboxRelay,
+	}
+
+	// Webhooks command
+	webhooksCmd := &cobra.Command{
+		Use:   "webhooks",
+		Short: "Manage outgoing webhooks",
+		Long:  `Manage the webhook calls sent to partners when transfers touching their accounts complete.`,
+	}
+	webhooksDispatchCmd := &cobra.Command{
+		Use:   "dispatch",
+		Short: "Send due webhook deliveries",
+		Long:  `Send signed webhook deliveries as they become due, retrying failures with exponential backoff until they are dead-lettered, until interrupted or, with --once, after one pass.`,
+		Run:   runWebhooksDispatch,
+	}
+
+	// Accounts command
+	accountsCmd := &cobra.Command{
+		Use:     "accounts",
+		Aliases: []string{"account"},
+		Short:   "Operate accounts and manage the account store",
+		Long:    `Create, read, list and freeze accounts through the API, or directly with --direct, and manage how accounts are stored.`,
+	}
+	accountsCreateCmd := &cobra.Command{
+		Use:   "create <account-id>",
+		Short: "Open an account",
+		Args:  cobra.ExactArgs(1),
+		Run:   runAccountsCreate,
+	}
+	accountsGetCmd := &cobra.Command{
+		Use:   "get <account-id>",
+		Short: "Show an account",
+		Args:  cobra.ExactArgs(1),
+		Run:   runAccountsGet,
+	}
+	accountsListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List a page of accounts",
+		Long:  `List accounts ordered by id, a page at a time; pass the last id of a page to --after to list the next one.`,
+		Args:  cobra.NoArgs,
+		Run:   runAccountsList,
+	}
+	accountsFreezeCmd := &cobra.Command{
+		Use:   "freeze <account-id>",
+		Short: "Freeze an account",
+		Long:  `Freeze an account, so that it can neither send nor receive transfers until it is unfrozen.`,
+		Args:  cobra.ExactArgs(1),
+		Run:   runAccountsFreeze,
+	}
+	accountsUnfreezeCmd := &cobra.Command{
+		Use:   "unfreeze <account-id>",
+		Short: "Unfreeze an account",
+		Args:  cobra.ExactArgs(1),
+		Run:   runAccountsFreeze,
+	}
+
+	// Transfer command
+	transferCmd := &cobra.Command{
+		Use:   "transfer <source-account-id> <destination-account-id> <amount>",
+		Short: "Transfer money between accounts",
+		Long:  `Transfer money between accounts through the API, or directly with --direct. Transfers held for review or approval are shown with their status.`,
+		Args:  cobra.ExactArgs(3),
+		Run:   runTransfer,
+	}
+	accountsSeedEventsCmd := &cobra.Command{
+		Use:   "seed-events",
+		Short: "Open event streams for existing accounts",
+		Long:  `Open an event stream for every account row that has none, starting from the row's current state, before switching accounts.store to event_sourced.`,
+		Run:   runAccountsSeedEvents,
+	}
+
+	// Read model command
+	readModelCmd := &cobra.Command{
+		Use:   "readmodel",
+		Short: "Manage the account read model",
+		Long:  `Manage the denormalized account summaries that account reads are served from.`,
+	}
+	readModelProjectCmd := &cobra.Command{
+		Use:   "project",
+		Short: "Apply outbox events to the read model",
+		Long:  `Apply unprojected outbox events to the account summaries in order, until interrupted or, with --once, until none are left.`,
+		Run:   runReadModelProject,
+	}
+	readModelRebuildCmd := &cobra.Command{
+		Use:   "rebuild",
+		Short: "Rebuild the read model",
+		Long:  `Replace the account summaries with ones built from the accounts and transfers tables, and mark the events they cover projected.`,
+		Run:   runReadModelRebuild,
+	}
 
 	// Add flags to commands
 	apiCmd.Flags().StringVar(&configPath, "config", "", "Path to configuration file")
-	migrateCmd.Flags().StringVar(&configPath, "config", "", "Path to configuration file")
+	apiCmd.Flags().BoolVar(&inMemory, "in-memory", false, "Keep all data in memory, without Postgres or Redis, for demos")
+	migrateCmd.PersistentFlags().StringVar(&configPath, "config", "", "Path to configuration file")
+	migrateDownCmd.Flags().IntVar(&migrateSteps, "steps", 1, "Number of migrations to revert")
+	interestCmd.PersistentFlags().StringVar(&configPath, "config", "", "Path to configuration file")
+	interestAccrueCmd.Flags().StringVar(&accrualDate, "date", "", "Day to accrue interest for (YYYY-MM-DD), defaults to yesterday")
+	interestPostCmd.Flags().StringVar(&postingMonth, "month", "", "Month to post interest for (YYYY-MM), defaults to last month")
+	approvalsCmd.PersistentFlags().StringVar(&configPath, "config", "", "Path to configuration file")
+	screeningCmd.PersistentFlags().StringVar(&configPath, "config", "", "Path to configuration file")
+	screeningReloadCmd.Flags().StringVar(&watchlistPath, "file", "", "Watchlist file to load, defaults to screening.watchlist_path")
+	outboxCmd.PersistentFlags().StringVar(&configPath, "config", "", "Path to configuration file")
+	outboxRelayCmd.Flags().BoolVar(&relayOnce, "once", false, "Drain the outbox once and exit")
+	webhooksCmd.PersistentFlags().StringVar(&configPath, "config", "", "Path to configuration file")
+	webhooksDispatchCmd.Flags().BoolVar(&dispatchOnce, "once", false, "Send the deliveries due now and exit")
+	accountsCmd.PersistentFlags().StringVar(&configPath, "config", "", "Path to configuration file")
+	for _, cmd := range []*cobra.Command{accountsCreateCmd, accountsGetCmd, accountsListCmd, accountsFreezeCmd, accountsUnfreezeCmd, transferCmd} {
+		addOperatorFlags(cmd)
+	}
+	accountsCreateCmd.Flags().StringVar(&holderName, "holder", "", "Name of the account holder")
+	accountsCreateCmd.Flags().StringVar(&ownerId, "owner", "", "Principal owning the account, required with --direct")
+	accountsCreateCmd.Flags().Float64Var(&initialBalance, "balance", 0, "Initial balance")
+	accountsCreateCmd.Flags().StringVar(&accountType, "type", "", "Account type, defaults to the service's default")
+	accountsCreateCmd.Flags().StringVar(&ratePlanId, "rate-plan", "", "Interest rate plan")
+	accountsCreateCmd.Flags().StringVar(&accountTier, "tier", "", "Limit tier, defaults to the service's default")
+	accountsListCmd.Flags().StringVar(&listAfter, "after", "", "List the accounts after this id")
+	accountsListCmd.Flags().IntVar(&listLimit, "limit", 0, "Number of accounts to list, defaults to the service's page size")
+	accountsFreezeCmd.Flags().StringVar(&freezeReason, "reason", "", "Why the account is frozen, recorded with the change")
+	accountsUnfreezeCmd.Flags().StringVar(&freezeReason, "reason", "", "Why the account is unfrozen, recorded with the change")
+	transferCmd.Flags().StringVar(&configPath, "config", "", "Path to configuration file")
+	transferCmd.Flags().StringVar(&idempotencyKey, "idempotency-key", "", "Key making the transfer safe to run again, generated when empty")
+	readModelCmd.PersistentFlags().StringVar(&configPath, "config", "", "Path to configuration file")
+	readModelProjectCmd.Flags().BoolVar(&projectOnce, "once", false, "Apply the pending events once and exit")
 
 	// Add commands to root command
+	migrateCmd.AddCommand(migrateUpCmd)
+	migrateCmd.AddCommand(migrateDownCmd)
+	migrateCmd.AddCommand(migrateToCmd)
+	migrateCmd.AddCommand(migrateStatusCmd)
+	interestCmd.AddCommand(interestAccrueCmd)
+	interestCmd.AddCommand(interestPostCmd)
+	approvalsCmd.AddCommand(approvalsExpireCmd)
+	screeningCmd.AddCommand(screeningReloadCmd)
+	outboxCmd.AddCommand(outboxRelayCmd)
+	webhooksCmd.AddCommand(webhooksDispatchCmd)
+	accountsCmd.AddCommand(accountsCreateCmd)
+	accountsCmd.AddCommand(accountsGetCmd)
+	accountsCmd.AddCommand(accountsListCmd)
+	accountsCmd.AddCommand(accountsFreezeCmd)
+	accountsCmd.AddCommand(accountsUnfreezeCmd)
+	accountsCmd.AddCommand(accountsSeedEventsCmd)
+	readModelCmd.AddCommand(readModelProjectCmd)
+	readModelCmd.AddCommand(readModelRebuildCmd)
 	rootCmd.AddCommand(apiCmd)
 	rootCmd.AddCommand(migrateCmd)
+	rootCmd.AddCommand(interestCmd)
+	rootCmd.AddCommand(approvalsCmd)
+	rootCmd.AddCommand(screeningCmd)
+	rootCmd.AddCommand(outboxCmd)
+	rootCmd.AddCommand(webhooksCmd)
+	rootCmd.AddCommand(accountsCmd)
+	rootCmd.AddCommand(transferCmd)
+	rootCmd.AddCommand(readModelCmd)
 
 	// Execute
 	if err := rootCmd.Execute(); err != nil {
@@ -62,6 +324,29 @@ func main() {
 }
 
 func runMigrate(cmd *cobra.Command, args []string) {
+	runMigrations(cmd, func(ctx context.Context, migrator migration.Service) ([]migration.Step, error) {
+		return migrator.Up(ctx)
+	})
+}
+
+func runMigrateDown(cmd *cobra.Command, args []string) {
+	runMigrations(cmd, func(ctx context.Context, migrator migration.Service) ([]migration.Step, error) {
+		return migrator.Down(ctx, migrateSteps)
+	})
+}
+
+func runMigrateTo(cmd *cobra.Command, args []string) {
+	version, err := strconv.Atoi(args[0])
+	if err != nil || version < 0 {
+		logger.Fatalf("Invalid version %q", args[0])
+	}
+	runMigrations(cmd, func(ctx context.Context, migrator migration.Service) ([]migration.Step, error) {
+		return migrator.To(ctx, version)
+	})
+}
+
+// runMigrations runs migrate with the migrator of the configured database and reports the steps it took
+func runMigrations(cmd *cobra.Command, migrate func(ctx context.Context, migrator migration.Service) ([]migration.Step, error)) {
 	// Load configuration
 	cfg, err := config.LoadConfig(configPath)
 	if err != nil {
@@ -75,12 +360,447 @@ func runMigrate(cmd *cobra.Command, args []string) {
 	}
 	defer appFactory.Close()
 
+	migrator, err := appFactory.CreateMigrator()
+	if err != nil {
+		logger.Fatalf("Failed to load migrations: %v", err)
+	}
+
 	// Run database migrations
 	logger.Info("Running database migrations...")
-	if err := appFactory.MigrateDB(); err != nil {
-		logger.Fatalf("Failed to migrate database: %v", err)
+	steps, err := migrate(cmd.Context(), migrator)
+	if err != nil {
+		logger.Fatalf("Failed to migrate database after %d migrations: %v", len(steps), err)
+	}
+	if len(steps) == 0 {
+		logger.Info("Database schema is up to date")
+		return
+	}
+	logger.Infof("Database migrations completed successfully: %d migrations run", len(steps))
+}
+
+func runMigrateStatus(cmd *cobra.Command, args []string) {
+	// Load configuration
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		logger.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Create factory
+	appFactory, err := factory.NewFactory(cfg)
+	if err != nil {
+		logger.Fatalf("Failed to create factory: %v", err)
+	}
+	defer appFactory.Close()
+
+	migrator, err := appFactory.CreateMigrator()
+	if err != nil {
+		logger.Fatalf("Failed to load migrations: %v", err)
+	}
+	statuses, err := migrator.Status(cmd.Context())
+	if err != nil {
+		logger.Fatalf("Failed to read migration status: %v", err)
+	}
+
+	table := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, status := range statuses {
+		state, appliedAt := "pending", ""
+		if status.Applied {
+			state, appliedAt = "applied", status.AppliedAt.Format(time.RFC3339)
+		}
+		if status.Modified {
+			state = "modified"
+		}
+		if status.Missing {
+			state = "missing"
+		}
+		fmt.Fprintf(table, "%d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+	}
+	table.Flush()
+}
+
+func runInterestAccrue(cmd *cobra.Command, args []string) {
+	// Resolve the accrual day
+	date := time.Now().UTC().AddDate(0, 0, -1)
+	if accrualDate != "" {
+		parsed, err := time.Parse(interest.DateLayout, accrualDate)
+		if err != nil {
+			logger.Fatalf("Invalid --date %q: %v", accrualDate, err)
+		}
+		date = parsed
+	}
+
+	// Load configuration
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		logger.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Create factory
+	appFactory, err := factory.NewFactory(cfg)
+	if err != nil {
+		logger.Fatalf("Failed to create factory: %v", err)
+	}
+	defer appFactory.Close()
+
+	// Run the accrual job
+	logger.Infof("Accruing interest for %s...", date.Format(interest.DateLayout))
+	summary, err := appFactory.CreateInterestService().AccrueDaily(cmd.Context(), date)
+	if err != nil {
+		logger.Fatalf("Failed to accrue interest: %v", err)
+	}
+	logger.Infof("Interest accrual completed: %d accrued, %d already accrued, %d skipped, total %.6f",
+		summary.Accrued, summary.Existing, summary.Skipped, summary.Total)
+}
+
+func runInterestPost(cmd *cobra.Command, args []string) {
+	// Resolve the posting month
+	now := time.Now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+	if postingMonth != "" {
+		parsed, err := time.Parse(interest.MonthLayout, postingMonth)
+		if err != nil {
+			logger.Fatalf("Invalid --month %q: %v", postingMonth, err)
+		}
+		month = parsed
+	}
+
+	// Load configuration
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		logger.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Create factory
+	appFactory, err := factory.NewFactory(cfg)
+	if err != nil {
+		logger.Fatalf("Failed to create factory: %v", err)
+	}
+	defer appFactory.Close()
+
+	// Run the posting job
+	logger.Infof("Posting interest for %s...", month.Format(interest.MonthLayout))
+	summary, err := appFactory.CreateInterestService().PostMonthly(cmd.Context(), month)
+	if err != nil {
+		logger.Fatalf("Failed to post interest: %v", err)
+	}
+	logger.Infof("Interest posting completed: %d accounts credited, total %.2f", summary.Posted, summary.Total)
+}
+
+func runApprovalsExpire(cmd *cobra.Command, args []string) {
+	// Load configuration
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		logger.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Create factory
+	appFactory, err := factory.NewFactory(cfg)
+	if err != nil {
+		logger.Fatalf("Failed to create factory: %v", err)
+	}
+	defer appFactory.Close()
+
+	// Expire pending transfers
+	expired, err := appFactory.CreateAccountService().ExpirePendingTransfers(cmd.Context())
+	if err != nil {
+		logger.Fatalf("Failed to expire pending transfers: %v", err)
+	}
+	logger.Infof("Expired %d pending transfers", expired)
+}
+
+func runScreeningReload(cmd *cobra.Command, args []string) {
+	// Load configuration
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		logger.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	path := watchlistPath
+	if path == "" {
+		path = cfg.GetWatchlistPath()
+	}
+
+	// Create factory
+	appFactory, err := factory.NewFactory(cfg)
+	if err != nil {
+		logger.Fatalf("Failed to create factory: %v", err)
+	}
+	defer appFactory.Close()
+
+	// Reload the watchlist
+	summary, err := appFactory.CreateScreeningService().ReloadWatchlist(cmd.Context(), path)
+	if err != nil {
+		logger.Fatalf("Failed to reload watchlist: %v", err)
 	}
-	logger.Info("Database migrations completed successfully")
+	logger.Infof("Loaded %d watchlist entries from %s (version %s)", summary.Entries, summary.Source, summary.Version)
+}
+
+func runOutboxRelay(cmd *cobra.Command, args []string) {
+	// Load configuration
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		logger.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Create factory
+	appFactory, err := factory.NewFactory(cfg)
+	if err != nil {
+		logger.Fatalf("Failed to create factory: %v", err)
+	}
+	defer appFactory.Close()
+
+	eventPublisher, err := appFactory.CreateEventPublisher()
+	if err != nil {
+		logger.Fatalf("Failed to create event publisher: %v", err)
+	}
+	defer eventPublisher.Close()
+	relay := appFactory.CreateOutboxRelay(eventPublisher)
+
+	if relayOnce {
+		sent, err := relay.RelayPending(cmd.Context())
+		if err != nil {
+			logger.Fatalf("Failed to relay outbox events after %d sent: %v", sent, err)
+		}
+		logger.Infof("Relayed %d outbox events", sent)
+		return
+	}
+
+	// Relay until interrupted
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	logger.Infof("Relaying outbox events every %s", cfg.GetOutboxRelayInterval())
+	if err := relay.Run(ctx); err != nil {
+		logger.Fatalf("Outbox relay stopped: %v", err)
+	}
+	logger.Info("Outbox relay stopped")
+}
+
+func runWebhooksDispatch(cmd *cobra.Command, args []string) {
+	// Load configuration
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		logger.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Create factory
+	appFactory, err := factory.NewFactory(cfg)
+	if err != nil {
+		logger.Fatalf("Failed to create factory: %v", err)
+	}
+	defer appFactory.Close()
+
+	dispatcher := appFactory.CreateWebhookDispatcher()
+
+	if dispatchOnce {
+		succeeded, err := dispatcher.DispatchDue(cmd.Context())
+		if err != nil {
+			logger.Fatalf("Failed to dispatch webhooks after %d delivered: %v", succeeded, err)
+		}
+		logger.Infof("Delivered %d webhooks", succeeded)
+		return
+	}
+
+	// Dispatch until interrupted
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	logger.Infof("Dispatching webhooks every %s", cfg.GetWebhookDispatchInterval())
+	if err := dispatcher.Run(ctx); err != nil {
+		logger.Fatalf("Webhook dispatcher stopped: %v", err)
+	}
+	logger.Info("Webhook dispatcher stopped")
+}
+
+func runAccountsSeedEvents(cmd *cobra.Command, args []string) {
+	// Load configuration
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		logger.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Create factory
+	appFactory, err := factory.NewFactory(cfg)
+	if err != nil {
+		logger.Fatalf("Failed to create factory: %v", err)
+	}
+	defer appFactory.Close()
+
+	seeded, err := appFactory.CreateEventSourcedAccountRepo().SeedStreams(cmd.Context())
+	if err != nil {
+		logger.Fatalf("Failed to seed account streams after %d: %v", seeded, err)
+	}
+	logger.Infof("Opened %d account event streams", seeded)
+}
+
+// addOperatorFlags adds the flags choosing where an operator command runs, on whose behalf, and how its
+// result is printed
+func addOperatorFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&serverURL, "server", "http://localhost:3000", "Base URL of the API")
+	cmd.Flags().StringVar(&operatorId, "operator", os.Getenv("USER"), "Operator the command acts on behalf of")
+	cmd.Flags().StringVar(&apiToken, "token", os.Getenv("TRANSFER_API_TOKEN"), "Bearer token of the API when auth is enabled, defaults to $TRANSFER_API_TOKEN")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", cli.FormatTable, "Output format: table or json")
+	cmd.Flags().BoolVar(&direct, "direct", false, "Run against the database configured by --config instead of the API")
+}
+
+// operatorSession returns the operations and printer of an operator command, and a func releasing them
+func operatorSession() (cli.Operations, *cli.Printer, func()) {
+	printer, err := cli.NewPrinter(os.Stdout, outputFormat)
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	if !direct {
+		return client.New(serverURL, client.WithOperator(operatorId), client.WithBearerToken(apiToken)), printer, func() {}
+	}
+
+	// Load configuration
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		logger.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Create factory
+	appFactory, err := factory.NewFactory(cfg)
+	if err != nil {
+		logger.Fatalf("Failed to create factory: %v", err)
+	}
+	return cli.NewDirectOperations(appFactory.CreateAccountService(), operatorId), printer, appFactory.Close
+}
+
+func runAccountsCreate(cmd *cobra.Command, args []string) {
+	operations, printer, release := operatorSession()
+	defer release()
+
+	err := operations.CreateAccount(cmd.Context(), client.CreateAccountRequest{
+		AccountId:      args[0],
+		HolderName:     holderName,
+		OwnerId:        ownerId,
+		InitialBalance: initialBalance,
+		AccountType:    accountType,
+		RatePlanId:     ratePlanId,
+		Tier:           accountTier,
+	})
+	if err != nil {
+		logger.Fatalf("Failed to create account %s: %v", args[0], err)
+	}
+	acc, err := operations.GetAccount(cmd.Context(), args[0])
+	if err != nil {
+		logger.Fatalf("Failed to read account %s: %v", args[0], err)
+	}
+	printer.Accounts(*acc)
+}
+
+func runAccountsGet(cmd *cobra.Command, args []string) {
+	operations, printer, release := operatorSession()
+	defer release()
+
+	acc, err := operations.GetAccount(cmd.Context(), args[0])
+	if err != nil {
+		logger.Fatalf("Failed to read account %s: %v", args[0], err)
+	}
+	printer.Accounts(*acc)
+}
+
+func runAccountsList(cmd *cobra.Command, args []string) {
+	operations, printer, release := operatorSession()
+	defer release()
+
+	page, err := operations.ListAccounts(cmd.Context(), client.ListAccountsOptions{After: listAfter, Limit: listLimit})
+	if err != nil {
+		logger.Fatalf("Failed to list accounts: %v", err)
+	}
+	printer.AccountPage(page)
+}
+
+// runAccountsFreeze runs both freeze and unfreeze, telling them apart by the command's name
+func runAccountsFreeze(cmd *cobra.Command, args []string) {
+	operations, printer, release := operatorSession()
+	defer release()
+
+	acc, err := operations.FreezeAccount(cmd.Context(), args[0], client.FreezeAccountRequest{
+		Frozen: cmd.Name() == "freeze",
+		Reason: freezeReason,
+	})
+	if err != nil {
+		logger.Fatalf("Failed to %s account %s: %v", cmd.Name(), args[0], err)
+	}
+	printer.Accounts(*acc)
+}
+
+func runTransfer(cmd *cobra.Command, args []string) {
+	amount, err := strconv.ParseFloat(args[2], 64)
+	if err != nil || amount <= 0 {
+		logger.Fatalf("Invalid amount %q", args[2])
+	}
+
+	operations, printer, release := operatorSession()
+	defer release()
+
+	result, err := operations.Transfer(cmd.Context(), client.TransferRequest{
+		SourceAccountId:      args[0],
+		DestinationAccountId: args[1],
+		Amount:               amount,
+		IdempotencyKey:       idempotencyKey,
+	})
+	if err != nil {
+		logger.Fatalf("Transfer refused: %v", err)
+	}
+	printer.Transfer(result)
+}
+
+func runReadModelProject(cmd *cobra.Command, args []string) {
+	// Load configuration
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		logger.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Create factory
+	appFactory, err := factory.NewFactory(cfg)
+	if err != nil {
+		logger.Fatalf("Failed to create factory: %v", err)
+	}
+	defer appFactory.Close()
+
+	projector := appFactory.CreateReadModelProjector()
+
+	if projectOnce {
+		applied, err := projector.ProjectPending(cmd.Context())
+		if err != nil {
+			logger.Fatalf("Failed to project events after %d applied: %v", applied, err)
+		}
+		logger.Infof("Projected %d events into the read model", applied)
+		return
+	}
+
+	// Project until interrupted
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	logger.Infof("Projecting events into the read model every %s", cfg.GetReadModelProjectInterval())
+	if err := projector.Run(ctx); err != nil {
+		logger.Fatalf("Read model projector stopped: %v", err)
+	}
+	logger.Info("Read model projector stopped")
+}
+
+func runReadModelRebuild(cmd *cobra.Command, args []string) {
+	// Load configuration
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		logger.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Create factory
+	appFactory, err := factory.NewFactory(cfg)
+	if err != nil {
+		logger.Fatalf("Failed to create factory: %v", err)
+	}
+	defer appFactory.Close()
+
+	rebuilt, err := appFactory.CreateReadModelProjector().Rebuild(cmd.Context())
+	if err != nil {
+		logger.Fatalf("Failed to rebuild the read model: %v", err)
+	}
+	logger.Infof("Rebuilt %d account summaries", rebuilt)
 }
 
 func runAPI(cmd *cobra.Command, args []string) {
@@ -103,7 +823,12 @@ func runAPI(cmd *cobra.Command, args []string) {
 	gin.SetMode(cfg.GetGinMode())
 
 	// Create factory
-	appFactory, err := factory.NewFactory(cfg)
+	newFactory := factory.NewFactory
+	if inMemory {
+		logger.Warn("Running in memory: data is lost when the server stops")
+		newFactory = factory.NewInMemoryFactory
+	}
+	appFactory, err := newFactory(cfg)
 	if err != nil {
 		logger.Fatalf("Failed to create factory: %v", err)
 	}
@@ -111,16 +836,24 @@ func runAPI(cmd *cobra.Command, args []string) {
 
 	// Create router
 	router := gin.Default()
+	// Let services see values middleware puts into the request context
+	router.ContextWithFallback = true
 
 	// Setup middleware
 	router.Use(gin.Recovery())
 	router.Use(gin.Logger())
 
-	// Create controllers
-	accountController := appFactory.CreateAccountController()
+	spec, err := api.Load()
+	if err != nil {
+		logger.Fatalf("Failed to load the OpenAPI document: %v", err)
+	}
 
-	// Setup routes
-	routes.SetupAccountRoutes(router, accountController)
+	// Public routes are registered before the middleware identifying callers, so they need no token
+	docsController, err := controller.NewDocsController(spec)
+	if err != nil {
+		logger.Fatalf("Failed to create docs controller: %v", err)
+	}
+	routes.SetupDocsRoutes(router, docsController)
 
 	// Health check route
 	router.GET("/health", func(c *gin.Context) {
@@ -129,11 +862,66 @@ func runAPI(cmd *cobra.Command, args []string) {
 		})
 	})
 
+	// Identify callers by their bearer token when auth is enabled, or else by the X-Operator-Id header
+	verifier, err := appFactory.CreateTokenVerifier()
+	if err != nil {
+		logger.Fatalf("Failed to set up authentication: %v", err)
+	}
+	if verifier != nil {
+		router.Use(middleware.Authenticate(verifier))
+	} else {
+		logger.Warn("Authentication is disabled: callers are trusted as named in X-Operator-Id")
+		router.Use(middleware.OperatorIdentity())
+	}
+
+	// Cache hit rates and other runtime counters, which only admins may read once callers authenticate
+	if verifier != nil {
+		routes.SetupDebugRoutes(router, middleware.RequireScope(access.AdminScope))
+	} else {
+		routes.SetupDebugRoutes(router)
+	}
+
+	// Validate the account and transfer routes against their OpenAPI document
+	if cfg.GetOpenAPIValidateResponses() {
+		validateResponses, err := middleware.ValidateResponses(spec, func(req *http.Request, err error) {
+			logger.Warnf("Response to %s %s does not match the OpenAPI document: %v", req.Method, req.URL.Path, err)
+		})
+		if err != nil {
+			logger.Fatalf("Failed to set up response validation: %v", err)
+		}
+		router.Use(validateResponses)
+	}
+	if cfg.GetOpenAPIValidateRequests() {
+		validateRequests, err := middleware.ValidateRequests(spec)
+		if err != nil {
+			logger.Fatalf("Failed to set up request validation: %v", err)
+		}
+		router.Use(validateRequests)
+	}
+
+	// Replay responses to POST requests retried with the same Idempotency-Key
+	router.Use(appFactory.CreateIdempotencyMiddleware())
+
+	// Create controllers
+	accountController := appFactory.CreateAccountController()
+
+	// Setup routes
+	routes.SetupAccountRoutes(router, accountController)
+	routes.SetupScreeningRoutes(router, appFactory.CreateScreeningController())
+	routes.SetupWebhookRoutes(router, appFactory.CreateWebhookController())
+	routes.SetupStreamRoutes(router, appFactory.CreateStreamController())
+
+	// Event streams never finish on their own, so they end with this context when shutdown starts
+	streamsCtx, stopStreams := context.WithCancel(context.Background())
+	defer stopStreams()
+
 	// Create server
 	server := &http.Server{
-		Addr:    ":" + cfg.GetServerPort(),
-		Handler: router,
+		Addr:        ":" + cfg.GetServerPort(),
+		Handler:     router,
+		BaseContext: func(net.Listener) context.Context { return streamsCtx },
 	}
+	server.RegisterOnShutdown(stopStreams)
 
 	// Start the server in a goroutine
 	go func() {
@@ -143,6 +931,19 @@ func runAPI(cmd *cobra.Command, args []string) {
 		}
 	}()
 
+	// Start the gRPC server on its own port, sharing the factory's connections with the REST server
+	grpcServer := appFactory.CreateGRPCServer(verifier)
+	grpcListener, err := net.Listen("tcp", ":"+cfg.GetGRPCPort())
+	if err != nil {
+		logger.Fatalf("Failed to listen on gRPC port %s: %v", cfg.GetGRPCPort(), err)
+	}
+	go func() {
+		logger.Infof("gRPC server starting on port %s", cfg.GetGRPCPort())
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			logger.Fatalf("Failed to start gRPC server: %v", err)
+		}
+	}()
+
 	// Wait for the interrupt signal to gracefully shut down the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -156,7 +957,8 @@ func runAPI(cmd *cobra.Command, args []string) {
 	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
 
-	// Shutdown server
+	// Shutdown servers
+	grpcServer.Shutdown(ctx)
 	if err := server.Shutdown(ctx); err != nil {
 		logger.Fatalf("Server forced to shutdown: %v", err)
 	}
@@ -0,0 +1,21 @@
+// Package migrations embeds the versioned SQL migrations of the database schema, in a directory per
+// database driver. Migrations are named <version>_<name>.up.sql, with an optional <version>_<name>.down.sql
+// that reverts them, and are applied in version order by the migrate command. Every driver has the same
+// migrations, each written in its dialect. Applied migrations must not be edited; add a new one instead.
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+// Drivers lists the database drivers with migrations
+var Drivers = []string{"postgres", "sqlite"}
+
+// For returns the migrations of the database driver
+func For(driver string) (fs.FS, error) {
+	return fs.Sub(files, driver)
+}
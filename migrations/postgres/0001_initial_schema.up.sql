@@ -0,0 +1,241 @@
+-- The schema as created by GORM AutoMigrate before versioned migrations. Deployed databases hold the
+-- accounts table of the original service, with none of the columns added since: CREATE TABLE IF NOT
+-- EXISTS leaves it as it is, so the columns are added to it, before anything indexes or constrains them.
+
+CREATE TABLE IF NOT EXISTS accounts (
+    id uuid PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    account_id text,
+    holder_name text,
+    balance decimal,
+    account_type text DEFAULT 'standard',
+    rate_plan_id text,
+    tier text DEFAULT 'standard',
+    overdraft_limit decimal NOT NULL DEFAULT 0,
+    overdraft_limit_version bigint NOT NULL DEFAULT 0,
+    frozen boolean NOT NULL DEFAULT false,
+    version bigint NOT NULL DEFAULT 0
+);
+ALTER TABLE accounts
+    ADD COLUMN IF NOT EXISTS holder_name text,
+    ADD COLUMN IF NOT EXISTS account_type text DEFAULT 'standard',
+    ADD COLUMN IF NOT EXISTS rate_plan_id text,
+    ADD COLUMN IF NOT EXISTS tier text DEFAULT 'standard',
+    ADD COLUMN IF NOT EXISTS overdraft_limit decimal NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS overdraft_limit_version bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS frozen boolean NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 0;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_accounts_account_id ON accounts (account_id);
+CREATE INDEX IF NOT EXISTS idx_accounts_rate_plan_id ON accounts (rate_plan_id);
+
+CREATE TABLE IF NOT EXISTS overdraft_limit_changes (
+    id uuid PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    account_id text,
+    version bigint,
+    previous_limit decimal,
+    new_limit decimal,
+    changed_by text,
+    reason text
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_overdraft_limit_changes_account_version ON overdraft_limit_changes (account_id, version);
+
+CREATE TABLE IF NOT EXISTS transfers (
+    id uuid PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    source_account_id text,
+    destination_account_id text,
+    amount decimal,
+    fee decimal,
+    fee_breakdown text,
+    type text DEFAULT 'transfer',
+    status text,
+    risk_decision text,
+    failure_reason text,
+    initiated_by text,
+    reviewed_by text,
+    reviewed_at timestamptz,
+    expires_at timestamptz,
+    executed_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_transfers_source_account_id ON transfers (source_account_id);
+CREATE INDEX IF NOT EXISTS idx_transfers_destination_account_id ON transfers (destination_account_id);
+CREATE INDEX IF NOT EXISTS idx_transfers_status ON transfers (status);
+
+CREATE TABLE IF NOT EXISTS interest_accruals (
+    id uuid PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    account_id text,
+    accrual_date varchar(10),
+    rate_plan_id text,
+    annual_rate decimal,
+    day_count text,
+    balance decimal,
+    amount decimal,
+    transfer_id uuid
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_interest_accruals_account_date ON interest_accruals (account_id, accrual_date);
+CREATE INDEX IF NOT EXISTS idx_interest_accruals_transfer_id ON interest_accruals (transfer_id);
+
+CREATE TABLE IF NOT EXISTS transfer_limit_counters (
+    id uuid PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    account_id text,
+    period varchar(16),
+    amount decimal NOT NULL DEFAULT 0,
+    count bigint NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transfer_limit_counters_account_period ON transfer_limit_counters (account_id, period);
+
+CREATE TABLE IF NOT EXISTS watchlist_entries (
+    id uuid PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    entry_id text,
+    name text,
+    aliases text,
+    type text,
+    programs text,
+    source text
+);
+CREATE INDEX IF NOT EXISTS idx_watchlist_entries_entry_id ON watchlist_entries (entry_id);
+
+CREATE TABLE IF NOT EXISTS screening_hits (
+    id uuid PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    transfer_id uuid,
+    account_id text,
+    screened_name text,
+    entry_id text,
+    matched_name text,
+    programs text,
+    score decimal,
+    action text,
+    status text DEFAULT 'open',
+    reviewed_by text,
+    reviewed_at timestamptz,
+    review_note text
+);
+CREATE INDEX IF NOT EXISTS idx_screening_hits_transfer_id ON screening_hits (transfer_id);
+CREATE INDEX IF NOT EXISTS idx_screening_hits_account_id ON screening_hits (account_id);
+CREATE INDEX IF NOT EXISTS idx_screening_hits_status ON screening_hits (status);
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id uuid PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    sequence bigserial,
+    event_type text,
+    schema_version bigint,
+    aggregate_type text,
+    aggregate_id text,
+    partition_key text,
+    payload text,
+    occurred_at timestamptz,
+    sent_at timestamptz,
+    attempts bigint NOT NULL DEFAULT 0,
+    last_error text,
+    projected_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_outbox_events_sequence ON outbox_events (sequence);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_event_type ON outbox_events (event_type);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_aggregate_id ON outbox_events (aggregate_id);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_sent_at ON outbox_events (sent_at);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_projected_at ON outbox_events (projected_at);
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id uuid PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    account_id text,
+    url text,
+    event_types text,
+    secret text,
+    active boolean DEFAULT true
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_account_id ON webhook_subscriptions (account_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_active ON webhook_subscriptions (active);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id uuid PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    subscription_id uuid,
+    event_id uuid,
+    event_type text,
+    payload text,
+    status text,
+    attempts bigint NOT NULL DEFAULT 0,
+    next_attempt_at timestamptz,
+    last_status_code bigint,
+    last_error text,
+    delivered_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription_id ON webhook_deliveries (subscription_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_event_id ON webhook_deliveries (event_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_status ON webhook_deliveries (status);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_next_attempt_at ON webhook_deliveries (next_attempt_at);
+
+CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+    id uuid PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    delivery_id uuid,
+    attempt bigint,
+    status_code bigint,
+    error text,
+    duration_ms bigint
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_attempts_delivery_id ON webhook_delivery_attempts (delivery_id);
+
+CREATE TABLE IF NOT EXISTS account_events (
+    id uuid PRIMARY KEY,
+    account_id text NOT NULL,
+    version bigint NOT NULL,
+    type text NOT NULL,
+    data text,
+    transfer_id uuid,
+    recorded_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_account_events_stream_version ON account_events (account_id, version);
+CREATE INDEX IF NOT EXISTS idx_account_events_transfer_id ON account_events (transfer_id);
+
+CREATE TABLE IF NOT EXISTS account_snapshots (
+    account_id text PRIMARY KEY,
+    version bigint NOT NULL,
+    state text,
+    taken_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS account_summaries (
+    account_id text PRIMARY KEY,
+    holder_name text,
+    account_type text,
+    rate_plan_id text,
+    tier text,
+    balance decimal,
+    balance_sequence bigint NOT NULL DEFAULT 0,
+    overdraft_limit decimal NOT NULL DEFAULT 0,
+    overdraft_limit_version bigint NOT NULL DEFAULT 0,
+    frozen boolean NOT NULL DEFAULT false,
+    last_transfer_id text,
+    last_transfer_direction text,
+    last_transfer_counterparty text,
+    last_transfer_amount decimal,
+    last_transfer_at timestamptz,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS account_daily_flows (
+    account_id text,
+    day text,
+    inflow decimal NOT NULL DEFAULT 0,
+    outflow decimal NOT NULL DEFAULT 0,
+    PRIMARY KEY (account_id, day)
+);
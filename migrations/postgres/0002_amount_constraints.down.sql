@@ -0,0 +1,10 @@
+ALTER TABLE transfer_limit_counters
+    DROP CONSTRAINT IF EXISTS transfer_limit_counters_count_check,
+    DROP CONSTRAINT IF EXISTS transfer_limit_counters_amount_check;
+
+ALTER TABLE transfers
+    DROP CONSTRAINT IF EXISTS transfers_fee_check,
+    DROP CONSTRAINT IF EXISTS transfers_amount_check;
+
+ALTER TABLE accounts
+    DROP CONSTRAINT IF EXISTS accounts_overdraft_limit_check;
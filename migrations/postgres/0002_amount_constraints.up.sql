@@ -0,0 +1,13 @@
+-- NOT VALID enforces the constraints on new and updated rows without checking the existing ones,
+-- which can be validated separately with ALTER TABLE ... VALIDATE CONSTRAINT.
+
+ALTER TABLE accounts
+    ADD CONSTRAINT accounts_overdraft_limit_check CHECK (overdraft_limit >= 0) NOT VALID;
+
+ALTER TABLE transfers
+    ADD CONSTRAINT transfers_amount_check CHECK (amount > 0) NOT VALID,
+    ADD CONSTRAINT transfers_fee_check CHECK (fee >= 0) NOT VALID;
+
+ALTER TABLE transfer_limit_counters
+    ADD CONSTRAINT transfer_limit_counters_amount_check CHECK (amount >= 0) NOT VALID,
+    ADD CONSTRAINT transfer_limit_counters_count_check CHECK (count >= 0) NOT VALID;
@@ -0,0 +1,7 @@
+-- Accounts belong to the principal that owns them. Accounts created before have no owner, so only
+-- principals acting on every account can use them.
+
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS owner_id text;
+CREATE INDEX IF NOT EXISTS idx_accounts_owner_id ON accounts (owner_id);
+
+ALTER TABLE account_summaries ADD COLUMN IF NOT EXISTS owner_id text;
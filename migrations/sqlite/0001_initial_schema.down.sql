@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS account_daily_flows;
+DROP TABLE IF EXISTS account_summaries;
+DROP TABLE IF EXISTS account_snapshots;
+DROP TABLE IF EXISTS account_events;
+DROP TABLE IF EXISTS webhook_delivery_attempts;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
+DROP TABLE IF EXISTS outbox_events;
+DROP TABLE IF EXISTS screening_hits;
+DROP TABLE IF EXISTS watchlist_entries;
+DROP TABLE IF EXISTS transfer_limit_counters;
+DROP TABLE IF EXISTS interest_accruals;
+DROP TABLE IF EXISTS transfers;
+DROP TABLE IF EXISTS overdraft_limit_changes;
+DROP TABLE IF EXISTS accounts;
@@ -0,0 +1,239 @@
+-- The SQLite dialect of the initial schema, for local development and tests. Column types follow the
+-- names the SQLite driver converts from: datetime to time.Time and boolean to bool. SQLite databases were
+-- only ever created by these migrations, so unlike PostgreSQL ones they have no earlier schema to adopt.
+
+CREATE TABLE IF NOT EXISTS accounts (
+    id text PRIMARY KEY,
+    created_at datetime,
+    updated_at datetime,
+    account_id text,
+    holder_name text,
+    balance real,
+    account_type text DEFAULT 'standard',
+    rate_plan_id text,
+    tier text DEFAULT 'standard',
+    overdraft_limit real NOT NULL DEFAULT 0,
+    overdraft_limit_version integer NOT NULL DEFAULT 0,
+    frozen boolean NOT NULL DEFAULT 0,
+    version integer NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_accounts_account_id ON accounts (account_id);
+CREATE INDEX IF NOT EXISTS idx_accounts_rate_plan_id ON accounts (rate_plan_id);
+
+CREATE TABLE IF NOT EXISTS overdraft_limit_changes (
+    id text PRIMARY KEY,
+    created_at datetime,
+    updated_at datetime,
+    account_id text,
+    version integer,
+    previous_limit real,
+    new_limit real,
+    changed_by text,
+    reason text
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_overdraft_limit_changes_account_version ON overdraft_limit_changes (account_id, version);
+
+CREATE TABLE IF NOT EXISTS transfers (
+    id text PRIMARY KEY,
+    created_at datetime,
+    updated_at datetime,
+    source_account_id text,
+    destination_account_id text,
+    amount real,
+    fee real,
+    fee_breakdown text,
+    type text DEFAULT 'transfer',
+    status text,
+    risk_decision text,
+    failure_reason text,
+    initiated_by text,
+    reviewed_by text,
+    reviewed_at datetime,
+    expires_at datetime,
+    executed_at datetime
+);
+CREATE INDEX IF NOT EXISTS idx_transfers_source_account_id ON transfers (source_account_id);
+CREATE INDEX IF NOT EXISTS idx_transfers_destination_account_id ON transfers (destination_account_id);
+CREATE INDEX IF NOT EXISTS idx_transfers_status ON transfers (status);
+
+CREATE TABLE IF NOT EXISTS interest_accruals (
+    id text PRIMARY KEY,
+    created_at datetime,
+    updated_at datetime,
+    account_id text,
+    accrual_date text,
+    rate_plan_id text,
+    annual_rate real,
+    day_count text,
+    balance real,
+    amount real,
+    transfer_id text
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_interest_accruals_account_date ON interest_accruals (account_id, accrual_date);
+CREATE INDEX IF NOT EXISTS idx_interest_accruals_transfer_id ON interest_accruals (transfer_id);
+
+CREATE TABLE IF NOT EXISTS transfer_limit_counters (
+    id text PRIMARY KEY,
+    created_at datetime,
+    updated_at datetime,
+    account_id text,
+    period text,
+    amount real NOT NULL DEFAULT 0,
+    count integer NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transfer_limit_counters_account_period ON transfer_limit_counters (account_id, period);
+
+CREATE TABLE IF NOT EXISTS watchlist_entries (
+    id text PRIMARY KEY,
+    created_at datetime,
+    updated_at datetime,
+    entry_id text,
+    name text,
+    aliases text,
+    type text,
+    programs text,
+    source text
+);
+CREATE INDEX IF NOT EXISTS idx_watchlist_entries_entry_id ON watchlist_entries (entry_id);
+
+CREATE TABLE IF NOT EXISTS screening_hits (
+    id text PRIMARY KEY,
+    created_at datetime,
+    updated_at datetime,
+    transfer_id text,
+    account_id text,
+    screened_name text,
+    entry_id text,
+    matched_name text,
+    programs text,
+    score real,
+    action text,
+    status text DEFAULT 'open',
+    reviewed_by text,
+    reviewed_at datetime,
+    review_note text
+);
+CREATE INDEX IF NOT EXISTS idx_screening_hits_transfer_id ON screening_hits (transfer_id);
+CREATE INDEX IF NOT EXISTS idx_screening_hits_account_id ON screening_hits (account_id);
+CREATE INDEX IF NOT EXISTS idx_screening_hits_status ON screening_hits (status);
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id text PRIMARY KEY,
+    created_at datetime,
+    updated_at datetime,
+    sequence integer,
+    event_type text,
+    schema_version integer,
+    aggregate_type text,
+    aggregate_id text,
+    partition_key text,
+    payload text,
+    occurred_at datetime,
+    sent_at datetime,
+    attempts integer NOT NULL DEFAULT 0,
+    last_error text,
+    projected_at datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_outbox_events_sequence ON outbox_events (sequence);
+-- SQLite only auto-increments the primary key, so the relay order is assigned once the event is inserted,
+-- which is safe as SQLite runs one write transaction at a time
+CREATE TRIGGER IF NOT EXISTS outbox_events_sequence AFTER INSERT ON outbox_events
+WHEN NEW.sequence IS NULL
+BEGIN
+    UPDATE outbox_events SET sequence = (SELECT COALESCE(MAX(sequence), 0) + 1 FROM outbox_events) WHERE id = NEW.id;
+END;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_event_type ON outbox_events (event_type);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_aggregate_id ON outbox_events (aggregate_id);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_sent_at ON outbox_events (sent_at);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_projected_at ON outbox_events (projected_at);
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id text PRIMARY KEY,
+    created_at datetime,
+    updated_at datetime,
+    account_id text,
+    url text,
+    event_types text,
+    secret text,
+    active boolean DEFAULT 1
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_account_id ON webhook_subscriptions (account_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_active ON webhook_subscriptions (active);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id text PRIMARY KEY,
+    created_at datetime,
+    updated_at datetime,
+    subscription_id text,
+    event_id text,
+    event_type text,
+    payload text,
+    status text,
+    attempts integer NOT NULL DEFAULT 0,
+    next_attempt_at datetime,
+    last_status_code integer,
+    last_error text,
+    delivered_at datetime
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription_id ON webhook_deliveries (subscription_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_event_id ON webhook_deliveries (event_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_status ON webhook_deliveries (status);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_next_attempt_at ON webhook_deliveries (next_attempt_at);
+
+CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+    id text PRIMARY KEY,
+    created_at datetime,
+    updated_at datetime,
+    delivery_id text,
+    attempt integer,
+    status_code integer,
+    error text,
+    duration_ms integer
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_attempts_delivery_id ON webhook_delivery_attempts (delivery_id);
+
+CREATE TABLE IF NOT EXISTS account_events (
+    id text PRIMARY KEY,
+    account_id text NOT NULL,
+    version integer NOT NULL,
+    type text NOT NULL,
+    data text,
+    transfer_id text,
+    recorded_at datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_account_events_stream_version ON account_events (account_id, version);
+CREATE INDEX IF NOT EXISTS idx_account_events_transfer_id ON account_events (transfer_id);
+
+CREATE TABLE IF NOT EXISTS account_snapshots (
+    account_id text PRIMARY KEY,
+    version integer NOT NULL,
+    state text,
+    taken_at datetime
+);
+
+CREATE TABLE IF NOT EXISTS account_summaries (
+    account_id text PRIMARY KEY,
+    holder_name text,
+    account_type text,
+    rate_plan_id text,
+    tier text,
+    balance real,
+    balance_sequence integer NOT NULL DEFAULT 0,
+    overdraft_limit real NOT NULL DEFAULT 0,
+    overdraft_limit_version integer NOT NULL DEFAULT 0,
+    frozen boolean NOT NULL DEFAULT 0,
+    last_transfer_id text,
+    last_transfer_direction text,
+    last_transfer_counterparty text,
+    last_transfer_amount real,
+    last_transfer_at datetime,
+    updated_at datetime
+);
+
+CREATE TABLE IF NOT EXISTS account_daily_flows (
+    account_id text,
+    day text,
+    inflow real NOT NULL DEFAULT 0,
+    outflow real NOT NULL DEFAULT 0,
+    PRIMARY KEY (account_id, day)
+);
@@ -0,0 +1,10 @@
+DROP TRIGGER IF EXISTS transfer_limit_counters_count_check_update;
+DROP TRIGGER IF EXISTS transfer_limit_counters_count_check_insert;
+DROP TRIGGER IF EXISTS transfer_limit_counters_amount_check_update;
+DROP TRIGGER IF EXISTS transfer_limit_counters_amount_check_insert;
+DROP TRIGGER IF EXISTS transfers_fee_check_update;
+DROP TRIGGER IF EXISTS transfers_fee_check_insert;
+DROP TRIGGER IF EXISTS transfers_amount_check_update;
+DROP TRIGGER IF EXISTS transfers_amount_check_insert;
+DROP TRIGGER IF EXISTS accounts_overdraft_limit_check_update;
+DROP TRIGGER IF EXISTS accounts_overdraft_limit_check_insert;
@@ -0,0 +1,62 @@
+-- SQLite cannot add constraints to existing tables, so the checks of the Postgres migration are triggers
+-- that abort the statement the same way.
+
+CREATE TRIGGER accounts_overdraft_limit_check_insert BEFORE INSERT ON accounts
+WHEN NEW.overdraft_limit < 0
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: accounts_overdraft_limit_check');
+END;
+
+CREATE TRIGGER accounts_overdraft_limit_check_update BEFORE UPDATE ON accounts
+WHEN NEW.overdraft_limit < 0
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: accounts_overdraft_limit_check');
+END;
+
+CREATE TRIGGER transfers_amount_check_insert BEFORE INSERT ON transfers
+WHEN NEW.amount <= 0
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: transfers_amount_check');
+END;
+
+CREATE TRIGGER transfers_amount_check_update BEFORE UPDATE ON transfers
+WHEN NEW.amount <= 0
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: transfers_amount_check');
+END;
+
+CREATE TRIGGER transfers_fee_check_insert BEFORE INSERT ON transfers
+WHEN NEW.fee < 0
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: transfers_fee_check');
+END;
+
+CREATE TRIGGER transfers_fee_check_update BEFORE UPDATE ON transfers
+WHEN NEW.fee < 0
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: transfers_fee_check');
+END;
+
+CREATE TRIGGER transfer_limit_counters_amount_check_insert BEFORE INSERT ON transfer_limit_counters
+WHEN NEW.amount < 0
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: transfer_limit_counters_amount_check');
+END;
+
+CREATE TRIGGER transfer_limit_counters_amount_check_update BEFORE UPDATE ON transfer_limit_counters
+WHEN NEW.amount < 0
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: transfer_limit_counters_amount_check');
+END;
+
+CREATE TRIGGER transfer_limit_counters_count_check_insert BEFORE INSERT ON transfer_limit_counters
+WHEN NEW.count < 0
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: transfer_limit_counters_count_check');
+END;
+
+CREATE TRIGGER transfer_limit_counters_count_check_update BEFORE UPDATE ON transfer_limit_counters
+WHEN NEW.count < 0
+BEGIN
+    SELECT RAISE(ABORT, 'CHECK constraint failed: transfer_limit_counters_count_check');
+END;
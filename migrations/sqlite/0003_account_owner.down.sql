@@ -0,0 +1,4 @@
+ALTER TABLE account_summaries DROP COLUMN owner_id;
+
+DROP INDEX IF EXISTS idx_accounts_owner_id;
+ALTER TABLE accounts DROP COLUMN owner_id;
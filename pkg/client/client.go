@@ -0,0 +1,294 @@
+// Package client is the Go client of the transfer service's REST API.
+//
+// Requests that fail on the way, or that the service answers with 429, 502, 503 or 504, are retried
+// with exponential backoff. Every POST carries an Idempotency-Key that stays the same across its
+// retries, so the service executes a transfer once however often it is sent. Refusals come back as
+// an *Error matching the sentinel errors of this package.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// IdempotencyHeader carries the key that makes retrying a POST safe
+	IdempotencyHeader = "Idempotency-Key"
+	// OperatorHeader carries the identity of the operator a request acts on behalf of
+	OperatorHeader = "X-Operator-Id"
+)
+
+// Retry defaults of New
+const (
+	DefaultMaxRetries = 3
+	DefaultMinBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff = 2 * time.Second
+	DefaultTimeout    = 30 * time.Second
+)
+
+// Client calls the transfer service at a base URL. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	operator   string
+	token      string
+
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	// newIdempotencyKey and sleep are replaced by tests
+	newIdempotencyKey func() string
+	sleep             func(ctx context.Context, d time.Duration) error
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sends requests through httpClient instead of one timing out after DefaultTimeout
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithOperator sends every request on behalf of operator
+func WithOperator(operator string) Option {
+	return func(c *Client) {
+		c.operator = operator
+	}
+}
+
+// WithBearerToken authenticates every request with token, for services with auth enabled. The service
+// then acts on behalf of the token's subject rather than the operator.
+func WithBearerToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithRetries retries a request up to maxRetries times, waiting minBackoff before the first retry and
+// doubling the wait after every attempt up to maxBackoff. Zero maxRetries disables retries.
+func WithRetries(maxRetries int, minBackoff, maxBackoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.minBackoff = minBackoff
+		c.maxBackoff = maxBackoff
+	}
+}
+
+// New creates a client of the service at baseURL, such as http://localhost:3000
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:           strings.TrimRight(baseURL, "/"),
+		httpClient:        &http.Client{Timeout: DefaultTimeout},
+		maxRetries:        DefaultMaxRetries,
+		minBackoff:        DefaultMinBackoff,
+		maxBackoff:        DefaultMaxBackoff,
+		newIdempotencyKey: uuid.NewString,
+		sleep:             sleep,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// CreateAccount opens an account, failing with ErrConflict when its id is taken
+func (c *Client) CreateAccount(ctx context.Context, req CreateAccountRequest) error {
+	return c.do(ctx, http.MethodPost, "/api/v1/accounts", req.IdempotencyKey, req, nil)
+}
+
+// GetAccount returns an account, failing with ErrNotFound when there is none
+func (c *Client) GetAccount(ctx context.Context, accountId string) (*Account, error) {
+	var acc Account
+	if err := c.do(ctx, http.MethodGet, "/api/v1/accounts/"+url.PathEscape(accountId), "", nil, &acc); err != nil {
+		return nil, err
+	}
+	return &acc, nil
+}
+
+// ListAccounts returns a page of accounts ordered by id
+func (c *Client) ListAccounts(ctx context.Context, opts ListAccountsOptions) (*AccountPage, error) {
+	query := url.Values{}
+	if opts.After != "" {
+		query.Set("after", opts.After)
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	path := "/api/v1/accounts"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var page AccountPage
+	if err := c.do(ctx, http.MethodGet, path, "", nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// FreezeAccount freezes or unfreezes an account and returns it. It needs an operator, failing with
+// ErrUnauthorized without one.
+func (c *Client) FreezeAccount(ctx context.Context, accountId string, req FreezeAccountRequest) (*Account, error) {
+	var acc Account
+	if err := c.do(ctx, http.MethodPut, "/api/v1/accounts/"+url.PathEscape(accountId)+"/freeze", "", req, &acc); err != nil {
+		return nil, err
+	}
+	return &acc, nil
+}
+
+// Transfer moves money between accounts. Transfers held for review or approval are returned without an
+// error, and are Pending. Refused transfers fail with an *Error matching ErrTransferRefused, and with
+// ErrInsufficientFunds, ErrLimitExceeded or another sentinel naming the reason.
+func (c *Client) Transfer(ctx context.Context, req TransferRequest) (*TransferResult, error) {
+	var result errorBody
+	if err := c.do(ctx, http.MethodPost, "/api/v1/accounts/transfer", req.IdempotencyKey, req, &result); err != nil {
+		return nil, err
+	}
+	// transfers refused for lack of funds are answered with 200 and no transfer record
+	if result.Transfer == nil {
+		return nil, result.toError(http.StatusOK)
+	}
+	return &TransferResult{Message: result.Message, Transfer: result.Transfer}, nil
+}
+
+// do sends a request with body encoded as JSON, retrying it while it fails in a retryable way, and
+// decodes a successful response into out. POST requests carry idempotencyKey, or a generated one.
+func (c *Client) do(ctx context.Context, method, path, idempotencyKey string, body, out any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("encoding request: %w", err)
+		}
+	}
+	if method == http.MethodPost && idempotencyKey == "" {
+		idempotencyKey = c.newIdempotencyKey()
+	}
+
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := c.send(ctx, method, path, idempotencyKey, payload, out)
+		if err == nil || attempt >= c.maxRetries || !retryable(ctx, err) {
+			return err
+		}
+		if err := c.sleep(ctx, c.backoff(attempt, retryAfter)); err != nil {
+			return err
+		}
+	}
+}
+
+// send makes a single attempt of a request, returning the delay the service asked for in Retry-After
+func (c *Client) send(ctx context.Context, method, path, idempotencyKey string, payload []byte, out any) (time.Duration, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set(IdempotencyHeader, idempotencyKey)
+	}
+	if c.operator != "" {
+		req.Header.Set(OperatorHeader, c.operator)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var body errorBody
+		json.Unmarshal(raw, &body)
+		return retryAfter(resp), body.toError(resp.StatusCode)
+	}
+	if out != nil {
+		if err := json.Unmarshal(raw, out); err != nil {
+			return 0, fmt.Errorf("decoding response to %s %s: %w", method, path, err)
+		}
+	}
+	return 0, nil
+}
+
+// retryable reports whether a failed attempt may succeed when sent again: requests that never got an
+// answer, that the service was too busy or unavailable for, or whose key another attempt still holds
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var transportErr *url.Error
+	if errors.As(err, &transportErr) {
+		return true
+	}
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return apiErr.Code == CodeIdempotencyKeyInUse
+}
+
+// backoff returns the wait before retry attempt+1: the service's Retry-After when it sent one, or an
+// exponential backoff with jitter, so clients retrying together do not all come back at once
+func (c *Client) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return min(retryAfter, c.maxBackoff)
+	}
+	wait := c.minBackoff << attempt
+	if wait <= 0 || wait > c.maxBackoff {
+		wait = c.maxBackoff
+	}
+	return wait/2 + rand.N(wait/2+1)
+}
+
+// retryAfter parses the delay in seconds of the Retry-After header
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// sleep waits for d, or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,318 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"internal-transfer-microservice/api"
+	"internal-transfer-microservice/internal/config"
+	"internal-transfer-microservice/internal/controller"
+	"internal-transfer-microservice/internal/domain/limit"
+	"internal-transfer-microservice/internal/domain/transfer"
+	"internal-transfer-microservice/internal/infrastructure/cache"
+	"internal-transfer-microservice/internal/middleware"
+	"internal-transfer-microservice/internal/repository"
+	"internal-transfer-microservice/internal/routes"
+	"internal-transfer-microservice/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestServer serves the account routes from memory behind the API's middleware, validating requests and
+// responses against the OpenAPI document. Transfers above 1000 wait for approval, and the limit per transfer
+// is 5000. Every request passes through intercept first.
+func newTestServer(t *testing.T, intercept func(w http.ResponseWriter, req *http.Request, next http.Handler)) *httptest.Server {
+	spec, err := api.Load()
+	if err != nil {
+		t.Fatalf("Expected the OpenAPI document to load, got %v", err)
+	}
+	validateResponses, err := middleware.ValidateResponses(spec, func(req *http.Request, err error) {
+		t.Errorf("Expected the response to %s %s to match the OpenAPI document, got %v", req.Method, req.URL, err)
+	})
+	if err != nil {
+		t.Fatalf("Expected response validation, got %v", err)
+	}
+	validateRequests, err := middleware.ValidateRequests(spec)
+	if err != nil {
+		t.Fatalf("Expected request validation, got %v", err)
+	}
+
+	repo := repository.NewMemoryAccountRepo()
+	memoryCache := cache.NewMemoryCache()
+	cfg := &config.Config{
+		Limits: config.LimitsConfig{
+			Tiers: []config.LimitTierConfig{{Tier: "", PerTransferMax: 5000}},
+		},
+	}
+	accountService := service.NewAccountService(repo, memoryCache,
+		service.WithLimitEngine(service.NewLimitEngine(repo, cfg)),
+		service.WithApprovalPolicy(1000, time.Hour),
+	)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.ContextWithFallback = true
+	router.Use(middleware.OperatorIdentity(), validateResponses, validateRequests, middleware.Idempotency(memoryCache, time.Hour))
+	routes.SetupAccountRoutes(router, controller.NewAccountController(accountService))
+
+	var handler http.Handler = router
+	if intercept != nil {
+		handler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) { intercept(w, req, router) })
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTestClient calls server without waiting between retries
+func newTestClient(server *httptest.Server, opts ...Option) *Client {
+	c := New(server.URL, append([]Option{WithHTTPClient(server.Client()), WithOperator("maker")}, opts...)...)
+	c.sleep = func(ctx context.Context, d time.Duration) error { return ctx.Err() }
+	return c
+}
+
+func TestErrorCodesMatchTheServer(t *testing.T) {
+	// Test case: the codes the client matches are the ones the service reports
+	codes := map[string]string{
+		CodeLimitPerTransferMax:  limit.CodePerTransferMax,
+		CodeLimitDailyAmount:     limit.CodeDailyAmount,
+		CodeLimitMonthlyAmount:   limit.CodeMonthlyAmount,
+		CodeLimitDailyCount:      limit.CodeDailyCount,
+		CodeLimitMonthlyCount:    limit.CodeMonthlyCount,
+		CodeInsufficientFunds:    transfer.CodeInsufficientFunds,
+		CodeAccountNotFound:      transfer.CodeAccountNotFound,
+		CodeAccountFrozen:        transfer.CodeAccountFrozen,
+		CodeTransferDenied:       transfer.CodeDenied,
+		CodeTransferBlocked:      transfer.CodeBlocked,
+		CodeIdempotencyKeyInUse:  middleware.CodeIdempotencyKeyInUse,
+		CodeIdempotencyKeyReused: middleware.CodeIdempotencyKeyReused,
+	}
+	for client, server := range codes {
+		if client != server {
+			t.Errorf("Expected client code %s to be %s", client, server)
+		}
+	}
+	if IdempotencyHeader != middleware.IdempotencyHeader || OperatorHeader != middleware.OperatorHeader {
+		t.Errorf("Expected the headers of the middleware, got %s and %s", IdempotencyHeader, OperatorHeader)
+	}
+}
+
+func TestClientAccounts(t *testing.T) {
+	// Setup
+	server := newTestServer(t, nil)
+	c := newTestClient(server)
+	ctx := context.Background()
+
+	// Test case: creating accounts, once per id
+	for _, id := range []string{"acc-1", "acc-2", "acc-3"} {
+		if err := c.CreateAccount(ctx, CreateAccountRequest{AccountId: id, HolderName: "Holder " + id, InitialBalance: 100}); err != nil {
+			t.Fatalf("Expected %s to be created, got %v", id, err)
+		}
+	}
+	if err := c.CreateAccount(ctx, CreateAccountRequest{AccountId: "acc-1"}); !errors.Is(err, ErrConflict) {
+		t.Errorf("Expected ErrConflict for a duplicate account, got %v", err)
+	}
+	if err := c.CreateAccount(ctx, CreateAccountRequest{}); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("Expected ErrBadRequest for an account without id, got %v", err)
+	}
+
+	// Test case: reading an account
+	acc, err := c.GetAccount(ctx, "acc-2")
+	if err != nil || acc.AccountId != "acc-2" || acc.Balance != 100 || acc.HolderName != "Holder acc-2" {
+		t.Errorf("Expected acc-2 with a balance of 100, got %+v (%v)", acc, err)
+	}
+	var apiErr *Error
+	_, err = c.GetAccount(ctx, "missing")
+	if !errors.Is(err, ErrNotFound) || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected a 404 ErrNotFound for a missing account, got %v", err)
+	}
+
+	// Test case: listing accounts a page at a time
+	page, err := c.ListAccounts(ctx, ListAccountsOptions{Limit: 2})
+	if err != nil || len(page.Accounts) != 2 || page.Accounts[0].AccountId != "acc-1" || page.NextAfter != "acc-2" {
+		t.Fatalf("Expected acc-1 and acc-2 with a next page, got %+v (%v)", page, err)
+	}
+	page, err = c.ListAccounts(ctx, ListAccountsOptions{After: page.NextAfter, Limit: 2})
+	if err != nil || len(page.Accounts) != 1 || page.Accounts[0].AccountId != "acc-3" || page.NextAfter != "" {
+		t.Errorf("Expected acc-3 on the last page, got %+v (%v)", page, err)
+	}
+	if _, err := c.ListAccounts(ctx, ListAccountsOptions{Limit: 1000}); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("Expected ErrBadRequest for a page too large, got %v", err)
+	}
+
+	// Test case: freezing an account, which needs an operator
+	acc, err = c.FreezeAccount(ctx, "acc-3", FreezeAccountRequest{Frozen: true, Reason: "fraud report"})
+	if err != nil || !acc.Frozen {
+		t.Errorf("Expected acc-3 to be frozen, got %+v (%v)", acc, err)
+	}
+	if _, err := c.FreezeAccount(ctx, "missing", FreezeAccountRequest{Frozen: true}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound freezing a missing account, got %v", err)
+	}
+	if _, err := newTestClient(server, WithOperator("")).FreezeAccount(ctx, "acc-3", FreezeAccountRequest{}); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized without an operator, got %v", err)
+	}
+}
+
+func TestClientBearerToken(t *testing.T) {
+	// Setup
+	var authorization string
+	server := newTestServer(t, func(w http.ResponseWriter, req *http.Request, next http.Handler) {
+		authorization = req.Header.Get("Authorization")
+		next.ServeHTTP(w, req)
+	})
+	ctx := context.Background()
+
+	// Test case: requests carry the bearer token, and none without one
+	newTestClient(server, WithBearerToken("token-1")).ListAccounts(ctx, ListAccountsOptions{})
+	if authorization != "Bearer token-1" {
+		t.Errorf("Expected the bearer token, got %q", authorization)
+	}
+	newTestClient(server).ListAccounts(ctx, ListAccountsOptions{})
+	if authorization != "" {
+		t.Errorf("Expected no Authorization header, got %q", authorization)
+	}
+}
+
+func TestClientTransfer(t *testing.T) {
+	// Setup
+	c := newTestClient(newTestServer(t, nil))
+	ctx := context.Background()
+	c.CreateAccount(ctx, CreateAccountRequest{AccountId: "src", InitialBalance: 10000})
+	c.CreateAccount(ctx, CreateAccountRequest{AccountId: "dst"})
+
+	// Test case: an executed transfer
+	result, err := c.Transfer(ctx, TransferRequest{SourceAccountId: "src", DestinationAccountId: "dst", Amount: 100})
+	if err != nil || result.Transfer.Status != StatusCompleted || result.Pending() {
+		t.Fatalf("Expected a completed transfer, got %+v (%v)", result, err)
+	}
+
+	// Test case: a transfer held for approval is not an error
+	result, err = c.Transfer(ctx, TransferRequest{SourceAccountId: "src", DestinationAccountId: "dst", Amount: 1500})
+	if err != nil || !result.Pending() || result.Transfer.Status != StatusPendingApproval {
+		t.Errorf("Expected a transfer pending approval, got %+v (%v)", result, err)
+	}
+
+	// Test case: refused transfers match the sentinel of their reason
+	var apiErr *Error
+	_, err = c.Transfer(ctx, TransferRequest{SourceAccountId: "dst", DestinationAccountId: "src", Amount: 900})
+	if !errors.Is(err, ErrInsufficientFunds) || !errors.Is(err, ErrTransferRefused) {
+		t.Errorf("Expected ErrInsufficientFunds, got %v", err)
+	}
+	_, err = c.Transfer(ctx, TransferRequest{SourceAccountId: "src", DestinationAccountId: "dst", Amount: 6000})
+	if !errors.Is(err, ErrLimitExceeded) || !errors.Is(err, ErrTransferRefused) || !errors.As(err, &apiErr) {
+		t.Fatalf("Expected ErrLimitExceeded, got %v", err)
+	}
+	if apiErr.Code != CodeLimitPerTransferMax || apiErr.Remaining == nil || *apiErr.Remaining.PerTransferMax != 5000 {
+		t.Errorf("Expected the per transfer limit with its allowance, got %+v", apiErr)
+	}
+	_, err = c.Transfer(ctx, TransferRequest{SourceAccountId: "missing", DestinationAccountId: "dst", Amount: 5})
+	if !errors.Is(err, ErrNotFound) || errors.Is(err, ErrTransferRefused) {
+		t.Errorf("Expected ErrNotFound for a missing source account, got %v", err)
+	}
+	_, err = c.Transfer(ctx, TransferRequest{SourceAccountId: "src", DestinationAccountId: "dst", Amount: -5})
+	if !errors.Is(err, ErrBadRequest) {
+		t.Errorf("Expected ErrBadRequest for a negative amount, got %v", err)
+	}
+}
+
+func TestClientRetriesWithTheSameIdempotencyKey(t *testing.T) {
+	// Setup: the responses to the first two attempts of every transfer are lost after it executed
+	var mu sync.Mutex
+	keys := map[string]int{}
+	server := newTestServer(t, func(w http.ResponseWriter, req *http.Request, next http.Handler) {
+		if req.URL.Path != "/api/v1/accounts/transfer" {
+			next.ServeHTTP(w, req)
+			return
+		}
+		key := req.Header.Get(IdempotencyHeader)
+		mu.Lock()
+		keys[key]++
+		attempt := keys[key]
+		mu.Unlock()
+		if attempt <= 2 {
+			next.ServeHTTP(httptest.NewRecorder(), req)
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+	c := newTestClient(server)
+	ctx := context.Background()
+	c.CreateAccount(ctx, CreateAccountRequest{AccountId: "src", InitialBalance: 1000})
+	c.CreateAccount(ctx, CreateAccountRequest{AccountId: "dst"})
+
+	// Test case: the retries replay the executed transfer instead of executing it again
+	result, err := c.Transfer(ctx, TransferRequest{SourceAccountId: "src", DestinationAccountId: "dst", Amount: 100})
+	if err != nil || result.Transfer.Status != StatusCompleted {
+		t.Fatalf("Expected the transfer to complete after retries, got %+v (%v)", result, err)
+	}
+	if len(keys) != 1 {
+		t.Errorf("Expected every attempt to carry the same key, got %v", keys)
+	}
+	for key, attempts := range keys {
+		if key == "" || attempts != 3 {
+			t.Errorf("Expected 3 attempts with a generated key, got %d with %q", attempts, key)
+		}
+	}
+	if acc, _ := c.GetAccount(ctx, "src"); acc.Balance != 900 {
+		t.Errorf("Expected the transfer to execute once leaving 900, got %v", acc.Balance)
+	}
+
+	// Test case: a key reused for a different transfer is refused, and not retried
+	_, err = c.Transfer(ctx, TransferRequest{SourceAccountId: "src", DestinationAccountId: "dst", Amount: 100, IdempotencyKey: "reused"})
+	if err != nil {
+		t.Fatalf("Expected the transfer with the given key to complete, got %v", err)
+	}
+	_, err = c.Transfer(ctx, TransferRequest{SourceAccountId: "src", DestinationAccountId: "dst", Amount: 200, IdempotencyKey: "reused"})
+	if !errors.Is(err, ErrIdempotencyKeyReused) || errors.Is(err, ErrTransferRefused) {
+		t.Errorf("Expected ErrIdempotencyKeyReused, got %v", err)
+	}
+	if keys["reused"] != 4 {
+		t.Errorf("Expected 3 attempts of the first transfer and 1 of the second, got %d", keys["reused"])
+	}
+}
+
+func TestClientStopsRetrying(t *testing.T) {
+	// Setup: the service is unavailable
+	attempts := 0
+	server := newTestServer(t, func(w http.ResponseWriter, req *http.Request, next http.Handler) {
+		attempts++
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	// Test case: retries give up after the configured number
+	c := newTestClient(server, WithRetries(2, time.Millisecond, time.Millisecond))
+	_, err := c.GetAccount(context.Background(), "acc")
+	if !errors.Is(err, ErrServer) || attempts != 3 {
+		t.Errorf("Expected ErrServer after 3 attempts, got %v after %d", err, attempts)
+	}
+
+	// Test case: a cancelled context ends the retries
+	attempts = 0
+	c = newTestClient(server)
+	ctx, cancel := context.WithCancel(context.Background())
+	c.sleep = func(ctx context.Context, d time.Duration) error {
+		cancel()
+		return ctx.Err()
+	}
+	_, err = c.GetAccount(ctx, "acc")
+	if !errors.Is(err, context.Canceled) || attempts != 1 {
+		t.Errorf("Expected the cancellation after 1 attempt, got %v after %d", err, attempts)
+	}
+
+	// Test case: the backoff grows up to its maximum, and honours Retry-After
+	c = New(server.URL, WithRetries(5, 100*time.Millisecond, time.Second))
+	if wait := c.backoff(0, 0); wait < 50*time.Millisecond || wait > 100*time.Millisecond {
+		t.Errorf("Expected a first wait between 50ms and 100ms, got %v", wait)
+	}
+	if wait := c.backoff(10, 0); wait < 500*time.Millisecond || wait > time.Second {
+		t.Errorf("Expected a wait capped at 1s, got %v", wait)
+	}
+	if wait := c.backoff(0, 500*time.Millisecond); wait != 500*time.Millisecond {
+		t.Errorf("Expected the Retry-After of 500ms, got %v", wait)
+	}
+}
@@ -0,0 +1,132 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Error codes the service reports in error_code
+const (
+	CodeLimitPerTransferMax = "LIMIT_PER_TRANSFER_MAX"
+	CodeLimitDailyAmount    = "LIMIT_DAILY_AMOUNT"
+	CodeLimitMonthlyAmount  = "LIMIT_MONTHLY_AMOUNT"
+	CodeLimitDailyCount     = "LIMIT_DAILY_COUNT"
+	CodeLimitMonthlyCount   = "LIMIT_MONTHLY_COUNT"
+
+	CodeInsufficientFunds = "INSUFFICIENT_FUNDS"
+	CodeAccountNotFound   = "ACCOUNT_NOT_FOUND"
+	CodeAccountFrozen     = "ACCOUNT_FROZEN"
+	CodeTransferDenied    = "TRANSFER_DENIED"
+	CodeTransferBlocked   = "TRANSFER_BLOCKED"
+
+	CodeIdempotencyKeyInUse  = "IDEMPOTENCY_KEY_IN_USE"
+	CodeIdempotencyKeyReused = "IDEMPOTENCY_KEY_REUSED"
+)
+
+// limitCodePrefix starts the codes of every transfer limit
+const limitCodePrefix = "LIMIT_"
+
+// Sentinel errors an *Error matches with errors.Is, by its status or code
+var (
+	ErrBadRequest   = errors.New("transfer service: bad request")
+	ErrUnauthorized = errors.New("transfer service: authentication required")
+	ErrForbidden    = errors.New("transfer service: forbidden")
+	ErrNotFound     = errors.New("transfer service: not found")
+	ErrConflict     = errors.New("transfer service: conflict")
+	ErrServer       = errors.New("transfer service: server error")
+
+	// ErrTransferRefused matches every transfer the service refused, whatever the reason
+	ErrTransferRefused   = errors.New("transfer service: transfer refused")
+	ErrLimitExceeded     = errors.New("transfer service: transfer limit exceeded")
+	ErrInsufficientFunds = errors.New("transfer service: insufficient funds")
+	ErrAccountFrozen     = errors.New("transfer service: account frozen")
+	ErrTransferDenied    = errors.New("transfer service: transfer denied by risk screening")
+	ErrTransferBlocked   = errors.New("transfer service: transfer blocked by watchlist screening")
+
+	ErrIdempotencyKeyInUse  = errors.New("transfer service: idempotency key in use")
+	ErrIdempotencyKeyReused = errors.New("transfer service: idempotency key reused for a different request")
+)
+
+// Error is a request the service refused or failed. Match it against the sentinel errors with errors.Is,
+// or read its fields with errors.As.
+type Error struct {
+	StatusCode int
+	// Code is the error_code the service reported, if any
+	Code    string
+	Message string
+	// Transfer is the record of a refused transfer the service kept, such as a denied one
+	Transfer *Transfer
+	// Remaining is the allowance left when a transfer limit was hit
+	Remaining *Allowance
+}
+
+func (e *Error) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("transfer service: %d %s: %s", e.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("transfer service: %d: %s", e.StatusCode, e.Message)
+}
+
+// Is matches the sentinel errors of the error's code, or of its status when the code has none
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrLimitExceeded:
+		return strings.HasPrefix(e.Code, limitCodePrefix)
+	case ErrInsufficientFunds:
+		return e.Code == CodeInsufficientFunds
+	case ErrAccountFrozen:
+		return e.Code == CodeAccountFrozen
+	case ErrTransferDenied:
+		return e.Code == CodeTransferDenied
+	case ErrTransferBlocked:
+		return e.Code == CodeTransferBlocked
+	case ErrIdempotencyKeyInUse:
+		return e.Code == CodeIdempotencyKeyInUse
+	case ErrIdempotencyKeyReused:
+		return e.Code == CodeIdempotencyKeyReused
+	case ErrTransferRefused:
+		return e.StatusCode == http.StatusUnprocessableEntity && e.Code != CodeIdempotencyKeyReused ||
+			e.Code == CodeInsufficientFunds
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound || e.Code == CodeAccountNotFound
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// errorBody is every shape of error the service answers with, and the body of transfer responses
+type errorBody struct {
+	Error     string     `json:"error"`
+	Message   string     `json:"message"`
+	ErrorCode string     `json:"error_code"`
+	Transfer  *Transfer  `json:"transfer"`
+	Remaining *Allowance `json:"remaining"`
+}
+
+func (b *errorBody) toError(statusCode int) *Error {
+	message := b.Message
+	if message == "" {
+		message = b.Error
+	}
+	if message == "" {
+		message = http.StatusText(statusCode)
+	}
+	return &Error{
+		StatusCode: statusCode,
+		Code:       b.ErrorCode,
+		Message:    message,
+		Transfer:   b.Transfer,
+		Remaining:  b.Remaining,
+	}
+}
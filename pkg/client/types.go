@@ -0,0 +1,153 @@
+package client
+
+import "time"
+
+// Transfer statuses
+const (
+	StatusCompleted       = "completed"
+	StatusPendingReview   = "pending_review"
+	StatusPendingApproval = "pending_approval"
+	StatusDenied          = "denied"
+	StatusBlocked         = "blocked"
+	StatusRejected        = "rejected"
+	StatusExpired         = "expired"
+	StatusFailed          = "failed"
+)
+
+// CreateAccountRequest opens an account; empty AccountType and Tier take the service's defaults
+type CreateAccountRequest struct {
+	AccountId      string  `json:"account_id"`
+	HolderName     string  `json:"holder_name,omitempty"`
+	OwnerId        string  `json:"owner_id,omitempty"`
+	InitialBalance float64 `json:"initial_balance"`
+	AccountType    string  `json:"account_type,omitempty"`
+	RatePlanId     string  `json:"rate_plan_id,omitempty"`
+	Tier           string  `json:"tier,omitempty"`
+
+	// IdempotencyKey is sent with every attempt of the request; one is generated when it is empty
+	IdempotencyKey string `json:"-"`
+}
+
+// TransferRequest moves Amount from the source account to the destination account
+type TransferRequest struct {
+	SourceAccountId      string  `json:"source_account_id"`
+	DestinationAccountId string  `json:"destination_account_id"`
+	Amount               float64 `json:"amount"`
+
+	// IdempotencyKey is sent with every attempt of the request; one is generated when it is empty.
+	// Callers that persist it can retry the transfer safely after a restart.
+	IdempotencyKey string `json:"-"`
+}
+
+// FreezeAccountRequest freezes or unfreezes an account; frozen accounts can neither send nor receive transfers
+type FreezeAccountRequest struct {
+	Frozen bool   `json:"frozen"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ListAccountsOptions select a page of accounts; a zero Limit asks for the service's default page size
+type ListAccountsOptions struct {
+	// After is the NextAfter of the previous page, or empty for the first page
+	After string
+	Limit int
+}
+
+type Account struct {
+	AccountId   string  `json:"account_id"`
+	HolderName  string  `json:"holder_name,omitempty"`
+	OwnerId     string  `json:"owner_id,omitempty"`
+	Balance     float64 `json:"balance"`
+	AccountType string  `json:"account_type"`
+	RatePlanId  string  `json:"rate_plan_id,omitempty"`
+	Tier        string  `json:"tier"`
+
+	OverdraftLimit        float64 `json:"overdraft_limit"`
+	OverdraftLimitVersion int     `json:"overdraft_limit_version"`
+	OverdraftUsed         float64 `json:"overdraft_used"`
+	AvailableBalance      float64 `json:"available_balance"`
+	Frozen                bool    `json:"frozen,omitempty"`
+
+	// Activity is only reported when the service reads accounts from its read model
+	Activity *Activity `json:"activity,omitempty"`
+}
+
+// Activity summarizes the recent transfers of an account
+type Activity struct {
+	LastTransfer *LastTransfer `json:"last_transfer,omitempty"`
+	Inflow30d    float64       `json:"inflow_30d"`
+	Outflow30d   float64       `json:"outflow_30d"`
+	AsOf         time.Time     `json:"as_of"`
+}
+
+type LastTransfer struct {
+	TransferId     string     `json:"transfer_id"`
+	Direction      string     `json:"direction"`
+	CounterpartyId string     `json:"counterparty_id"`
+	Amount         float64    `json:"amount"`
+	ExecutedAt     *time.Time `json:"executed_at"`
+}
+
+// AccountPage is a page of accounts ordered by id. NextAfter lists the next page, and is empty on the last one.
+type AccountPage struct {
+	Accounts  []Account `json:"accounts"`
+	NextAfter string    `json:"next_after,omitempty"`
+}
+
+// TransferResult is a transfer the service executed or held for review or approval
+type TransferResult struct {
+	Message  string
+	Transfer *Transfer
+}
+
+// Pending reports whether the transfer waits for a review or an operator's approval
+func (r *TransferResult) Pending() bool {
+	return r.Transfer.Status == StatusPendingReview || r.Transfer.Status == StatusPendingApproval
+}
+
+type Transfer struct {
+	TransferId           string        `json:"transfer_id"`
+	SourceAccountId      string        `json:"source_account_id"`
+	DestinationAccountId string        `json:"destination_account_id"`
+	Amount               float64       `json:"amount"`
+	Fee                  float64       `json:"fee"`
+	FeeBreakdown         *FeeBreakdown `json:"fee_breakdown,omitempty"`
+	Type                 string        `json:"type"`
+	Status               string        `json:"status"`
+	RiskDecision         *RiskDecision `json:"risk_decision,omitempty"`
+	FailureReason        string        `json:"failure_reason,omitempty"`
+	InitiatedBy          string        `json:"initiated_by,omitempty"`
+	ReviewedBy           string        `json:"reviewed_by,omitempty"`
+	ReviewedAt           *time.Time    `json:"reviewed_at,omitempty"`
+	ExpiresAt            *time.Time    `json:"expires_at,omitempty"`
+	ExecutedAt           *time.Time    `json:"executed_at,omitempty"`
+	CreatedAt            *time.Time    `json:"created_at,omitempty"`
+}
+
+// FeeBreakdown shows how the fee of a transfer was computed
+type FeeBreakdown struct {
+	AccountType string  `json:"account_type"`
+	Kind        string  `json:"kind"`
+	Tier        int     `json:"tier,omitempty"`
+	Flat        float64 `json:"flat"`
+	Rate        float64 `json:"rate"`
+	Variable    float64 `json:"variable"`
+	CapApplied  string  `json:"cap_applied,omitempty"`
+	Total       float64 `json:"total"`
+}
+
+// RiskDecision is the outcome of screening a transfer for risk
+type RiskDecision struct {
+	Outcome        string    `json:"outcome"`
+	TriggeredRules []string  `json:"triggered_rules,omitempty"`
+	EvaluatedAt    time.Time `json:"evaluated_at"`
+}
+
+// Allowance is what an account may still transfer out; nil fields are not limited
+type Allowance struct {
+	Tier           string   `json:"tier"`
+	PerTransferMax *float64 `json:"per_transfer_max,omitempty"`
+	DailyAmount    *float64 `json:"daily_amount,omitempty"`
+	MonthlyAmount  *float64 `json:"monthly_amount,omitempty"`
+	DailyCount     *int     `json:"daily_count,omitempty"`
+	MonthlyCount   *int     `json:"monthly_count,omitempty"`
+}
@@ -0,0 +1,1072 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: transfer/v1/transfer.proto
+
+package transferv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetAccountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAccountRequest) Reset() {
+	*x = GetAccountRequest{}
+	mi := &file_transfer_v1_transfer_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAccountRequest) ProtoMessage() {}
+
+func (x *GetAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_transfer_v1_transfer_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAccountRequest.ProtoReflect.Descriptor instead.
+func (*GetAccountRequest) Descriptor() ([]byte, []int) {
+	return file_transfer_v1_transfer_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetAccountRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+type Account struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	AccountId             string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	HolderName            string                 `protobuf:"bytes,2,opt,name=holder_name,json=holderName,proto3" json:"holder_name,omitempty"`
+	Balance               float64                `protobuf:"fixed64,3,opt,name=balance,proto3" json:"balance,omitempty"`
+	AccountType           string                 `protobuf:"bytes,4,opt,name=account_type,json=accountType,proto3" json:"account_type,omitempty"`
+	RatePlanId            string                 `protobuf:"bytes,5,opt,name=rate_plan_id,json=ratePlanId,proto3" json:"rate_plan_id,omitempty"`
+	Tier                  string                 `protobuf:"bytes,6,opt,name=tier,proto3" json:"tier,omitempty"`
+	OverdraftLimit        float64                `protobuf:"fixed64,7,opt,name=overdraft_limit,json=overdraftLimit,proto3" json:"overdraft_limit,omitempty"`
+	OverdraftLimitVersion int32                  `protobuf:"varint,8,opt,name=overdraft_limit_version,json=overdraftLimitVersion,proto3" json:"overdraft_limit_version,omitempty"`
+	OverdraftUsed         float64                `protobuf:"fixed64,9,opt,name=overdraft_used,json=overdraftUsed,proto3" json:"overdraft_used,omitempty"`
+	AvailableBalance      float64                `protobuf:"fixed64,10,opt,name=available_balance,json=availableBalance,proto3" json:"available_balance,omitempty"`
+	Frozen                bool                   `protobuf:"varint,11,opt,name=frozen,proto3" json:"frozen,omitempty"`
+	// activity is only reported when accounts are read from the read model
+	Activity      *Activity `protobuf:"bytes,12,opt,name=activity,proto3" json:"activity,omitempty"`
+	OwnerId       string    `protobuf:"bytes,13,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Account) Reset() {
+	*x = Account{}
+	mi := &file_transfer_v1_transfer_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Account) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Account) ProtoMessage() {}
+
+func (x *Account) ProtoReflect() protoreflect.Message {
+	mi := &file_transfer_v1_transfer_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Account.ProtoReflect.Descriptor instead.
+func (*Account) Descriptor() ([]byte, []int) {
+	return file_transfer_v1_transfer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Account) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *Account) GetHolderName() string {
+	if x != nil {
+		return x.HolderName
+	}
+	return ""
+}
+
+func (x *Account) GetBalance() float64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+func (x *Account) GetAccountType() string {
+	if x != nil {
+		return x.AccountType
+	}
+	return ""
+}
+
+func (x *Account) GetRatePlanId() string {
+	if x != nil {
+		return x.RatePlanId
+	}
+	return ""
+}
+
+func (x *Account) GetTier() string {
+	if x != nil {
+		return x.Tier
+	}
+	return ""
+}
+
+func (x *Account) GetOverdraftLimit() float64 {
+	if x != nil {
+		return x.OverdraftLimit
+	}
+	return 0
+}
+
+func (x *Account) GetOverdraftLimitVersion() int32 {
+	if x != nil {
+		return x.OverdraftLimitVersion
+	}
+	return 0
+}
+
+func (x *Account) GetOverdraftUsed() float64 {
+	if x != nil {
+		return x.OverdraftUsed
+	}
+	return 0
+}
+
+func (x *Account) GetAvailableBalance() float64 {
+	if x != nil {
+		return x.AvailableBalance
+	}
+	return 0
+}
+
+func (x *Account) GetFrozen() bool {
+	if x != nil {
+		return x.Frozen
+	}
+	return false
+}
+
+func (x *Account) GetActivity() *Activity {
+	if x != nil {
+		return x.Activity
+	}
+	return nil
+}
+
+func (x *Account) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+// Activity summarizes the recent transfers of an account
+type Activity struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	LastTransfer *LastTransfer          `protobuf:"bytes,1,opt,name=last_transfer,json=lastTransfer,proto3" json:"last_transfer,omitempty"`
+	Inflow_30D   float64                `protobuf:"fixed64,2,opt,name=inflow_30d,json=inflow30d,proto3" json:"inflow_30d,omitempty"`
+	Outflow_30D  float64                `protobuf:"fixed64,3,opt,name=outflow_30d,json=outflow30d,proto3" json:"outflow_30d,omitempty"`
+	// as_of is when the read model last applied a change to the account
+	AsOf          *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Activity) Reset() {
+	*x = Activity{}
+	mi := &file_transfer_v1_transfer_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Activity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Activity) ProtoMessage() {}
+
+func (x *Activity) ProtoReflect() protoreflect.Message {
+	mi := &file_transfer_v1_transfer_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Activity.ProtoReflect.Descriptor instead.
+func (*Activity) Descriptor() ([]byte, []int) {
+	return file_transfer_v1_transfer_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Activity) GetLastTransfer() *LastTransfer {
+	if x != nil {
+		return x.LastTransfer
+	}
+	return nil
+}
+
+func (x *Activity) GetInflow_30D() float64 {
+	if x != nil {
+		return x.Inflow_30D
+	}
+	return 0
+}
+
+func (x *Activity) GetOutflow_30D() float64 {
+	if x != nil {
+		return x.Outflow_30D
+	}
+	return 0
+}
+
+func (x *Activity) GetAsOf() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AsOf
+	}
+	return nil
+}
+
+type LastTransfer struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	TransferId     string                 `protobuf:"bytes,1,opt,name=transfer_id,json=transferId,proto3" json:"transfer_id,omitempty"`
+	Direction      string                 `protobuf:"bytes,2,opt,name=direction,proto3" json:"direction,omitempty"`
+	CounterpartyId string                 `protobuf:"bytes,3,opt,name=counterparty_id,json=counterpartyId,proto3" json:"counterparty_id,omitempty"`
+	Amount         float64                `protobuf:"fixed64,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	ExecutedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=executed_at,json=executedAt,proto3" json:"executed_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *LastTransfer) Reset() {
+	*x = LastTransfer{}
+	mi := &file_transfer_v1_transfer_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LastTransfer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LastTransfer) ProtoMessage() {}
+
+func (x *LastTransfer) ProtoReflect() protoreflect.Message {
+	mi := &file_transfer_v1_transfer_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LastTransfer.ProtoReflect.Descriptor instead.
+func (*LastTransfer) Descriptor() ([]byte, []int) {
+	return file_transfer_v1_transfer_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *LastTransfer) GetTransferId() string {
+	if x != nil {
+		return x.TransferId
+	}
+	return ""
+}
+
+func (x *LastTransfer) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *LastTransfer) GetCounterpartyId() string {
+	if x != nil {
+		return x.CounterpartyId
+	}
+	return ""
+}
+
+func (x *LastTransfer) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *LastTransfer) GetExecutedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExecutedAt
+	}
+	return nil
+}
+
+type CreateAccountRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	AccountId      string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	HolderName     string                 `protobuf:"bytes,2,opt,name=holder_name,json=holderName,proto3" json:"holder_name,omitempty"`
+	InitialBalance float64                `protobuf:"fixed64,3,opt,name=initial_balance,json=initialBalance,proto3" json:"initial_balance,omitempty"`
+	AccountType    string                 `protobuf:"bytes,4,opt,name=account_type,json=accountType,proto3" json:"account_type,omitempty"`
+	RatePlanId     string                 `protobuf:"bytes,5,opt,name=rate_plan_id,json=ratePlanId,proto3" json:"rate_plan_id,omitempty"`
+	Tier           string                 `protobuf:"bytes,6,opt,name=tier,proto3" json:"tier,omitempty"`
+	// owner_id defaults to the subject of the caller creating the account, and is required from admins
+	OwnerId       string `protobuf:"bytes,7,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAccountRequest) Reset() {
+	*x = CreateAccountRequest{}
+	mi := &file_transfer_v1_transfer_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAccountRequest) ProtoMessage() {}
+
+func (x *CreateAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_transfer_v1_transfer_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAccountRequest.ProtoReflect.Descriptor instead.
+func (*CreateAccountRequest) Descriptor() ([]byte, []int) {
+	return file_transfer_v1_transfer_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateAccountRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *CreateAccountRequest) GetHolderName() string {
+	if x != nil {
+		return x.HolderName
+	}
+	return ""
+}
+
+func (x *CreateAccountRequest) GetInitialBalance() float64 {
+	if x != nil {
+		return x.InitialBalance
+	}
+	return 0
+}
+
+func (x *CreateAccountRequest) GetAccountType() string {
+	if x != nil {
+		return x.AccountType
+	}
+	return ""
+}
+
+func (x *CreateAccountRequest) GetRatePlanId() string {
+	if x != nil {
+		return x.RatePlanId
+	}
+	return ""
+}
+
+func (x *CreateAccountRequest) GetTier() string {
+	if x != nil {
+		return x.Tier
+	}
+	return ""
+}
+
+func (x *CreateAccountRequest) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+type CreateAccountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAccountResponse) Reset() {
+	*x = CreateAccountResponse{}
+	mi := &file_transfer_v1_transfer_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAccountResponse) ProtoMessage() {}
+
+func (x *CreateAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_transfer_v1_transfer_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAccountResponse.ProtoReflect.Descriptor instead.
+func (*CreateAccountResponse) Descriptor() ([]byte, []int) {
+	return file_transfer_v1_transfer_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateAccountResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type TransferRequest struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	SourceAccountId      string                 `protobuf:"bytes,1,opt,name=source_account_id,json=sourceAccountId,proto3" json:"source_account_id,omitempty"`
+	DestinationAccountId string                 `protobuf:"bytes,2,opt,name=destination_account_id,json=destinationAccountId,proto3" json:"destination_account_id,omitempty"`
+	Amount               float64                `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *TransferRequest) Reset() {
+	*x = TransferRequest{}
+	mi := &file_transfer_v1_transfer_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferRequest) ProtoMessage() {}
+
+func (x *TransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_transfer_v1_transfer_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferRequest.ProtoReflect.Descriptor instead.
+func (*TransferRequest) Descriptor() ([]byte, []int) {
+	return file_transfer_v1_transfer_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TransferRequest) GetSourceAccountId() string {
+	if x != nil {
+		return x.SourceAccountId
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetDestinationAccountId() string {
+	if x != nil {
+		return x.DestinationAccountId
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type TransferResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Message string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	// transfer is missing when the transfer was refused before it was recorded
+	Transfer      *Transfer `protobuf:"bytes,2,opt,name=transfer,proto3" json:"transfer,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferResponse) Reset() {
+	*x = TransferResponse{}
+	mi := &file_transfer_v1_transfer_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferResponse) ProtoMessage() {}
+
+func (x *TransferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_transfer_v1_transfer_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferResponse.ProtoReflect.Descriptor instead.
+func (*TransferResponse) Descriptor() ([]byte, []int) {
+	return file_transfer_v1_transfer_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *TransferResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *TransferResponse) GetTransfer() *Transfer {
+	if x != nil {
+		return x.Transfer
+	}
+	return nil
+}
+
+type Transfer struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	TransferId           string                 `protobuf:"bytes,1,opt,name=transfer_id,json=transferId,proto3" json:"transfer_id,omitempty"`
+	SourceAccountId      string                 `protobuf:"bytes,2,opt,name=source_account_id,json=sourceAccountId,proto3" json:"source_account_id,omitempty"`
+	DestinationAccountId string                 `protobuf:"bytes,3,opt,name=destination_account_id,json=destinationAccountId,proto3" json:"destination_account_id,omitempty"`
+	Amount               float64                `protobuf:"fixed64,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	Fee                  float64                `protobuf:"fixed64,5,opt,name=fee,proto3" json:"fee,omitempty"`
+	Type                 string                 `protobuf:"bytes,6,opt,name=type,proto3" json:"type,omitempty"`
+	Status               string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	FailureReason        string                 `protobuf:"bytes,8,opt,name=failure_reason,json=failureReason,proto3" json:"failure_reason,omitempty"`
+	InitiatedBy          string                 `protobuf:"bytes,9,opt,name=initiated_by,json=initiatedBy,proto3" json:"initiated_by,omitempty"`
+	ReviewedBy           string                 `protobuf:"bytes,10,opt,name=reviewed_by,json=reviewedBy,proto3" json:"reviewed_by,omitempty"`
+	ReviewedAt           *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=reviewed_at,json=reviewedAt,proto3" json:"reviewed_at,omitempty"`
+	ExpiresAt            *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	ExecutedAt           *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=executed_at,json=executedAt,proto3" json:"executed_at,omitempty"`
+	CreatedAt            *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *Transfer) Reset() {
+	*x = Transfer{}
+	mi := &file_transfer_v1_transfer_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Transfer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transfer) ProtoMessage() {}
+
+func (x *Transfer) ProtoReflect() protoreflect.Message {
+	mi := &file_transfer_v1_transfer_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transfer.ProtoReflect.Descriptor instead.
+func (*Transfer) Descriptor() ([]byte, []int) {
+	return file_transfer_v1_transfer_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Transfer) GetTransferId() string {
+	if x != nil {
+		return x.TransferId
+	}
+	return ""
+}
+
+func (x *Transfer) GetSourceAccountId() string {
+	if x != nil {
+		return x.SourceAccountId
+	}
+	return ""
+}
+
+func (x *Transfer) GetDestinationAccountId() string {
+	if x != nil {
+		return x.DestinationAccountId
+	}
+	return ""
+}
+
+func (x *Transfer) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Transfer) GetFee() float64 {
+	if x != nil {
+		return x.Fee
+	}
+	return 0
+}
+
+func (x *Transfer) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Transfer) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Transfer) GetFailureReason() string {
+	if x != nil {
+		return x.FailureReason
+	}
+	return ""
+}
+
+func (x *Transfer) GetInitiatedBy() string {
+	if x != nil {
+		return x.InitiatedBy
+	}
+	return ""
+}
+
+func (x *Transfer) GetReviewedBy() string {
+	if x != nil {
+		return x.ReviewedBy
+	}
+	return ""
+}
+
+func (x *Transfer) GetReviewedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReviewedAt
+	}
+	return nil
+}
+
+func (x *Transfer) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *Transfer) GetExecutedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExecutedAt
+	}
+	return nil
+}
+
+func (x *Transfer) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type StreamBalancesRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	AccountId string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	// last_event_id resumes a stream after the update with this event id, when it is set
+	LastEventId   uint64 `protobuf:"varint,2,opt,name=last_event_id,json=lastEventId,proto3" json:"last_event_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamBalancesRequest) Reset() {
+	*x = StreamBalancesRequest{}
+	mi := &file_transfer_v1_transfer_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamBalancesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamBalancesRequest) ProtoMessage() {}
+
+func (x *StreamBalancesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_transfer_v1_transfer_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamBalancesRequest.ProtoReflect.Descriptor instead.
+func (*StreamBalancesRequest) Descriptor() ([]byte, []int) {
+	return file_transfer_v1_transfer_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *StreamBalancesRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *StreamBalancesRequest) GetLastEventId() uint64 {
+	if x != nil {
+		return x.LastEventId
+	}
+	return 0
+}
+
+type BalanceUpdate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// event_id is the last_event_id to resume the stream from
+	EventId          uint64  `protobuf:"varint,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	AccountId        string  `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Balance          float64 `protobuf:"fixed64,3,opt,name=balance,proto3" json:"balance,omitempty"`
+	AvailableBalance float64 `protobuf:"fixed64,4,opt,name=available_balance,json=availableBalance,proto3" json:"available_balance,omitempty"`
+	// transfer_id is the transfer that changed the balance, if any
+	TransferId    string                 `protobuf:"bytes,5,opt,name=transfer_id,json=transferId,proto3" json:"transfer_id,omitempty"`
+	ChangedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BalanceUpdate) Reset() {
+	*x = BalanceUpdate{}
+	mi := &file_transfer_v1_transfer_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BalanceUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BalanceUpdate) ProtoMessage() {}
+
+func (x *BalanceUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_transfer_v1_transfer_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BalanceUpdate.ProtoReflect.Descriptor instead.
+func (*BalanceUpdate) Descriptor() ([]byte, []int) {
+	return file_transfer_v1_transfer_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BalanceUpdate) GetEventId() uint64 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *BalanceUpdate) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *BalanceUpdate) GetBalance() float64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+func (x *BalanceUpdate) GetAvailableBalance() float64 {
+	if x != nil {
+		return x.AvailableBalance
+	}
+	return 0
+}
+
+func (x *BalanceUpdate) GetTransferId() string {
+	if x != nil {
+		return x.TransferId
+	}
+	return ""
+}
+
+func (x *BalanceUpdate) GetChangedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return nil
+}
+
+var File_transfer_v1_transfer_proto protoreflect.FileDescriptor
+
+const file_transfer_v1_transfer_proto_rawDesc = "" +
+	"\n" +
+	"\x1atransfer/v1/transfer.proto\x12\vtransfer.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"2\n" +
+	"\x11GetAccountRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\"\xd7\x03\n" +
+	"\aAccount\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x1f\n" +
+	"\vholder_name\x18\x02 \x01(\tR\n" +
+	"holderName\x12\x18\n" +
+	"\abalance\x18\x03 \x01(\x01R\abalance\x12!\n" +
+	"\faccount_type\x18\x04 \x01(\tR\vaccountType\x12 \n" +
+	"\frate_plan_id\x18\x05 \x01(\tR\n" +
+	"ratePlanId\x12\x12\n" +
+	"\x04tier\x18\x06 \x01(\tR\x04tier\x12'\n" +
+	"\x0foverdraft_limit\x18\a \x01(\x01R\x0eoverdraftLimit\x126\n" +
+	"\x17overdraft_limit_version\x18\b \x01(\x05R\x15overdraftLimitVersion\x12%\n" +
+	"\x0eoverdraft_used\x18\t \x01(\x01R\roverdraftUsed\x12+\n" +
+	"\x11available_balance\x18\n" +
+	" \x01(\x01R\x10availableBalance\x12\x16\n" +
+	"\x06frozen\x18\v \x01(\bR\x06frozen\x121\n" +
+	"\bactivity\x18\f \x01(\v2\x15.transfer.v1.ActivityR\bactivity\x12\x19\n" +
+	"\bowner_id\x18\r \x01(\tR\aownerId\"\xbb\x01\n" +
+	"\bActivity\x12>\n" +
+	"\rlast_transfer\x18\x01 \x01(\v2\x19.transfer.v1.LastTransferR\flastTransfer\x12\x1d\n" +
+	"\n" +
+	"inflow_30d\x18\x02 \x01(\x01R\tinflow30d\x12\x1f\n" +
+	"\voutflow_30d\x18\x03 \x01(\x01R\n" +
+	"outflow30d\x12/\n" +
+	"\x05as_of\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x04asOf\"\xcb\x01\n" +
+	"\fLastTransfer\x12\x1f\n" +
+	"\vtransfer_id\x18\x01 \x01(\tR\n" +
+	"transferId\x12\x1c\n" +
+	"\tdirection\x18\x02 \x01(\tR\tdirection\x12'\n" +
+	"\x0fcounterparty_id\x18\x03 \x01(\tR\x0ecounterpartyId\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x01R\x06amount\x12;\n" +
+	"\vexecuted_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"executedAt\"\xf3\x01\n" +
+	"\x14CreateAccountRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x1f\n" +
+	"\vholder_name\x18\x02 \x01(\tR\n" +
+	"holderName\x12'\n" +
+	"\x0finitial_balance\x18\x03 \x01(\x01R\x0einitialBalance\x12!\n" +
+	"\faccount_type\x18\x04 \x01(\tR\vaccountType\x12 \n" +
+	"\frate_plan_id\x18\x05 \x01(\tR\n" +
+	"ratePlanId\x12\x12\n" +
+	"\x04tier\x18\x06 \x01(\tR\x04tier\x12\x19\n" +
+	"\bowner_id\x18\a \x01(\tR\aownerId\"1\n" +
+	"\x15CreateAccountResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"\x8b\x01\n" +
+	"\x0fTransferRequest\x12*\n" +
+	"\x11source_account_id\x18\x01 \x01(\tR\x0fsourceAccountId\x124\n" +
+	"\x16destination_account_id\x18\x02 \x01(\tR\x14destinationAccountId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x01R\x06amount\"_\n" +
+	"\x10TransferResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x121\n" +
+	"\btransfer\x18\x02 \x01(\v2\x15.transfer.v1.TransferR\btransfer\"\xbe\x04\n" +
+	"\bTransfer\x12\x1f\n" +
+	"\vtransfer_id\x18\x01 \x01(\tR\n" +
+	"transferId\x12*\n" +
+	"\x11source_account_id\x18\x02 \x01(\tR\x0fsourceAccountId\x124\n" +
+	"\x16destination_account_id\x18\x03 \x01(\tR\x14destinationAccountId\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x01R\x06amount\x12\x10\n" +
+	"\x03fee\x18\x05 \x01(\x01R\x03fee\x12\x12\n" +
+	"\x04type\x18\x06 \x01(\tR\x04type\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12%\n" +
+	"\x0efailure_reason\x18\b \x01(\tR\rfailureReason\x12!\n" +
+	"\finitiated_by\x18\t \x01(\tR\vinitiatedBy\x12\x1f\n" +
+	"\vreviewed_by\x18\n" +
+	" \x01(\tR\n" +
+	"reviewedBy\x12;\n" +
+	"\vreviewed_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"reviewedAt\x129\n" +
+	"\n" +
+	"expires_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12;\n" +
+	"\vexecuted_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"executedAt\x129\n" +
+	"\n" +
+	"created_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"Z\n" +
+	"\x15StreamBalancesRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\"\n" +
+	"\rlast_event_id\x18\x02 \x01(\x04R\vlastEventId\"\xec\x01\n" +
+	"\rBalanceUpdate\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\x04R\aeventId\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x02 \x01(\tR\taccountId\x12\x18\n" +
+	"\abalance\x18\x03 \x01(\x01R\abalance\x12+\n" +
+	"\x11available_balance\x18\x04 \x01(\x01R\x10availableBalance\x12\x1f\n" +
+	"\vtransfer_id\x18\x05 \x01(\tR\n" +
+	"transferId\x129\n" +
+	"\n" +
+	"changed_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tchangedAt2\xc9\x02\n" +
+	"\x0eAccountService\x12B\n" +
+	"\n" +
+	"GetAccount\x12\x1e.transfer.v1.GetAccountRequest\x1a\x14.transfer.v1.Account\x12V\n" +
+	"\rCreateAccount\x12!.transfer.v1.CreateAccountRequest\x1a\".transfer.v1.CreateAccountResponse\x12G\n" +
+	"\bTransfer\x12\x1c.transfer.v1.TransferRequest\x1a\x1d.transfer.v1.TransferResponse\x12R\n" +
+	"\x0eStreamBalances\x12\".transfer.v1.StreamBalancesRequest\x1a\x1a.transfer.v1.BalanceUpdate0\x01B=Z;internal-transfer-microservice/proto/transfer/v1;transferv1b\x06proto3"
+
+var (
+	file_transfer_v1_transfer_proto_rawDescOnce sync.Once
+	file_transfer_v1_transfer_proto_rawDescData []byte
+)
+
+func file_transfer_v1_transfer_proto_rawDescGZIP() []byte {
+	file_transfer_v1_transfer_proto_rawDescOnce.Do(func() {
+		file_transfer_v1_transfer_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_transfer_v1_transfer_proto_rawDesc), len(file_transfer_v1_transfer_proto_rawDesc)))
+	})
+	return file_transfer_v1_transfer_proto_rawDescData
+}
+
+var file_transfer_v1_transfer_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_transfer_v1_transfer_proto_goTypes = []any{
+	(*GetAccountRequest)(nil),     // 0: transfer.v1.GetAccountRequest
+	(*Account)(nil),               // 1: transfer.v1.Account
+	(*Activity)(nil),              // 2: transfer.v1.Activity
+	(*LastTransfer)(nil),          // 3: transfer.v1.LastTransfer
+	(*CreateAccountRequest)(nil),  // 4: transfer.v1.CreateAccountRequest
+	(*CreateAccountResponse)(nil), // 5: transfer.v1.CreateAccountResponse
+	(*TransferRequest)(nil),       // 6: transfer.v1.TransferRequest
+	(*TransferResponse)(nil),      // 7: transfer.v1.TransferResponse
+	(*Transfer)(nil),              // 8: transfer.v1.Transfer
+	(*StreamBalancesRequest)(nil), // 9: transfer.v1.StreamBalancesRequest
+	(*BalanceUpdate)(nil),         // 10: transfer.v1.BalanceUpdate
+	(*timestamppb.Timestamp)(nil), // 11: google.protobuf.Timestamp
+}
+var file_transfer_v1_transfer_proto_depIdxs = []int32{
+	2,  // 0: transfer.v1.Account.activity:type_name -> transfer.v1.Activity
+	3,  // 1: transfer.v1.Activity.last_transfer:type_name -> transfer.v1.LastTransfer
+	11, // 2: transfer.v1.Activity.as_of:type_name -> google.protobuf.Timestamp
+	11, // 3: transfer.v1.LastTransfer.executed_at:type_name -> google.protobuf.Timestamp
+	8,  // 4: transfer.v1.TransferResponse.transfer:type_name -> transfer.v1.Transfer
+	11, // 5: transfer.v1.Transfer.reviewed_at:type_name -> google.protobuf.Timestamp
+	11, // 6: transfer.v1.Transfer.expires_at:type_name -> google.protobuf.Timestamp
+	11, // 7: transfer.v1.Transfer.executed_at:type_name -> google.protobuf.Timestamp
+	11, // 8: transfer.v1.Transfer.created_at:type_name -> google.protobuf.Timestamp
+	11, // 9: transfer.v1.BalanceUpdate.changed_at:type_name -> google.protobuf.Timestamp
+	0,  // 10: transfer.v1.AccountService.GetAccount:input_type -> transfer.v1.GetAccountRequest
+	4,  // 11: transfer.v1.AccountService.CreateAccount:input_type -> transfer.v1.CreateAccountRequest
+	6,  // 12: transfer.v1.AccountService.Transfer:input_type -> transfer.v1.TransferRequest
+	9,  // 13: transfer.v1.AccountService.StreamBalances:input_type -> transfer.v1.StreamBalancesRequest
+	1,  // 14: transfer.v1.AccountService.GetAccount:output_type -> transfer.v1.Account
+	5,  // 15: transfer.v1.AccountService.CreateAccount:output_type -> transfer.v1.CreateAccountResponse
+	7,  // 16: transfer.v1.AccountService.Transfer:output_type -> transfer.v1.TransferResponse
+	10, // 17: transfer.v1.AccountService.StreamBalances:output_type -> transfer.v1.BalanceUpdate
+	14, // [14:18] is the sub-list for method output_type
+	10, // [10:14] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_transfer_v1_transfer_proto_init() }
+func file_transfer_v1_transfer_proto_init() {
+	if File_transfer_v1_transfer_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_transfer_v1_transfer_proto_rawDesc), len(file_transfer_v1_transfer_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_transfer_v1_transfer_proto_goTypes,
+		DependencyIndexes: file_transfer_v1_transfer_proto_depIdxs,
+		MessageInfos:      file_transfer_v1_transfer_proto_msgTypes,
+	}.Build()
+	File_transfer_v1_transfer_proto = out.File
+	file_transfer_v1_transfer_proto_goTypes = nil
+	file_transfer_v1_transfer_proto_depIdxs = nil
+}